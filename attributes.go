@@ -17,6 +17,59 @@ const (
 	rtcpPacketsKey
 )
 
+type ecnAttributesKeyType int
+
+// ECNAttributesKey is set on outgoing RTP packets by interceptors asking the
+// transport to send them with an ECN codepoint. Its value is a rtcp.ECN, for
+// instance rtcp.ECNECT1 to mark the packets ECT(1) as L4S capable (RFC 9331).
+// On incoming RTP packets, it is set by the transport to the codepoint the
+// packet was received with.
+const ECNAttributesKey ecnAttributesKeyType = iota
+
+type retransmittedAttributesKeyType int
+
+// RetransmittedAttributesKey is set to true in the Attributes of every media
+// packet that was received as a retransmission on the RTX stream, by the rtx
+// ReceiverInterceptor. On the sending side, the NACK responder sets it on the
+// packets it resends.
+const RetransmittedAttributesKey retransmittedAttributesKeyType = iota
+
+type recoveredAttributesKeyType int
+
+// RecoveredAttributesKey is set to true in the Attributes of every media packet
+// that was recovered by the flexfec FecDecoderInterceptor instead of being
+// received.
+const RecoveredAttributesKey recoveredAttributesKeyType = iota
+
+type fecPacketsAttributesKeyType int
+
+// FECPacketsAttributesKey is set to the FECPackets of the protected stream in the
+// Attributes of every media packet returned by the flexfec FecDecoderInterceptor.
+const FECPacketsAttributesKey fecPacketsAttributesKeyType = iota
+
+// FECPackets counts the FEC packets received for a protected media stream.
+type FECPackets struct {
+	// Received is the number of FEC packets received.
+	Received uint64
+	// Discarded is the number of FEC packets which recovered no media packet
+	// when they were received, usually as all the packets they protect arrived.
+	Discarded uint64
+}
+
+type discardedPacketsAttributesKeyType int
+
+// DiscardedPacketsAttributesKey is set to the number of packets of the remote
+// stream the jitterbuffer ReceiverInterceptor dropped, as they arrived too late
+// to be played out, in the Attributes of every packet it returns. Its value is a
+// uint64.
+const DiscardedPacketsAttributesKey discardedPacketsAttributesKeyType = iota
+
+type sendDelayAttributesKeyType int
+
+// SendDelayAttributesKey is set to the time.Duration a packet waited in the
+// pacer queue in the Attributes of every packet the pacing Interceptor sends.
+const SendDelayAttributesKey sendDelayAttributesKeyType = iota
+
 var errInvalidType = errors.New("found value of invalid type in attributes map")
 
 // Attributes are a generic key/value store used by interceptors.
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import "time"
+
+// Ticker delivers ticks at intervals, as a *time.Ticker does.
+type Ticker interface {
+	// Ch returns the channel the ticks are delivered on.
+	Ch() <-chan time.Time
+	// Stop turns off the ticker.
+	Stop()
+}
+
+// Clock is the source of time of the interceptors. The interceptors driven by
+// time accept a Clock with their options, so that whole chains can run on a
+// simulated clock, such as the VirtualClock of package netem.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTicker returns a new Ticker ticking every d.
+	NewTicker(d time.Duration) Ticker
+}
+
+// SystemClock returns the Clock of the system time, used by the interceptors by default.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return &timeTicker{time.NewTicker(d)}
+}
+
+type timeTicker struct {
+	*time.Ticker
+}
+
+func (t *timeTicker) Ch() <-chan time.Time {
+	return t.C
+}
@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystemClock(t *testing.T) {
+	clock := SystemClock()
+
+	before := time.Now()
+	now := clock.Now()
+	assert.False(t, now.Before(before))
+	assert.False(t, time.Now().Before(now))
+
+	ticker := clock.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	select {
+	case tick := <-ticker.Ch():
+		assert.False(t, tick.Before(before))
+	case <-time.After(time.Second):
+		assert.Fail(t, "no tick")
+	}
+}
@@ -19,6 +19,7 @@ type Acknowledgment struct {
 	Departure      time.Time
 	Arrival        time.Time
 	ECN            rtcp.ECN
+	ProbeClusterID int // ID of the probe cluster the packet was sent in, 0 if none
 }
 
 func (a Acknowledgment) String() string {
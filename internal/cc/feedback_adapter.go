@@ -4,7 +4,6 @@
 package cc
 
 import (
-	"container/list"
 	"errors"
 	"sync"
 	"time"
@@ -19,6 +18,12 @@ import (
 // so we don't need to reparse.
 const TwccExtensionAttributesKey = iota
 
+type probeClusterAttributesKeyType int
+
+// ProbeClusterAttributesKey identifies the ID of the probe cluster an outgoing packet
+// was sent in, it is copied to the Acknowledgment of the packet.
+const ProbeClusterAttributesKey probeClusterAttributesKeyType = iota
+
 var (
 	errMissingTWCCExtension = errors.New("missing transport layer cc header extension")
 	errInvalidFeedback      = errors.New("invalid feedback")
@@ -26,64 +31,165 @@ var (
 
 // FeedbackAdapter converts incoming RTCP Packets (TWCC and RFC8888) into Acknowledgments.
 // Acknowledgments are the common format that Congestion Controllers in Pion understand.
+// The sent packets are recorded in an interceptor.SentPacketRegistry. It is the
+// registry set in the attributes of the first packet sent by the interceptor
+// numbering the packets, or a registry of the adapter if that packet has none.
 type FeedbackAdapter struct {
-	lock    sync.Mutex
-	history *feedbackHistory
+	lock     sync.RWMutex
+	registry *interceptor.SentPacketRegistry
 }
 
 // NewFeedbackAdapter returns a new FeedbackAdapter.
 func NewFeedbackAdapter() *FeedbackAdapter {
-	return &FeedbackAdapter{history: newFeedbackHistory(250)}
+	return &FeedbackAdapter{}
+}
+
+// Registry returns the registry the sent packets are recorded in, or nil if no
+// packet was sent yet.
+func (f *FeedbackAdapter) Registry() *interceptor.SentPacketRegistry {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.registry
 }
 
-func (f *FeedbackAdapter) onSentRFC8888(ts time.Time, header *rtp.Header, size int) error {
+// useRegistry returns the registry the sent packets are recorded in. It is
+// chosen by the first packet sent: the registry set in its attributes, or a new
+// one if none is.
+func (f *FeedbackAdapter) useRegistry(attributes interceptor.Attributes) *interceptor.SentPacketRegistry {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
-	f.history.add(Acknowledgment{
-		SequenceNumber: header.SequenceNumber,
+	if f.registry == nil {
+		registry, ok := attributes.Get(interceptor.SentPacketRegistryAttributesKey).(*interceptor.SentPacketRegistry)
+		if !ok {
+			registry = interceptor.NewSentPacketRegistry()
+		}
+		f.registry = registry
+	}
+
+	return f.registry
+}
+
+// SetTransportSequenceNumber sets the transport wide sequence number of an
+// outgoing packet in the header extension hdrExtID, from the registry the sent
+// packets are recorded in. If overwrite is set, every packet is numbered, and
+// the adapter records the packets in a registry of its own. Otherwise the number
+// set by an earlier interceptor is kept, and only the packets without one, such
+// as the padding generated by a pacer, are numbered, once the registry of that
+// interceptor is known.
+func (f *FeedbackAdapter) SetTransportSequenceNumber(
+	header *rtp.Header, hdrExtID uint8, attributes interceptor.Attributes, overwrite bool,
+) error {
+	var registry *interceptor.SentPacketRegistry
+	if overwrite {
+		registry = f.useRegistry(nil)
+		attributes.Set(interceptor.SentPacketRegistryAttributesKey, registry)
+	} else {
+		if _, ok := attributes.Get(interceptor.SentPacketRegistryAttributesKey).(*interceptor.SentPacketRegistry); ok {
+			return nil
+		}
+		registry = f.Registry()
+		if registry == nil || header.GetExtension(hdrExtID) != nil {
+			return nil
+		}
+	}
+	tcc, err := (&rtp.TransportCCExtension{TransportSequence: registry.NextTransportSequenceNumber()}).Marshal()
+	if err != nil {
+		return err
+	}
+
+	return header.SetExtension(hdrExtID, tcc)
+}
+
+// OnSent records that and when an outgoing packet was sent for later mapping to
+// acknowledgments. A packet already recorded by the interceptor numbering it is
+// updated with the departure and probe cluster, so that the time spent in the
+// pacer is not taken as network delay. The registry the packet is recorded in is
+// set in its attributes, for the feedback consumers later in the chain.
+func (f *FeedbackAdapter) OnSent(ts time.Time, header *rtp.Header, size int, attributes interceptor.Attributes) error {
+	probeClusterID, _ := attributes.Get(ProbeClusterAttributesKey).(int)
+	packet := interceptor.SentPacket{
 		SSRC:           header.SSRC,
+		SequenceNumber: header.SequenceNumber,
 		Size:           size,
 		Departure:      ts,
-		Arrival:        time.Time{},
-		ECN:            0,
-	})
+		ProbeClusterID: probeClusterID,
+	}
+	hdrExtensionID := attributes.Get(TwccExtensionAttributesKey)
+	if id, ok := hdrExtensionID.(uint8); ok && hdrExtensionID != 0 {
+		var tccExt rtp.TransportCCExtension
+		if err := tccExt.Unmarshal(header.GetExtension(id)); err != nil {
+			return errMissingTWCCExtension
+		}
+		packet.TransportWide = true
+		packet.TransportSequenceNumber = tccExt.TransportSequence
+		packet.Size += header.MarshalSize()
+	}
+
+	registry := f.useRegistry(attributes)
+	if recorded, ok := recordedPacket(registry, packet); ok {
+		recorded.SequenceNumber = packet.SequenceNumber
+		recorded.Departure = packet.Departure
+		recorded.ProbeClusterID = packet.ProbeClusterID
+		registry.Update(recorded)
+	} else {
+		registry.Add(packet)
+	}
+	if attributes != nil {
+		attributes.Set(interceptor.SentPacketRegistryAttributesKey, registry)
+	}
 
 	return nil
 }
 
-func (f *FeedbackAdapter) onSentTWCC(ts time.Time, extID uint8, header *rtp.Header, size int) error {
-	sequenceNumber := header.GetExtension(extID)
-	var tccExt rtp.TransportCCExtension
-	err := tccExt.Unmarshal(sequenceNumber)
-	if err != nil {
-		return errMissingTWCCExtension
+// recordedPacket returns packet if it was recorded in registry before.
+func recordedPacket(
+	registry *interceptor.SentPacketRegistry, packet interceptor.SentPacket,
+) (interceptor.SentPacket, bool) {
+	recorded, ok := registry.GetBySequenceNumber(packet.SSRC, packet.SequenceNumber)
+	if packet.TransportWide {
+		recorded, ok = registry.GetByTransportSequenceNumber(packet.TransportSequenceNumber)
+	}
+	if !ok || recorded.SSRC != packet.SSRC || recorded.TransportWide != packet.TransportWide {
+		return interceptor.SentPacket{}, false
 	}
 
-	f.lock.Lock()
-	defer f.lock.Unlock()
-	f.history.add(Acknowledgment{
-		SequenceNumber: tccExt.TransportSequence,
-		SSRC:           0,
-		Size:           header.MarshalSize() + size,
-		Departure:      ts,
-		Arrival:        time.Time{},
-		ECN:            0,
-	})
+	return recorded, true
+}
 
-	return nil
+// getByTransportSequenceNumber returns the sent packet with the transport wide
+// sequence number.
+func (f *FeedbackAdapter) getByTransportSequenceNumber(sequenceNumber uint16) (interceptor.SentPacket, bool) {
+	registry := f.Registry()
+	if registry == nil {
+		return interceptor.SentPacket{}, false
+	}
+
+	return registry.GetByTransportSequenceNumber(sequenceNumber)
 }
 
-// OnSent records that and when an outgoing packet was sent for later mapping to
-// acknowledgments.
-func (f *FeedbackAdapter) OnSent(ts time.Time, header *rtp.Header, size int, attributes interceptor.Attributes) error {
-	hdrExtensionID := attributes.Get(TwccExtensionAttributesKey)
-	id, ok := hdrExtensionID.(uint8)
-	if ok && hdrExtensionID != 0 {
-		return f.onSentTWCC(ts, id, header, size)
+// getBySequenceNumber returns the sent packet of the SSRC with the RTP sequence
+// number.
+func (f *FeedbackAdapter) getBySequenceNumber(ssrc uint32, sequenceNumber uint16) (interceptor.SentPacket, bool) {
+	registry := f.Registry()
+	if registry == nil {
+		return interceptor.SentPacket{}, false
 	}
 
-	return f.onSentRFC8888(ts, header, size)
+	return registry.GetBySequenceNumber(ssrc, sequenceNumber)
+}
+
+// acknowledgment returns the Acknowledgment, without arrival, of a sent packet
+// acknowledged by sequenceNumber.
+func acknowledgment(packet interceptor.SentPacket, sequenceNumber uint16) Acknowledgment {
+	return Acknowledgment{
+		SequenceNumber: sequenceNumber,
+		SSRC:           packet.SSRC,
+		Size:           packet.Size,
+		Departure:      packet.Departure,
+		ProbeClusterID: packet.ProbeClusterID,
+	}
 }
 
 func (f *FeedbackAdapter) unpackRunLengthChunk(
@@ -95,11 +201,8 @@ func (f *FeedbackAdapter) unpackRunLengthChunk(
 	end := start + chunk.RunLength
 	resultIndex := 0
 	for i := start; i != end; i++ {
-		key := feedbackHistoryKey{
-			ssrc:           0,
-			sequenceNumber: i,
-		}
-		if ack, ok := f.history.get(key); ok {
+		if packet, ok := f.getByTransportSequenceNumber(i); ok {
+			ack := acknowledgment(packet, i)
 			if chunk.PacketStatusSymbol != rtcp.TypeTCCPacketNotReceived {
 				if len(deltas)-1 < deltaIndex {
 					return deltaIndex, refTime, result, errInvalidFeedback
@@ -123,11 +226,9 @@ func (f *FeedbackAdapter) unpackStatusVectorChunk(
 	deltaIndex := 0
 	resultIndex := 0
 	for i, symbol := range chunk.SymbolList {
-		key := feedbackHistoryKey{
-			ssrc:           0,
-			sequenceNumber: start + uint16(i), //nolint:gosec // G115
-		}
-		if ack, ok := f.history.get(key); ok {
+		sequenceNumber := start + uint16(i) //nolint:gosec // G115
+		if packet, ok := f.getByTransportSequenceNumber(sequenceNumber); ok {
+			ack := acknowledgment(packet, sequenceNumber)
 			if symbol != rtcp.TypeTCCPacketNotReceived {
 				if len(deltas)-1 < deltaIndex {
 					return deltaIndex, refTime, result, errInvalidFeedback
@@ -149,9 +250,6 @@ func (f *FeedbackAdapter) unpackStatusVectorChunk(
 func (f *FeedbackAdapter) OnTransportCCFeedback(
 	_ time.Time, feedback *rtcp.TransportLayerCC,
 ) ([]Acknowledgment, error) {
-	f.lock.Lock()
-	defer f.lock.Unlock()
-
 	result := []Acknowledgment{}
 	index := feedback.BaseSequenceNumber
 	refTime := time.Time{}.Add(time.Duration(feedback.ReferenceTime) * 64 * time.Millisecond)
@@ -188,19 +286,13 @@ func (f *FeedbackAdapter) OnTransportCCFeedback(
 // OnRFC8888Feedback converts incoming Congestion Control Feedback RTCP packet
 // to Acknowledgments.
 func (f *FeedbackAdapter) OnRFC8888Feedback(_ time.Time, feedback *rtcp.CCFeedbackReport) []Acknowledgment {
-	f.lock.Lock()
-	defer f.lock.Unlock()
-
 	result := []Acknowledgment{}
 	referenceTime := ntp.ToTime(uint64(feedback.ReportTimestamp) << 16)
 	for _, rb := range feedback.ReportBlocks {
 		for i, mb := range rb.MetricBlocks {
 			sequenceNumber := rb.BeginSequence + uint16(i) //nolint:gosec // G115
-			key := feedbackHistoryKey{
-				ssrc:           rb.MediaSSRC,
-				sequenceNumber: sequenceNumber,
-			}
-			if ack, ok := f.history.get(key); ok {
+			if packet, ok := f.getBySequenceNumber(rb.MediaSSRC, sequenceNumber); ok {
+				ack := acknowledgment(packet, sequenceNumber)
 				if mb.Received {
 					delta := time.Duration((float64(mb.ArrivalTimeOffset) / 1024.0) * float64(time.Second))
 					ack.Arrival = referenceTime.Add(-delta)
@@ -213,67 +305,3 @@ func (f *FeedbackAdapter) OnRFC8888Feedback(_ time.Time, feedback *rtcp.CCFeedba
 
 	return result
 }
-
-type feedbackHistoryKey struct {
-	ssrc           uint32
-	sequenceNumber uint16
-}
-
-type feedbackHistory struct {
-	size      int
-	evictList *list.List
-	items     map[feedbackHistoryKey]*list.Element
-}
-
-func newFeedbackHistory(size int) *feedbackHistory {
-	return &feedbackHistory{
-		size:      size,
-		evictList: list.New(),
-		items:     make(map[feedbackHistoryKey]*list.Element),
-	}
-}
-
-func (f *feedbackHistory) get(key feedbackHistoryKey) (Acknowledgment, bool) {
-	ent, ok := f.items[key]
-	if ok {
-		if ack, ok := ent.Value.(Acknowledgment); ok {
-			return ack, true
-		}
-	}
-
-	return Acknowledgment{}, false
-}
-
-func (f *feedbackHistory) add(ack Acknowledgment) {
-	key := feedbackHistoryKey{
-		ssrc:           ack.SSRC,
-		sequenceNumber: ack.SequenceNumber,
-	}
-	// Check for existing
-	if ent, ok := f.items[key]; ok {
-		f.evictList.MoveToFront(ent)
-		ent.Value = ack
-
-		return
-	}
-	// Add new
-	ent := f.evictList.PushFront(ack)
-	f.items[key] = ent
-	// Evict if necessary
-	if f.evictList.Len() > f.size {
-		f.removeOldest()
-	}
-}
-
-func (f *feedbackHistory) removeOldest() {
-	if ent := f.evictList.Back(); ent != nil {
-		f.evictList.Remove(ent)
-		if ack, ok := ent.Value.(Acknowledgment); ok {
-			key := feedbackHistoryKey{
-				ssrc:           ack.SSRC,
-				sequenceNumber: ack.SequenceNumber,
-			}
-			delete(f.items, key)
-		}
-	}
-}
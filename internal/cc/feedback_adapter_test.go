@@ -1003,3 +1003,34 @@ func TestFeedbackAdapterTWCC(t *testing.T) {
 		})
 	})
 }
+
+func TestFeedbackAdapter_ProbeClusterID(t *testing.T) {
+	adapter := NewFeedbackAdapter()
+	for i := range uint16(2) {
+		pkt := getPacketWithTransportCCExt(t, i)
+		attributes := interceptor.Attributes{TwccExtensionAttributesKey: hdrExtID}
+		if i == 1 {
+			attributes.Set(ProbeClusterAttributesKey, 3)
+		}
+		assert.NoError(t, adapter.OnSent(time.Time{}, &pkt.Header, 1200, attributes))
+	}
+
+	acks, err := adapter.OnTransportCCFeedback(time.Time{}, &rtcp.TransportLayerCC{
+		PacketStatusCount: 2,
+		PacketChunks: []rtcp.PacketStatusChunk{
+			&rtcp.RunLengthChunk{
+				Type:               rtcp.TypeTCCRunLengthChunk,
+				PacketStatusSymbol: rtcp.TypeTCCPacketReceivedSmallDelta,
+				RunLength:          2,
+			},
+		},
+		RecvDeltas: []*rtcp.RecvDelta{
+			{Type: rtcp.TypeTCCPacketReceivedSmallDelta, Delta: 1000},
+			{Type: rtcp.TypeTCCPacketReceivedSmallDelta, Delta: 1000},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, acks, 2)
+	assert.Equal(t, 0, acks[0].ProbeClusterID)
+	assert.Equal(t, 3, acks[1].ProbeClusterID)
+}
@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package cc
+
+import (
+	"math"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/ntp"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+)
+
+// TransportCCURI is the URI of the transport wide sequence number header extension.
+const TransportCCURI = "http://www.ietf.org/id/draft-holmer-rmcat-transport-wide-cc-extensions-01"
+
+// StreamPacer is the part of a pacer the streams are added to.
+type StreamPacer interface {
+	AddStream(ssrc uint32, writer interceptor.RTPWriter)
+}
+
+// streamInfoPacer is implemented by the pacers which prioritize the packets by
+// the kind of their stream.
+type streamInfoPacer interface {
+	AddStreamInfo(info *interceptor.StreamInfo, writer interceptor.RTPWriter)
+}
+
+// SentFunc is called with every packet leaving the pacer, after it was recorded.
+// size is the size of the payload and padding of the packet.
+type SentFunc func(now time.Time, header *rtp.Header, size int, attributes interceptor.Attributes)
+
+// SendSide implements the stream and feedback plumbing shared by the sender side
+// bandwidth estimators: it numbers and records the packets as they leave the
+// pacer, and converts the incoming feedback into Acknowledgments.
+type SendSide struct {
+	// MarkECT1 asks the transport to send the packets with the ECT(1) ECN codepoint.
+	MarkECT1 bool
+	// NumberPackets numbers the transport wide sequence numbers of all packets,
+	// replacing the numbers set by earlier interceptors.
+	NumberPackets bool
+	// Clock is the clock the departure of the packets is taken from.
+	Clock interceptor.Clock
+
+	feedbackAdapter *FeedbackAdapter
+}
+
+// NewSendSide returns a new SendSide using the system clock.
+func NewSendSide() *SendSide {
+	return &SendSide{
+		Clock:           interceptor.SystemClock(),
+		feedbackAdapter: NewFeedbackAdapter(),
+	}
+}
+
+// AddStream adds a stream to pacer. The packets of the stream are numbered,
+// marked and recorded as they leave the pacer, then onSent is called if it is
+// not nil, before they are written to writer.
+func (s *SendSide) AddStream(
+	pacer StreamPacer, info *interceptor.StreamInfo, writer interceptor.RTPWriter, onSent SentFunc,
+) {
+	var hdrExtID uint8
+	for _, e := range info.RTPHeaderExtensions {
+		if e.URI == TransportCCURI {
+			hdrExtID = uint8(e.ID) //nolint:gosec // G115
+
+			break
+		}
+	}
+
+	streamWriter := interceptor.RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+			if attributes == nil {
+				attributes = make(interceptor.Attributes)
+			}
+			if hdrExtID != 0 {
+				err := s.feedbackAdapter.SetTransportSequenceNumber(header, hdrExtID, attributes, s.NumberPackets)
+				if err != nil {
+					return 0, err
+				}
+				attributes.Set(TwccExtensionAttributesKey, hdrExtID)
+			}
+			if s.MarkECT1 {
+				attributes.Set(interceptor.ECNAttributesKey, rtcp.ECNECT1)
+			}
+			now := s.Clock.Now()
+			size := len(payload) + int(header.PaddingSize)
+			if err := s.feedbackAdapter.OnSent(now, header, size, attributes); err != nil {
+				return 0, err
+			}
+			if onSent != nil {
+				onSent(now, header, size, attributes)
+			}
+
+			return writer.Write(header, payload, attributes)
+		},
+	)
+	if infoPacer, ok := pacer.(streamInfoPacer); ok {
+		infoPacer.AddStreamInfo(info, streamWriter)
+	} else {
+		pacer.AddStream(info.SSRC, streamWriter)
+	}
+}
+
+// Feedback is the congestion control feedback carried by a RTCP packet.
+type Feedback struct {
+	Acks []Acknowledgment
+	// RTT is the smallest round trip time of the received packets, it is only
+	// valid if HasRTT is set.
+	RTT    time.Duration
+	HasRTT bool
+}
+
+// OnFeedback converts a TWCC or RFC 8888 feedback received at now into a
+// Feedback. It returns false for the other RTCP packets.
+func (s *SendSide) OnFeedback(now time.Time, pkt rtcp.Packet) (Feedback, bool, error) {
+	var acks []Acknowledgment
+	var feedbackSentTime time.Time
+	switch fb := pkt.(type) {
+	case *rtcp.TransportLayerCC:
+		var err error
+		acks, err = s.feedbackAdapter.OnTransportCCFeedback(now, fb)
+		if err != nil {
+			return Feedback{}, false, err
+		}
+		for _, ack := range acks {
+			if ack.Arrival.After(feedbackSentTime) {
+				feedbackSentTime = ack.Arrival
+			}
+		}
+	case *rtcp.CCFeedbackReport:
+		acks = s.feedbackAdapter.OnRFC8888Feedback(now, fb)
+		feedbackSentTime = ntp.ToTime(uint64(fb.ReportTimestamp) << 16)
+	default:
+		return Feedback{}, false, nil
+	}
+
+	rtt, hasRTT := feedbackRTT(now, feedbackSentTime, acks)
+
+	return Feedback{Acks: acks, RTT: rtt, HasRTT: hasRTT}, true, nil
+}
+
+// feedbackRTT returns the smallest round trip time of the received packets of a
+// feedback sent at feedbackSentTime on the clock of the remote, and whether any
+// packet was received.
+func feedbackRTT(now, feedbackSentTime time.Time, acks []Acknowledgment) (time.Duration, bool) {
+	minRTT := time.Duration(math.MaxInt64)
+	for _, ack := range acks {
+		if ack.Arrival.IsZero() || ack.Departure.IsZero() {
+			continue
+		}
+		pendingTime := feedbackSentTime.Sub(ack.Arrival)
+		minRTT = min(minRTT, now.Sub(ack.Departure)-pendingTime)
+	}
+	if minRTT == time.Duration(math.MaxInt64) {
+		return 0, false
+	}
+
+	return max(minRTT, 0), true
+}
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package cc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/netem"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStreamPacer struct {
+	writers map[uint32]interceptor.RTPWriter
+}
+
+func (p *mockStreamPacer) AddStream(ssrc uint32, writer interceptor.RTPWriter) {
+	p.writers[ssrc] = writer
+}
+
+func TestSendSide(t *testing.T) {
+	clock := netem.NewVirtualClock(time.Unix(100, 0))
+	sendSide := NewSendSide()
+	sendSide.Clock = clock
+	sendSide.MarkECT1 = true
+	sendSide.NumberPackets = true
+
+	pacer := &mockStreamPacer{writers: map[uint32]interceptor.RTPWriter{}}
+	var written []interceptor.Attributes
+	var sent []int
+	sendSide.AddStream(pacer, &interceptor.StreamInfo{
+		SSRC:                1,
+		RTPHeaderExtensions: []interceptor.RTPHeaderExtension{{URI: TransportCCURI, ID: int(hdrExtID)}},
+	}, interceptor.RTPWriterFunc(
+		func(_ *rtp.Header, _ []byte, attributes interceptor.Attributes) (int, error) {
+			written = append(written, attributes)
+
+			return 0, nil
+		},
+	), func(_ time.Time, _ *rtp.Header, size int, _ interceptor.Attributes) {
+		sent = append(sent, size)
+	})
+
+	writer, ok := pacer.writers[1]
+	require.True(t, ok)
+	for i := range 2 {
+		_, err := writer.Write(&rtp.Header{SSRC: 1, SequenceNumber: uint16(i)}, make([]byte, 100), nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []int{100, 100}, sent)
+	require.Len(t, written, 2)
+	for _, attributes := range written {
+		assert.Equal(t, rtcp.ECNECT1, attributes.Get(interceptor.ECNAttributesKey))
+		assert.Equal(t, hdrExtID, attributes.Get(TwccExtensionAttributesKey))
+	}
+
+	t.Run("converts feedback", func(t *testing.T) {
+		clock.Advance(50 * time.Millisecond)
+		feedback, ok, err := sendSide.OnFeedback(clock.Now(), &rtcp.TransportLayerCC{
+			BaseSequenceNumber: 0,
+			PacketStatusCount:  2,
+			ReferenceTime:      1,
+			PacketChunks: []rtcp.PacketStatusChunk{&rtcp.RunLengthChunk{
+				PacketStatusSymbol: rtcp.TypeTCCPacketReceivedSmallDelta,
+				RunLength:          2,
+			}},
+			RecvDeltas: []*rtcp.RecvDelta{
+				{Type: rtcp.TypeTCCPacketReceivedSmallDelta, Delta: 0},
+				{Type: rtcp.TypeTCCPacketReceivedSmallDelta, Delta: 0},
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Len(t, feedback.Acks, 2)
+		assert.True(t, feedback.HasRTT)
+		assert.Equal(t, 50*time.Millisecond, feedback.RTT)
+	})
+
+	t.Run("ignores other packets", func(t *testing.T) {
+		_, ok, err := sendSide.OnFeedback(clock.Now(), &rtcp.PictureLossIndication{})
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
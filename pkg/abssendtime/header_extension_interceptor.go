@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package abssendtime provides an interceptor stamping outgoing packets with their send time,
+// as defined in https://webrtc.googlesource.com/src/+/main/docs/native-code/rtp-hdrext/abs-send-time.
+package abssendtime
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtp"
+)
+
+var errHeaderIsNil = errors.New("header is nil")
+
+const absSendTimeURI = "http://www.webrtc.org/experiments/rtp-hdrext/abs-send-time"
+
+// Option can be used to configure a HeaderExtensionInterceptor.
+type Option func(*HeaderExtensionInterceptor) error
+
+// WithClock sets the clock the send time is taken from.
+func WithClock(clock interceptor.Clock) Option {
+	return func(h *HeaderExtensionInterceptor) error {
+		h.now = clock.Now
+
+		return nil
+	}
+}
+
+// HeaderExtensionInterceptorFactory is a interceptor.Factory for a HeaderExtensionInterceptor.
+type HeaderExtensionInterceptorFactory struct {
+	opts []Option
+}
+
+// NewInterceptor constructs a new HeaderExtensionInterceptor.
+func (h *HeaderExtensionInterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	headerExtensionInterceptor := &HeaderExtensionInterceptor{now: time.Now}
+	for _, opt := range h.opts {
+		if err := opt(headerExtensionInterceptor); err != nil {
+			return nil, err
+		}
+	}
+
+	return headerExtensionInterceptor, nil
+}
+
+// NewHeaderExtensionInterceptor returns a HeaderExtensionInterceptorFactory.
+func NewHeaderExtensionInterceptor(opts ...Option) (*HeaderExtensionInterceptorFactory, error) {
+	return &HeaderExtensionInterceptorFactory{opts: opts}, nil
+}
+
+// HeaderExtensionInterceptor adds the abs-send-time header extension to each RTP packet.
+//
+// The send time is taken when the packet is written to the next writer. To be
+// as close to the wire as possible, the interceptor should be registered before
+// any interceptor delaying packets, like a pacer, so that it binds the writer
+// closest to the transport.
+type HeaderExtensionInterceptor struct {
+	interceptor.NoOp
+	now func() time.Time
+}
+
+// BindLocalStream returns a writer that adds a rtp.AbsSendTimeExtension
+// header with the current time to each outgoing packet.
+func (h *HeaderExtensionInterceptor) BindLocalStream(
+	info *interceptor.StreamInfo,
+	writer interceptor.RTPWriter,
+) interceptor.RTPWriter {
+	var hdrExtID uint8
+	for _, e := range info.RTPHeaderExtensions {
+		if e.URI == absSendTimeURI {
+			hdrExtID = uint8(e.ID) //nolint:gosec // G115
+
+			break
+		}
+	}
+	if hdrExtID == 0 { // Don't add header extension if ID is 0, because 0 is an invalid extension ID
+		return writer
+	}
+
+	return interceptor.RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+			if header == nil {
+				return 0, errHeaderIsNil
+			}
+			ext, err := rtp.NewAbsSendTimeExtension(h.now()).Marshal()
+			if err != nil {
+				return 0, err
+			}
+			if err = header.SetExtension(hdrExtID, ext); err != nil {
+				return 0, err
+			}
+
+			return writer.Write(header, payload, attributes)
+		},
+	)
+}
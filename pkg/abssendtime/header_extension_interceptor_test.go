@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package abssendtime
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestInterceptor(t *testing.T) *HeaderExtensionInterceptor {
+	t.Helper()
+
+	factory, err := NewHeaderExtensionInterceptor()
+	require.NoError(t, err)
+
+	inter, err := factory.NewInterceptor("")
+	require.NoError(t, err)
+
+	return inter.(*HeaderExtensionInterceptor) //nolint:forcetypeassert
+}
+
+func TestHeaderExtensionInterceptor(t *testing.T) {
+	t.Run("if header is nil, return error", func(t *testing.T) {
+		inter := newTestInterceptor(t)
+
+		fn := inter.BindLocalStream(&interceptor.StreamInfo{RTPHeaderExtensions: []interceptor.RTPHeaderExtension{
+			{URI: absSendTimeURI, ID: 1},
+		}}, interceptor.RTPWriterFunc(func(*rtp.Header, []byte, interceptor.Attributes) (int, error) {
+			return 0, io.EOF
+		}))
+
+		_, err := fn.Write(nil, []byte{}, interceptor.Attributes{})
+		assert.Equal(t, errHeaderIsNil, err)
+	})
+
+	t.Run("add abs-send-time to each packet", func(t *testing.T) {
+		inter := newTestInterceptor(t)
+		sendTime := time.Now()
+		inter.now = func() time.Time {
+			return sendTime
+		}
+
+		stream := test.NewMockStream(&interceptor.StreamInfo{RTPHeaderExtensions: []interceptor.RTPHeaderExtension{
+			{URI: absSendTimeURI, ID: 3},
+		}}, inter)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+
+		for _, seqNum := range []uint16{1, 2, 3} {
+			sendTime = sendTime.Add(20 * time.Millisecond)
+			assert.NoError(t, stream.WriteRTP(&rtp.Packet{Header: rtp.Header{SequenceNumber: seqNum}}))
+
+			packet := <-stream.WrittenRTP()
+			assert.Equal(t, seqNum, packet.SequenceNumber)
+
+			ext := &rtp.AbsSendTimeExtension{}
+			require.NoError(t, ext.Unmarshal(packet.GetExtension(3)))
+			assert.WithinDuration(t, sendTime, ext.Estimate(sendTime.Add(10*time.Millisecond)), time.Millisecond)
+		}
+	})
+
+	t.Run("streams without abs-send-time are not modified", func(t *testing.T) {
+		inter := newTestInterceptor(t)
+		writer := interceptor.RTPWriterFunc(func(*rtp.Header, []byte, interceptor.Attributes) (int, error) {
+			return 0, nil
+		})
+
+		fn := inter.BindLocalStream(&interceptor.StreamInfo{}, writer)
+		header := &rtp.Header{}
+		_, err := fn.Write(header, nil, nil)
+		assert.NoError(t, err)
+		assert.False(t, header.Extension)
+	})
+}
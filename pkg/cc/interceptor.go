@@ -40,7 +40,9 @@ type InterceptorFactory struct {
 	addPeerConnection NewPeerConnectionCallback
 }
 
-// NewInterceptor returns a new CC interceptor factory.
+// NewInterceptor returns a new CC interceptor factory. Without factory, the
+// SendSideBWE of package gcc is used. The SendSideBWE of package nada can be
+// used instead for NADA congestion control.
 func NewInterceptor(factory BandwidthEstimatorFactory, opts ...Option) (*InterceptorFactory, error) {
 	if factory == nil {
 		factory = func() (BandwidthEstimator, error) {
@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package flexfec
+
+import "time"
+
+// bitrateMeterWindow is the window the bitrate of the media packets is measured over.
+const bitrateMeterWindow = time.Second
+
+type bitrateSample struct {
+	at   time.Time
+	size int
+}
+
+// bitrateMeter measures the bitrate of the packets sent over the last window.
+type bitrateMeter struct {
+	samples []bitrateSample
+	bytes   int
+}
+
+func (m *bitrateMeter) add(now time.Time, size int) {
+	m.samples = append(m.samples, bitrateSample{at: now, size: size})
+	m.bytes += size
+	m.expire(now)
+}
+
+func (m *bitrateMeter) expire(now time.Time) {
+	expired := 0
+	for _, sample := range m.samples {
+		if now.Sub(sample.at) < bitrateMeterWindow {
+			break
+		}
+		m.bytes -= sample.size
+		expired++
+	}
+	m.samples = m.samples[expired:]
+}
+
+// bitrate returns the bitrate in bits per second of the packets sent over the last window.
+func (m *bitrateMeter) bitrate(now time.Time) int {
+	m.expire(now)
+
+	return int(float64(m.bytes*8) / bitrateMeterWindow.Seconds())
+}
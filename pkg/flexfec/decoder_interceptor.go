@@ -0,0 +1,235 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package flexfec
+
+import (
+	"sync"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"github.com/pion/rtp"
+)
+
+// decoderStreamState holds the decoding state of a single protected media stream.
+type decoderStreamState struct {
+	mu        sync.Mutex
+	decoder   FlexDecoder
+	mediaSSRC uint32
+	fecSSRC   uint32
+	recovered []rtp.Packet
+	fec       interceptor.FECPackets
+}
+
+// decode feeds a received packet to the decoder and queues any recovered media packets.
+func (s *decoderStreamState) decode(packet rtp.Packet) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recovered := s.decoder.DecodeFec(packet)
+	if packet.SSRC == s.fecSSRC {
+		s.fec.Received++
+		if len(recovered) == 0 {
+			s.fec.Discarded++
+		}
+	}
+	s.recovered = append(s.recovered, recovered...)
+}
+
+// setFECPackets sets the FECPackets of the stream in attr, which is created if nil.
+func (s *decoderStreamState) setFECPackets(attr interceptor.Attributes) interceptor.Attributes {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if attr == nil {
+		attr = make(interceptor.Attributes)
+	}
+	attr.Set(interceptor.FECPacketsAttributesKey, s.fec)
+
+	return attr
+}
+
+func (s *decoderStreamState) popRecovered() (rtp.Packet, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.recovered) == 0 {
+		return rtp.Packet{}, false
+	}
+
+	packet := s.recovered[0]
+	s.recovered = s.recovered[1:]
+
+	return packet, true
+}
+
+// FecDecoderInterceptor recovers lost media packets from received FlexFEC packets.
+//
+// A media stream is protected when its StreamInfo carries both
+// SSRCForwardErrorCorrection and PayloadTypeForwardErrorCorrection. The FEC
+// stream is expected to be bound after the media stream it protects, which is
+// the order pion/webrtc binds them in.
+//
+// Recovered packets are returned by the media stream reader on the following
+// reads, with interceptor.RecoveredAttributesKey set in their Attributes. All media packets
+// carry the interceptor.FECPackets counted for their stream. They may be delivered out of
+// order, so the interceptor should be placed before any interceptor that
+// reorders packets, like the jitter buffer or the NACK generator.
+type FecDecoderInterceptor struct {
+	interceptor.NoOp
+	mu             sync.Mutex
+	streams        map[uint32]*decoderStreamState
+	fecStreams     map[uint32]*decoderStreamState
+	decoderFactory DecoderFactory
+	loggerFactory  logging.LoggerFactory
+	log            logging.LeveledLogger
+}
+
+// FecDecoderInterceptorFactory creates new FecDecoderInterceptors.
+type FecDecoderInterceptorFactory struct {
+	opts []FecDecoderOption
+}
+
+// NewFecDecoderInterceptor returns a new Fec decoder interceptor factory.
+func NewFecDecoderInterceptor(opts ...FecDecoderOption) (*FecDecoderInterceptorFactory, error) {
+	return &FecDecoderInterceptorFactory{opts: opts}, nil
+}
+
+// NewInterceptor constructs a new FecDecoderInterceptor.
+func (r *FecDecoderInterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	decoderInterceptor := &FecDecoderInterceptor{
+		streams:        make(map[uint32]*decoderStreamState),
+		fecStreams:     make(map[uint32]*decoderStreamState),
+		decoderFactory: FlexDecoder03Factory{},
+	}
+
+	for _, opt := range r.opts {
+		if err := opt(decoderInterceptor); err != nil {
+			return nil, err
+		}
+	}
+
+	if decoderInterceptor.loggerFactory == nil {
+		decoderInterceptor.loggerFactory = logging.NewDefaultLoggerFactory()
+	}
+	decoderInterceptor.log = decoderInterceptor.loggerFactory.NewLogger("flexfec_decoder")
+
+	return decoderInterceptor, nil
+}
+
+// BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream.
+// The returned method will be called once per rtp packet.
+func (r *FecDecoderInterceptor) BindRemoteStream(
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stream, ok := r.fecStreams[info.SSRC]; ok {
+		return r.bindFecStream(stream, reader)
+	}
+
+	if info.PayloadTypeForwardErrorCorrection == 0 || info.SSRCForwardErrorCorrection == 0 {
+		return reader
+	}
+
+	stream := &decoderStreamState{
+		decoder:   r.decoderFactory.NewDecoder(info.SSRCForwardErrorCorrection, info.SSRC, r.loggerFactory),
+		mediaSSRC: info.SSRC,
+		fecSSRC:   info.SSRCForwardErrorCorrection,
+	}
+	r.streams[info.SSRC] = stream
+	r.fecStreams[info.SSRCForwardErrorCorrection] = stream
+
+	return r.bindMediaStream(stream, reader)
+}
+
+func (r *FecDecoderInterceptor) bindMediaStream(
+	stream *decoderStreamState, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		for {
+			if packet, ok := stream.popRecovered(); ok {
+				n, err := packet.MarshalTo(b)
+				if err != nil {
+					return 0, nil, err
+				}
+
+				// Use fresh attributes, the ones passed in may cache the header of another packet.
+				attr := make(interceptor.Attributes)
+				attr.Set(interceptor.RecoveredAttributesKey, true)
+
+				return n, stream.setFECPackets(attr), nil
+			}
+
+			n, attr, err := reader.Read(b, a)
+			if err != nil {
+				return n, attr, err
+			}
+
+			packet := rtp.Packet{}
+			if err = packet.Unmarshal(b[:n]); err != nil {
+				return n, attr, err
+			}
+
+			// The decoder keeps the packets around, so they must not point into b.
+			stream.decode(*packet.Clone())
+
+			// FEC packets multiplexed on the media stream are consumed here.
+			if packet.SSRC == stream.fecSSRC {
+				continue
+			}
+
+			return n, stream.setFECPackets(attr), nil
+		}
+	})
+}
+
+func (r *FecDecoderInterceptor) bindFecStream(
+	stream *decoderStreamState, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		n, attr, err := reader.Read(b, a)
+		if err != nil {
+			return n, attr, err
+		}
+
+		packet := rtp.Packet{}
+		if err = packet.Unmarshal(b[:n]); err != nil {
+			r.log.Warnf("failed to unmarshal FEC packet: %v", err)
+
+			return n, attr, nil
+		}
+
+		if packet.SSRC == stream.fecSSRC {
+			stream.decode(*packet.Clone())
+		}
+
+		return n, attr, nil
+	})
+}
+
+// UnbindRemoteStream is called when the Stream is removed. It can be used to clean up any data related to that track.
+func (r *FecDecoderInterceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stream, ok := r.streams[info.SSRC]
+	if !ok {
+		return
+	}
+
+	delete(r.streams, info.SSRC)
+	delete(r.fecStreams, stream.fecSSRC)
+}
+
+// Close closes the interceptor.
+func (r *FecDecoderInterceptor) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.streams = make(map[uint32]*decoderStreamState)
+	r.fecStreams = make(map[uint32]*decoderStreamState)
+
+	return nil
+}
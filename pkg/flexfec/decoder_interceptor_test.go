@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package flexfec_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/flexfec"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	decoderMediaSSRC = uint32(476325762)
+	decoderFecSSRC   = uint32(867589674)
+	decoderFecPT     = uint8(49)
+)
+
+// packetQueueReader is an RTPReader returning the queued packets, then io.EOF.
+type packetQueueReader struct {
+	packets []rtp.Packet
+}
+
+func (q *packetQueueReader) Read(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+	if len(q.packets) == 0 {
+		return 0, nil, io.EOF
+	}
+
+	packet := q.packets[0]
+	q.packets = q.packets[1:]
+	n, err := packet.MarshalTo(b)
+
+	return n, a, err
+}
+
+func generateDecoderPackets(t *testing.T, encoderFactory flexfec.EncoderFactory) ([]rtp.Packet, []rtp.Packet) {
+	t.Helper()
+
+	mediaPackets := make([]rtp.Packet, 0, 5)
+	for i := range uint16(5) {
+		mediaPackets = append(mediaPackets, rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: 100 + i,
+				Timestamp:      3653407706,
+				SSRC:           decoderMediaSSRC,
+			},
+			Payload: []byte{1, 2, 3, 4, 5, byte(i)},
+		})
+	}
+
+	encoder := encoderFactory.NewEncoder(decoderFecPT, decoderFecSSRC)
+	fecPackets := encoder.EncodeFec(mediaPackets, 2)
+	require.Len(t, fecPackets, 2)
+
+	return mediaPackets, fecPackets
+}
+
+func readPacket(t *testing.T, reader interceptor.RTPReader) (rtp.Packet, interceptor.Attributes) {
+	t.Helper()
+
+	buf := make([]byte, 1500)
+	n, attr, err := reader.Read(buf, nil)
+	require.NoError(t, err)
+
+	packet := rtp.Packet{}
+	require.NoError(t, packet.Unmarshal(buf[:n]))
+
+	return packet, attr
+}
+
+func newDecoderInterceptor(t *testing.T, opts ...flexfec.FecDecoderOption) interceptor.Interceptor {
+	t.Helper()
+
+	factory, err := flexfec.NewFecDecoderInterceptor(opts...)
+	require.NoError(t, err)
+
+	i, err := factory.NewInterceptor("")
+	require.NoError(t, err)
+
+	return i
+}
+
+func TestFecDecoderInterceptor_RecoversFromFecStream(t *testing.T) {
+	t.Run("FlexFEC-03", func(t *testing.T) {
+		testRecoversFromFecStream(t, flexfec.FlexEncoder03Factory{})
+	})
+	t.Run("FlexFEC-20", func(t *testing.T) {
+		testRecoversFromFecStream(
+			t, flexfec.FlexEncoder20Factory{}, flexfec.FECDecoderFactory(flexfec.FlexDecoder20Factory{}),
+		)
+	})
+}
+
+func testRecoversFromFecStream(t *testing.T, encoderFactory flexfec.EncoderFactory, opts ...flexfec.FecDecoderOption) {
+	t.Helper()
+
+	i := newDecoderInterceptor(t, opts...)
+	mediaPackets, fecPackets := generateDecoderPackets(t, encoderFactory)
+
+	const lost = 2
+	received := append(append([]rtp.Packet{}, mediaPackets[:lost]...), mediaPackets[lost+1:]...)
+	mediaReader := i.BindRemoteStream(&interceptor.StreamInfo{
+		SSRC:                              decoderMediaSSRC,
+		SSRCForwardErrorCorrection:        decoderFecSSRC,
+		PayloadTypeForwardErrorCorrection: decoderFecPT,
+	}, &packetQueueReader{packets: received})
+	fecReader := i.BindRemoteStream(&interceptor.StreamInfo{
+		SSRC: decoderFecSSRC,
+	}, &packetQueueReader{packets: fecPackets})
+
+	for _, expected := range received {
+		packet, attr := readPacket(t, mediaReader)
+		assert.Equal(t, expected.SequenceNumber, packet.SequenceNumber)
+		assert.Nil(t, attr.Get(interceptor.RecoveredAttributesKey))
+		assert.Equal(t, interceptor.FECPackets{}, attr.Get(interceptor.FECPacketsAttributesKey))
+	}
+
+	for _, expected := range fecPackets {
+		packet, _ := readPacket(t, fecReader)
+		assert.Equal(t, expected.SequenceNumber, packet.SequenceNumber)
+	}
+
+	packet, attr := readPacket(t, mediaReader)
+	assert.Equal(t, mediaPackets[lost], packet)
+	assert.Equal(t, true, attr.Get(interceptor.RecoveredAttributesKey))
+	// Only one of the FEC packets recovered the lost packet.
+	assert.Equal(t, interceptor.FECPackets{Received: 2, Discarded: 1}, attr.Get(interceptor.FECPacketsAttributesKey))
+
+	_, _, err := mediaReader.Read(make([]byte, 1500), nil)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.NoError(t, i.Close())
+}
+
+func TestFecDecoderInterceptor_RecoversFromMediaStream(t *testing.T) {
+	i := newDecoderInterceptor(t)
+	mediaPackets, fecPackets := generateDecoderPackets(t, flexfec.FlexEncoder03Factory{})
+
+	received := append(append([]rtp.Packet{}, mediaPackets[1:]...), fecPackets...)
+	mediaReader := i.BindRemoteStream(&interceptor.StreamInfo{
+		SSRC:                              decoderMediaSSRC,
+		SSRCForwardErrorCorrection:        decoderFecSSRC,
+		PayloadTypeForwardErrorCorrection: decoderFecPT,
+	}, &packetQueueReader{packets: received})
+
+	for _, expected := range mediaPackets[1:] {
+		packet, _ := readPacket(t, mediaReader)
+		assert.Equal(t, expected.SequenceNumber, packet.SequenceNumber)
+	}
+
+	// FEC packets are consumed, only the recovered packet is returned.
+	packet, attr := readPacket(t, mediaReader)
+	assert.Equal(t, mediaPackets[0], packet)
+	assert.Equal(t, true, attr.Get(interceptor.RecoveredAttributesKey))
+	// It is returned before the second FEC packet is read.
+	assert.Equal(t, interceptor.FECPackets{Received: 1}, attr.Get(interceptor.FECPacketsAttributesKey))
+
+	_, _, err := mediaReader.Read(make([]byte, 1500), nil)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestFecDecoderInterceptor_UnprotectedStream(t *testing.T) {
+	i := newDecoderInterceptor(t)
+	mediaPackets, _ := generateDecoderPackets(t, flexfec.FlexEncoder03Factory{})
+
+	queue := &packetQueueReader{packets: mediaPackets}
+	reader := i.BindRemoteStream(&interceptor.StreamInfo{SSRC: decoderMediaSSRC}, queue)
+	assert.Equal(t, interceptor.RTPReader(queue), reader)
+
+	// A FEC stream bound after its media stream was unbound is passed through.
+	info := &interceptor.StreamInfo{
+		SSRC:                              decoderMediaSSRC,
+		SSRCForwardErrorCorrection:        decoderFecSSRC,
+		PayloadTypeForwardErrorCorrection: decoderFecPT,
+	}
+	i.BindRemoteStream(info, queue)
+	i.UnbindRemoteStream(info)
+
+	fecQueue := &packetQueueReader{}
+	assert.Equal(t, interceptor.RTPReader(fecQueue), i.BindRemoteStream(&interceptor.StreamInfo{
+		SSRC: decoderFecSSRC,
+	}, fecQueue))
+}
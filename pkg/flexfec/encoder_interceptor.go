@@ -5,9 +5,13 @@ package flexfec
 
 import (
 	"errors"
+	"math"
 	"sync"
+	"time"
 
 	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/rtpfb"
+	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 )
 
@@ -16,9 +20,26 @@ type streamState struct {
 	mu             sync.Mutex
 	flexFecEncoder FlexEncoder
 	packetBuffer   []rtp.Packet
+	// controller is only set in adaptive mode.
+	controller *protectionController
+}
+
+// BandwidthEstimator is the part of a cc.BandwidthEstimator the FecInterceptor
+// takes its bitrate budget from.
+type BandwidthEstimator interface {
+	GetTargetBitrate() int
 }
 
 // FecInterceptor implements FlexFec.
+//
+// In adaptive mode, the number of FEC packets generated for every
+// NumMediaPackets media packets and their MaskType are picked from the loss
+// reported in incoming receiver reports, and in rtpfb.Report when the
+// interceptor is registered after the rtpfb interceptor.
+//
+// Once a BandwidthEstimator is added for its PeerConnection, the FEC packets
+// are also limited to the part of the target bitrate left by the media packets
+// sent over the last second.
 type FecInterceptor struct {
 	interceptor.NoOp
 	mu              sync.Mutex
@@ -26,25 +47,52 @@ type FecInterceptor struct {
 	numMediaPackets uint32
 	numFecPackets   uint32
 	encoderFactory  EncoderFactory
+	adaptive        bool
+	maxOverhead     float64
+	clock           interceptor.Clock
+	id              string
+	onClose         func(string)
+
+	// budgetMu guards the estimator and the media bitrate. It is taken after
+	// the lock of a stream.
+	budgetMu   sync.Mutex
+	estimator  BandwidthEstimator
+	mediaMeter bitrateMeter
 }
 
-// FecInterceptorFactory creates new FecInterceptors.
+// FecInterceptorFactory creates new FecInterceptors. It also keeps a map
+// of interceptors created in the past by ID.
 type FecInterceptorFactory struct {
-	opts []FecOption
+	lock         sync.Mutex
+	opts         []FecOption
+	interceptors map[string]*FecInterceptor
+	estimators   map[string]BandwidthEstimator
 }
 
 // NewFecInterceptor returns a new Fec interceptor factory.
 func NewFecInterceptor(opts ...FecOption) (*FecInterceptorFactory, error) {
-	return &FecInterceptorFactory{opts: opts}, nil
+	return &FecInterceptorFactory{
+		opts:         opts,
+		interceptors: map[string]*FecInterceptor{},
+		estimators:   map[string]BandwidthEstimator{},
+	}, nil
 }
 
 // NewInterceptor constructs a new FecInterceptor.
-func (r *FecInterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+func (r *FecInterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
 	interceptor := &FecInterceptor{
 		streams:         make(map[uint32]*streamState),
 		numMediaPackets: 5,
 		numFecPackets:   2,
 		encoderFactory:  FlexEncoder03Factory{},
+		maxOverhead:     0.5,
+		clock:           interceptor.SystemClock(),
+		id:              id,
+		onClose:         r.remove,
+		estimator:       r.estimators[id],
 	}
 
 	for _, opt := range r.opts {
@@ -53,9 +101,198 @@ func (r *FecInterceptorFactory) NewInterceptor(_ string) (interceptor.Intercepto
 		}
 	}
 
+	r.interceptors[id] = interceptor
+
 	return interceptor, nil
 }
 
+// AddBandwidthEstimator sets the BandwidthEstimator of the PeerConnection with the given
+// ID, usually the cc.BandwidthEstimator passed to the cc.NewPeerConnectionCallback. Its
+// target bitrate is the budget of the FEC packets and the media packets.
+func (r *FecInterceptorFactory) AddBandwidthEstimator(id string, estimator BandwidthEstimator) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	// The cc interceptor may be created before the FecInterceptor of the PeerConnection.
+	r.estimators[id] = estimator
+	if i, ok := r.interceptors[id]; ok {
+		i.setEstimator(estimator)
+	}
+}
+
+// MediaBitrate returns the part of the target bitrate of the FecInterceptor with the given
+// ID which is left for media once the FEC overhead is subtracted. It returns 0 until
+// AddBandwidthEstimator is called.
+func (r *FecInterceptorFactory) MediaBitrate(id string) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	i, ok := r.interceptors[id]
+	if !ok {
+		return 0
+	}
+
+	return i.mediaBitrate()
+}
+
+func (r *FecInterceptorFactory) remove(id string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.interceptors, id)
+	delete(r.estimators, id)
+}
+
+func (r *FecInterceptor) setEstimator(estimator BandwidthEstimator) {
+	r.budgetMu.Lock()
+	defer r.budgetMu.Unlock()
+
+	r.estimator = estimator
+}
+
+func (r *FecInterceptor) targetBitrate() int {
+	r.budgetMu.Lock()
+	defer r.budgetMu.Unlock()
+
+	if r.estimator == nil {
+		return 0
+	}
+
+	return r.estimator.GetTargetBitrate()
+}
+
+// addMedia records a media packet of a protected stream.
+func (r *FecInterceptor) addMedia(now time.Time, size int) {
+	r.budgetMu.Lock()
+	defer r.budgetMu.Unlock()
+
+	r.mediaMeter.add(now, size)
+}
+
+// maxFecPackets returns the number of FEC packets per numMediaPackets media packets the
+// bitrate budget leaves room for, or math.MaxUint32 without BandwidthEstimator.
+func (r *FecInterceptor) maxFecPackets(now time.Time) uint32 {
+	r.budgetMu.Lock()
+	defer r.budgetMu.Unlock()
+
+	if r.estimator == nil {
+		return math.MaxUint32
+	}
+	mediaBitrate := r.mediaMeter.bitrate(now)
+	budget := r.estimator.GetTargetBitrate() - mediaBitrate
+	if budget <= 0 {
+		return 0
+	}
+
+	// FEC packets are about as large as the media packets they protect.
+	return uint32(min(float64(budget)/float64(mediaBitrate)*float64(r.numMediaPackets), math.MaxUint32))
+}
+
+// mediaBitrate subtracts the FEC overhead of the most protected stream from the target bitrate.
+func (r *FecInterceptor) mediaBitrate() int {
+	targetBitrate := r.targetBitrate()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	overhead := 0.0
+	if r.adaptive {
+		for _, stream := range r.streams {
+			stream.mu.Lock()
+			overhead = max(overhead, stream.controller.overhead(r.numMediaPackets, r.maxOverhead))
+			stream.mu.Unlock()
+		}
+	} else if len(r.streams) > 0 && r.numFecPackets > 0 {
+		overhead = float64(r.numFecPackets) / float64(r.numMediaPackets+r.numFecPackets)
+	}
+
+	return int(float64(targetBitrate) * (1 - overhead))
+}
+
+// BindRTCPReader lets you modify any incoming RTCP packets. In adaptive mode, it is used
+// to observe the loss reported by the remote peer.
+func (r *FecInterceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.RTCPReader {
+	if !r.adaptive {
+		return reader
+	}
+
+	return interceptor.RTCPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		n, attr, err := reader.Read(b, a)
+		if err != nil {
+			return n, attr, err
+		}
+
+		if attr == nil {
+			attr = make(interceptor.Attributes)
+		}
+		pkts, err := attr.GetRTCPPackets(b[:n])
+		if err != nil {
+			return n, attr, err
+		}
+
+		for _, pkt := range pkts {
+			switch pkt := pkt.(type) {
+			case *rtcp.ReceiverReport:
+				r.onReceptionReports(pkt.Reports)
+			case *rtcp.SenderReport:
+				r.onReceptionReports(pkt.Reports)
+			}
+		}
+
+		if report, ok := attr.Get(rtpfb.CCFBAttributesKey).(rtpfb.Report); ok {
+			r.onFeedbackReport(report)
+		}
+
+		return n, attr, nil
+	})
+}
+
+func (r *FecInterceptor) onReceptionReports(reports []rtcp.ReceptionReport) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, report := range reports {
+		stream, ok := r.streams[report.SSRC]
+		if !ok {
+			continue
+		}
+
+		stream.mu.Lock()
+		stream.controller.updateLossRate(float64(report.FractionLost) / 256)
+		stream.mu.Unlock()
+	}
+}
+
+func (r *FecInterceptor) onFeedbackReport(report rtpfb.Report) {
+	packetReports := make(map[uint32][]rtpfb.PacketReport)
+	for _, packetReport := range report.PacketReports {
+		packetReports[packetReport.SSRC] = append(packetReports[packetReport.SSRC], packetReport)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for ssrc, reports := range packetReports {
+		stream, ok := r.streams[ssrc]
+		if !ok {
+			continue
+		}
+
+		stream.mu.Lock()
+		stream.controller.onPacketReports(reports)
+		stream.mu.Unlock()
+	}
+}
+
+// Close removes the interceptor from its factory.
+func (r *FecInterceptor) Close() error {
+	if r.onClose != nil {
+		r.onClose(r.id)
+	}
+
+	return nil
+}
+
 // UnbindLocalStream removes the stream state for a specific SSRC.
 func (r *FecInterceptor) UnbindLocalStream(info *interceptor.StreamInfo) {
 	r.mu.Lock()
@@ -82,6 +319,9 @@ func (r *FecInterceptor) BindLocalStream(
 		flexFecEncoder: r.encoderFactory.NewEncoder(info.PayloadTypeForwardErrorCorrection, info.SSRCForwardErrorCorrection),
 		packetBuffer:   make([]rtp.Packet, 0),
 	}
+	if r.adaptive {
+		stream.controller = newProtectionController()
+	}
 	r.streams[mediaSSRC] = stream
 	r.mu.Unlock()
 
@@ -94,6 +334,8 @@ func (r *FecInterceptor) BindLocalStream(
 
 			var fecPackets []rtp.Packet
 			stream.mu.Lock()
+			now := r.clock.Now()
+			r.addMedia(now, header.MarshalSize()+len(payload))
 			stream.packetBuffer = append(stream.packetBuffer, rtp.Packet{
 				Header:  *header,
 				Payload: payload,
@@ -101,7 +343,18 @@ func (r *FecInterceptor) BindLocalStream(
 
 			// Check if we have enough packets to generate FEC
 			if len(stream.packetBuffer) == int(r.numMediaPackets) {
-				fecPackets = stream.flexFecEncoder.EncodeFec(stream.packetBuffer, r.numFecPackets)
+				numFecPackets := r.numFecPackets
+				if stream.controller != nil {
+					var maskType MaskType
+					numFecPackets, maskType = stream.controller.protection(r.numMediaPackets, r.maxOverhead)
+					if setter, ok := stream.flexFecEncoder.(MaskTypeSetter); ok {
+						setter.SetMaskType(maskType)
+					}
+				}
+				numFecPackets = min(numFecPackets, r.maxFecPackets(now))
+				if numFecPackets > 0 {
+					fecPackets = stream.flexFecEncoder.EncodeFec(stream.packetBuffer, numFecPackets)
+				}
 				// Reset the packet buffer now that we've sent the corresponding FEC packets.
 				stream.packetBuffer = nil
 			}
@@ -5,10 +5,13 @@ package flexfec_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pion/interceptor"
 	"github.com/pion/interceptor/internal/test"
 	"github.com/pion/interceptor/pkg/flexfec"
+	"github.com/pion/interceptor/pkg/netem"
+	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/assert"
 )
@@ -389,3 +392,141 @@ func TestFecInterceptor_HandleMultipleStreamsCorrectly(t *testing.T) {
 	assert.Equal(t, 2, mediaPacketsCount2, "Expected 2 media packets for stream2")
 	assert.Equal(t, 1, fecPacketsCount2, "Expected 1 FEC packet for stream2")
 }
+
+func TestFecInterceptor_AdaptiveProtection(t *testing.T) {
+	mockEncoder := NewMockFlexEncoder(nil)
+	factory, err := flexfec.NewFecInterceptor(
+		flexfec.FECEncoderFactory(NewMockEncoderFactory(mockEncoder)),
+		flexfec.NumMediaPackets(10),
+		flexfec.AdaptiveProtection(),
+		flexfec.MaxFECOverhead(0.25),
+	)
+	assert.NoError(t, err)
+
+	i, err := factory.NewInterceptor("pc")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:                              1000,
+		PayloadTypeForwardErrorCorrection: 100,
+		SSRCForwardErrorCorrection:        2000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	writeMediaPackets := func(start uint16) {
+		for seq := start; seq < start+10; seq++ {
+			assert.NoError(t, stream.WriteRTP(&rtp.Packet{
+				Header:  rtp.Header{SSRC: 1000, SequenceNumber: seq, PayloadType: 96},
+				Payload: []byte{0x01},
+			}))
+		}
+	}
+
+	factory.AddBandwidthEstimator("pc", mockBandwidthEstimator{targetBitrate: 1_000_000})
+
+	// No loss reported yet, no FEC is generated.
+	writeMediaPackets(0)
+	assert.False(t, mockEncoder.Called)
+	assert.Equal(t, 1_000_000, factory.MediaBitrate("pc"))
+
+	for range 20 {
+		stream.ReceiveRTCP([]rtcp.Packet{&rtcp.ReceiverReport{
+			Reports: []rtcp.ReceptionReport{{SSRC: 1000, FractionLost: 25}}, // ~10%
+		}})
+		<-stream.ReadRTCP()
+	}
+
+	writeMediaPackets(10)
+	assert.True(t, mockEncoder.Called)
+	assert.Equal(t, uint32(2), mockEncoder.NumFECPackets)
+	assert.Equal(t, 1_000_000*10/12, factory.MediaBitrate("pc"))
+
+	for range 20 {
+		stream.ReceiveRTCP([]rtcp.Packet{&rtcp.ReceiverReport{
+			Reports: []rtcp.ReceptionReport{{SSRC: 1000, FractionLost: 128}}, // 50%
+		}})
+		<-stream.ReadRTCP()
+	}
+
+	// The overhead is capped to a quarter of the bitrate.
+	writeMediaPackets(20)
+	assert.Equal(t, uint32(3), mockEncoder.NumFECPackets)
+	assert.Equal(t, 1_000_000*10/13, factory.MediaBitrate("pc"))
+
+	assert.NoError(t, i.Close())
+	assert.Equal(t, 0, factory.MediaBitrate("pc"))
+}
+
+type mockBandwidthEstimator struct {
+	targetBitrate int
+}
+
+func (m mockBandwidthEstimator) GetTargetBitrate() int {
+	return m.targetBitrate
+}
+
+func TestFecInterceptor_BitrateBudget(t *testing.T) {
+	mockEncoder := NewMockFlexEncoder(nil)
+	clock := netem.NewVirtualClock(time.Unix(1000, 0))
+	factory, err := flexfec.NewFecInterceptor(
+		flexfec.FECEncoderFactory(NewMockEncoderFactory(mockEncoder)),
+		flexfec.FECEncoderClock(clock),
+		flexfec.NumMediaPackets(10),
+		flexfec.NumFECPackets(5),
+	)
+	assert.NoError(t, err)
+
+	// The estimator may be added before the interceptor is created.
+	factory.AddBandwidthEstimator("pc", mockBandwidthEstimator{targetBitrate: 1_000_000})
+	i, err := factory.NewInterceptor("pc")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:                              1000,
+		PayloadTypeForwardErrorCorrection: 100,
+		SSRCForwardErrorCorrection:        2000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	// 1200 bytes every 10ms, 960kbps of media.
+	seq := uint16(0)
+	writeMediaPackets := func(count int) {
+		for range count {
+			assert.NoError(t, stream.WriteRTP(&rtp.Packet{
+				Header:  rtp.Header{SSRC: 1000, SequenceNumber: seq, PayloadType: 96},
+				Payload: make([]byte, 1188),
+			}))
+			seq++
+			clock.Advance(10 * time.Millisecond)
+		}
+	}
+
+	// The media packets leave 40kbps, not enough for a FEC packet.
+	writeMediaPackets(100)
+	mockEncoder.Called = false
+	writeMediaPackets(10)
+	assert.False(t, mockEncoder.Called)
+
+	// 240kbps are enough for 2 FEC packets.
+	factory.AddBandwidthEstimator("pc", mockBandwidthEstimator{targetBitrate: 1_200_000})
+	writeMediaPackets(10)
+	assert.True(t, mockEncoder.Called)
+	assert.Equal(t, uint32(2), mockEncoder.NumFECPackets)
+
+	// Without budget limit, NumFECPackets are generated.
+	factory.AddBandwidthEstimator("pc", mockBandwidthEstimator{targetBitrate: 10_000_000})
+	writeMediaPackets(10)
+	assert.Equal(t, uint32(5), mockEncoder.NumFECPackets)
+}
+
+func TestFecInterceptor_InvalidMaxFECOverhead(t *testing.T) {
+	factory, err := flexfec.NewFecInterceptor(flexfec.MaxFECOverhead(1.5))
+	assert.NoError(t, err)
+
+	_, err = factory.NewInterceptor("")
+	assert.Error(t, err)
+}
@@ -21,8 +21,19 @@ const (
 func checkAnyPacketCanBeRecovered(t *testing.T, mediaPackets []rtp.Packet, fecPackets []rtp.Packet) {
 	t.Helper()
 
+	checkAnyPacketCanBeRecoveredWithDecoder(t, newFECDecoder, mediaPackets, fecPackets)
+}
+
+func checkAnyPacketCanBeRecoveredWithDecoder(
+	t *testing.T,
+	newDecoder func(uint32, uint32, logging.LoggerFactory) *fecDecoder,
+	mediaPackets []rtp.Packet,
+	fecPackets []rtp.Packet,
+) {
+	t.Helper()
+
 	for lost := range mediaPackets {
-		decoder := newFECDecoder(ssrc, protectedStreamSSRC, logging.NewDefaultLoggerFactory())
+		decoder := newDecoder(ssrc, protectedStreamSSRC, logging.NewDefaultLoggerFactory())
 		recoveredPackets := make([]rtp.Packet, 0)
 		// lose one packet
 		for _, mediaPacket := range mediaPackets[:lost] {
@@ -51,6 +62,14 @@ func generatePackets(t *testing.T, seqs []uint16) ([]rtp.Packet, []rtp.Packet) {
 func generatePacketsWithFecCount(t *testing.T, seqs []uint16, fecCount uint32) ([]rtp.Packet, []rtp.Packet) {
 	t.Helper()
 
+	return generatePacketsWithEncoder(t, FlexEncoder03Factory{}, seqs, fecCount)
+}
+
+func generatePacketsWithEncoder(
+	t *testing.T, encoderFactory EncoderFactory, seqs []uint16, fecCount uint32,
+) ([]rtp.Packet, []rtp.Packet) {
+	t.Helper()
+
 	mediaPackets := make([]rtp.Packet, 0)
 	for i, seq := range seqs {
 		payload := []byte{
@@ -78,7 +97,7 @@ func generatePacketsWithFecCount(t *testing.T, seqs []uint16, fecCount uint32) (
 		mediaPackets = append(mediaPackets, packet)
 	}
 
-	encoder := FlexEncoder03Factory{}.NewEncoder(payloadType, ssrc)
+	encoder := encoderFactory.NewEncoder(payloadType, ssrc)
 	fecPackets := encoder.EncodeFec(mediaPackets, fecCount)
 
 	return mediaPackets, fecPackets
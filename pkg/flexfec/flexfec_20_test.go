@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package flexfec
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/pion/logging"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlexFec20_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		seqStart uint16
+		seqLen   int
+		fecCount uint32
+	}{
+		{name: "single mask", seqStart: 1, seqLen: 5, fecCount: 2},
+		{name: "wrap", seqStart: 65533, seqLen: 5, fecCount: 2},
+		{name: "second mask", seqStart: 100, seqLen: 40, fecCount: 2},
+		{name: "third mask", seqStart: 1000, seqLen: 100, fecCount: 3},
+		{name: "whole range", seqStart: 0, seqLen: int(MaxMediaPackets), fecCount: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			seqs := make([]uint16, test.seqLen)
+			for i := range test.seqLen {
+				seqs[i] = test.seqStart + uint16(i) //nolint:gosec // G115
+			}
+
+			mediaPackets, fecPackets := generatePacketsWithEncoder(t, FlexEncoder20Factory{}, seqs, test.fecCount)
+			require.Len(t, fecPackets, int(test.fecCount))
+			for _, fecPacket := range fecPackets {
+				assert.Equal(t, []uint32{protectedStreamSSRC}, fecPacket.CSRC)
+			}
+			checkAnyPacketCanBeRecoveredWithDecoder(t, newFECDecoder20, mediaPackets, fecPackets)
+		})
+	}
+}
+
+func TestFlexFec20_HeaderMaskLengths(t *testing.T) {
+	for _, test := range []struct {
+		seqLen     int
+		headerSize int
+	}{
+		{seqLen: 15, headerSize: 12},
+		{seqLen: 46, headerSize: 16},
+		{seqLen: 110, headerSize: 24},
+	} {
+		seqs := make([]uint16, test.seqLen)
+		for i := range test.seqLen {
+			seqs[i] = uint16(i) //nolint:gosec // G115
+		}
+
+		mediaPackets, fecPackets := generatePacketsWithEncoder(t, FlexEncoder20Factory{}, seqs, 1)
+		require.Len(t, fecPackets, 1)
+
+		fec, err := parseFlexFEC20Header(fecPackets[0])
+		require.NoError(t, err)
+		assert.Len(t, fec.protectedSeqs, test.seqLen)
+		assert.Len(t, fec.payload, mediaPackets[0].MarshalSize()-BaseRTPHeaderSize)
+		assert.Len(t, fecPackets[0].Payload, test.headerSize+len(fec.payload))
+	}
+}
+
+func TestFlexFec20_Retransmission(t *testing.T) {
+	mediaPackets, _ := generatePacketsWithEncoder(t, FlexEncoder20Factory{}, []uint16{10, 11}, 1)
+
+	retransmitted, err := mediaPackets[1].Marshal()
+	require.NoError(t, err)
+	// R=1, F=0 replace the version field.
+	retransmitted[0] = (retransmitted[0] & 0x3f) | 0x80
+
+	decoder := newFECDecoder20(ssrc, protectedStreamSSRC, logging.NewDefaultLoggerFactory())
+	assert.Empty(t, decoder.DecodeFec(mediaPackets[0]))
+
+	fecPacket := rtp.Packet{
+		Header:  rtp.Header{Version: 2, PayloadType: payloadType, SequenceNumber: 1, SSRC: ssrc},
+		Payload: retransmitted,
+	}
+	recovered := decoder.DecodeFec(fecPacket)
+	require.Len(t, recovered, 1)
+	assert.Equal(t, mediaPackets[1], recovered[0])
+
+	// The retransmitted packet is only returned once.
+	fecPacket.SequenceNumber++
+	assert.Empty(t, decoder.DecodeFec(fecPacket))
+	assert.Empty(t, decoder.DecodeFec(mediaPackets[1]))
+}
+
+func TestFlexFec20_FixedMask(t *testing.T) {
+	header := make([]byte, BaseFecHeaderSize)
+	header[0] = 0x40
+	binary.BigEndian.PutUint16(header[8:10], 100)
+	packet := rtp.Packet{Header: rtp.Header{CSRC: []uint32{protectedStreamSSRC}}}
+
+	// Row FEC.
+	header[10], header[11] = 4, 0
+	packet.Payload = header
+	fec, err := parseFlexFEC20Header(packet)
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{100, 101, 102, 103}, fec.protectedSeqs)
+
+	// Column FEC.
+	header[10], header[11] = 4, 3
+	fec, err = parseFlexFEC20Header(packet)
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{100, 104, 108}, fec.protectedSeqs)
+
+	header[10], header[11] = 0, 3
+	_, err = parseFlexFEC20Header(packet)
+	assert.ErrorIs(t, err, errInvalidFixedMask)
+}
+
+func TestFlexFec20_ParseErrors(t *testing.T) {
+	_, err := parseFlexFEC20Header(rtp.Packet{Payload: make([]byte, 4)})
+	assert.ErrorIs(t, err, errPacketTruncated)
+
+	payload := make([]byte, BaseFecHeaderSize)
+	_, err = parseFlexFEC20Header(rtp.Packet{Payload: payload})
+	assert.ErrorIs(t, err, errMultipleSSRCProtection)
+
+	// k bit of the first mask unset, but the second mask is missing.
+	_, err = parseFlexFEC20Header(rtp.Packet{
+		Header:  rtp.Header{CSRC: []uint32{protectedStreamSSRC}},
+		Payload: payload,
+	})
+	assert.ErrorIs(t, err, errPacketTruncated)
+
+	payload[0] = 0xc0
+	_, err = parseFlexFEC20Header(rtp.Packet{Payload: payload})
+	assert.ErrorIs(t, err, errInflexibleGeneratorMatrix)
+}
@@ -17,6 +17,30 @@ const (
 	MaxFecPackets   uint32 = MaxMediaPackets
 )
 
+// MaskType selects how media packets are spread over the FEC packets protecting them.
+type MaskType int
+
+const (
+	// MaskBursty interleaves the media packets over the FEC packets, so that consecutive
+	// lost packets are protected by different FEC packets. This is the default.
+	MaskBursty MaskType = iota
+	// MaskRandom additionally protects each consecutive block of media packets with one
+	// FEC packet, so that every media packet is covered twice when at least two FEC packets
+	// are generated. This recovers more isolated losses at the cost of burst resilience.
+	MaskRandom
+)
+
+func (m MaskType) String() string {
+	switch m {
+	case MaskBursty:
+		return "bursty"
+	case MaskRandom:
+		return "random"
+	default:
+		return "unknown"
+	}
+}
+
 // ProtectionCoverage defines the map of RTP packets that individual Fec packets protect.
 type ProtectionCoverage struct {
 	// Array of masks, each mask capable of covering up to maxMediaPkts = 110.
@@ -26,12 +50,18 @@ type ProtectionCoverage struct {
 	numFecPackets   uint32
 	numMediaPackets uint32
 	mediaPackets    []rtp.Packet
+	maskType        MaskType
 }
 
 // NewCoverage returns a new ProtectionCoverage object. numFecPackets represents the number of
 // Fec packets that we will be generating to cover the list of mediaPackets. This allows us to know
 // how big the underlying map should be.
 func NewCoverage(mediaPackets []rtp.Packet, numFecPackets uint32) *ProtectionCoverage {
+	return NewCoverageWithMaskType(mediaPackets, numFecPackets, MaskBursty)
+}
+
+// NewCoverageWithMaskType returns a new ProtectionCoverage object using the given MaskType.
+func NewCoverageWithMaskType(mediaPackets []rtp.Packet, numFecPackets uint32, maskType MaskType) *ProtectionCoverage {
 	numMediaPackets := uint32(len(mediaPackets)) //nolint:gosec // G115
 
 	// Basic sanity checks
@@ -50,6 +80,7 @@ func NewCoverage(mediaPackets []rtp.Packet, numFecPackets uint32) *ProtectionCov
 		numFecPackets:   0,
 		numMediaPackets: 0,
 		mediaPackets:    nil,
+		maskType:        maskType,
 	}
 
 	coverage.UpdateCoverage(mediaPackets, numFecPackets)
@@ -95,6 +126,25 @@ func (p *ProtectionCoverage) UpdateCoverage(mediaPackets []rtp.Packet, numFecPac
 			coveredMediaPacketIndex += numFecPackets
 		}
 	}
+
+	if p.maskType == MaskRandom && numFecPackets > 1 {
+		// Given N FEC packets and M media packets, media packet X is also covered by FEC packet X * N / M.
+		for mediaPacketIndex := range numMediaPackets {
+			p.packetMasks[mediaPacketIndex*numFecPackets/numMediaPackets].SetBit(mediaPacketIndex)
+		}
+	}
+}
+
+// SetMaskType changes the MaskType used for the following batches of media packets.
+func (p *ProtectionCoverage) SetMaskType(maskType MaskType) {
+	if p.maskType == maskType {
+		return
+	}
+
+	p.maskType = maskType
+	// Force the coverage map to be regenerated on the next update.
+	p.numFecPackets = 0
+	p.numMediaPackets = 0
 }
 
 // ResetCoverage clears the underlying map so that we can reuse it for new batches of RTP packets.
@@ -7,7 +7,9 @@ import (
 	"testing"
 
 	"github.com/pion/interceptor/pkg/flexfec/util"
+	"github.com/pion/rtp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMaskExtractors(t *testing.T) {
@@ -73,3 +75,54 @@ func TestMaskExtractors(t *testing.T) {
 		})
 	}
 }
+
+func TestCoverageMaskTypes(t *testing.T) {
+	mediaPackets := make([]rtp.Packet, 6)
+	for i := range mediaPackets {
+		mediaPackets[i].SequenceNumber = uint16(i) //nolint:gosec // G115
+	}
+
+	coveredBy := func(coverage *ProtectionCoverage, fecPacketIndex uint32) []uint16 {
+		seqs := []uint16{}
+		it := coverage.GetCoveredBy(fecPacketIndex)
+		for it.HasNext() {
+			seqs = append(seqs, it.Next().SequenceNumber)
+		}
+
+		return seqs
+	}
+
+	coverage := NewCoverage(mediaPackets, 2)
+	assert.Equal(t, []uint16{0, 2, 4}, coveredBy(coverage, 0))
+	assert.Equal(t, []uint16{1, 3, 5}, coveredBy(coverage, 1))
+
+	coverage.SetMaskType(MaskRandom)
+	coverage.UpdateCoverage(mediaPackets, 2)
+	assert.Equal(t, []uint16{0, 1, 2, 4}, coveredBy(coverage, 0))
+	assert.Equal(t, []uint16{1, 3, 4, 5}, coveredBy(coverage, 1))
+
+	// A single FEC packet covers everything with both mask types.
+	coverage.UpdateCoverage(mediaPackets, 1)
+	assert.Equal(t, []uint16{0, 1, 2, 3, 4, 5}, coveredBy(coverage, 0))
+}
+
+func TestCoverageMaskRandomRoundTrip(t *testing.T) {
+	for _, encoderFactory := range []EncoderFactory{FlexEncoder03Factory{}, FlexEncoder20Factory{}} {
+		seqs := make([]uint16, 20)
+		for i := range seqs {
+			seqs[i] = uint16(100 + i) //nolint:gosec // G115
+		}
+
+		encoder := encoderFactory.NewEncoder(payloadType, ssrc)
+		encoder.(MaskTypeSetter).SetMaskType(MaskRandom) //nolint:forcetypeassert
+		mediaPackets, _ := generatePacketsWithEncoder(t, encoderFactory, seqs, 3)
+		fecPackets := encoder.EncodeFec(mediaPackets, 3)
+		require.Len(t, fecPackets, 3)
+
+		newDecoder := newFECDecoder
+		if _, ok := encoderFactory.(FlexEncoder20Factory); ok {
+			newDecoder = newFECDecoder20
+		}
+		checkAnyPacketCanBeRecoveredWithDecoder(t, newDecoder, mediaPackets, fecPackets)
+	}
+}
@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package flexfec
+
+import (
+	"github.com/pion/logging"
+	"github.com/pion/rtp"
+)
+
+// DecoderFactory is an interface for generic FEC decoders.
+type DecoderFactory interface {
+	NewDecoder(ssrc uint32, protectedSSRC uint32, loggerFactory logging.LoggerFactory) FlexDecoder
+}
+
+// FlexDecoder is the interface that FecDecoderInterceptor uses to recover media packets.
+// DecodeFec is fed with every received media and FEC packet, and returns the media packets
+// that could be recovered thanks to it. Decoders may keep references to the passed packets.
+type FlexDecoder interface {
+	DecodeFec(receivedPacket rtp.Packet) []rtp.Packet
+}
@@ -22,9 +22,20 @@ var (
 	errInflexibleGeneratorMatrix      = errors.New("packet with inflexible generator matrix not supported")
 	errMultipleSSRCProtection         = errors.New("multiple ssrc protection not supported")
 	errLastOptionalMaskKBitSetToFalse = errors.New("k-bit of last optional mask is set to false")
+	errInvalidFixedMask               = errors.New("invalid fixed mask")
 )
 
-// fecDecoder is a WIP implementation decoder used for testing purposes.
+// FlexDecoder03Factory is a factory for FlexFEC-03 decoders.
+type FlexDecoder03Factory struct{}
+
+// NewDecoder creates new FlexFEC-03 decoder.
+func (f FlexDecoder03Factory) NewDecoder(
+	ssrc uint32, protectedSSRC uint32, loggerFactory logging.LoggerFactory,
+) FlexDecoder {
+	return newFECDecoder(ssrc, protectedSSRC, loggerFactory)
+}
+
+// fecDecoder recovers media packets of a single stream protected by FlexFEC-03.
 type fecDecoder struct {
 	logger              logging.LeveledLogger
 	ssrc                uint32
@@ -33,6 +44,9 @@ type fecDecoder struct {
 	maxFECPackets       int
 	recoveredPackets    []rtp.Packet
 	receivedFECPackets  []fecPacketState
+	// retransmittedPackets are media packets carried as is by FEC packets, waiting to be returned.
+	retransmittedPackets []rtp.Packet
+	parseHeader          func(fecPkt rtp.Packet) (flexFec, error)
 }
 
 func newFECDecoder(ssrc uint32, protectedStreamSSRC uint32, loggerFactory logging.LoggerFactory) *fecDecoder {
@@ -44,6 +58,9 @@ func newFECDecoder(ssrc uint32, protectedStreamSSRC uint32, loggerFactory loggin
 		maxFECPackets:       100,
 		recoveredPackets:    make([]rtp.Packet, 0),
 		receivedFECPackets:  make([]fecPacketState, 0),
+		parseHeader: func(fecPkt rtp.Packet) (flexFec, error) {
+			return parseFlexFEC03Header(fecPkt.Payload)
+		},
 	}
 }
 
@@ -61,8 +78,14 @@ func (d *fecDecoder) DecodeFec(receivedPacket rtp.Packet) []rtp.Packet {
 	}
 
 	d.insertPacket(receivedPacket)
+	recoveredPackets := d.attemptRecovery()
+
+	if len(d.retransmittedPackets) > 0 {
+		recoveredPackets = append(d.retransmittedPackets, recoveredPackets...)
+		d.retransmittedPackets = nil
+	}
 
-	return d.attemptRecovery()
+	return recoveredPackets
 }
 
 func (d *fecDecoder) insertPacket(receivedPkt rtp.Packet) {
@@ -96,10 +119,11 @@ func (d *fecDecoder) insertPacket(receivedPkt rtp.Packet) {
 	d.discardOldRecoveredPackets()
 }
 
-func (d *fecDecoder) insertMediaPacket(receivedPkt rtp.Packet) {
+// insertMediaPacket returns false if the packet was already received or recovered.
+func (d *fecDecoder) insertMediaPacket(receivedPkt rtp.Packet) bool {
 	for _, recoveredPacket := range d.recoveredPackets {
 		if recoveredPacket.SequenceNumber == receivedPkt.SequenceNumber {
-			return
+			return false
 		}
 	}
 
@@ -108,6 +132,8 @@ func (d *fecDecoder) insertMediaPacket(receivedPkt rtp.Packet) {
 		return isNewerSeq(d.recoveredPackets[i].SequenceNumber, d.recoveredPackets[j].SequenceNumber)
 	})
 	d.updateCoveringFecPackets(receivedPkt)
+
+	return true
 }
 
 func (d *fecDecoder) updateCoveringFecPackets(receivedPkt rtp.Packet) {
@@ -127,27 +153,28 @@ func (d *fecDecoder) insertFECPacket(fecPkt rtp.Packet) { //nolint:cyclop
 		}
 	}
 
-	fec, err := parseFlexFEC03Header(fecPkt.Payload)
+	fec, err := d.parseHeader(fecPkt)
 	if err != nil {
-		d.logger.Errorf("failed to parse flexfec03 header: %v", err)
+		d.logger.Errorf("failed to parse flexfec header: %v", err)
 
 		return
 	}
 
 	if fec.protectedSSRC != d.protectedStreamSSRC {
-		d.logger.Errorf("fec is protecting unknown ssrc, expected %d, got %d", fec.protectedSSRC, d.protectedStreamSSRC)
+		d.logger.Errorf("fec is protecting unknown ssrc, expected %d, got %d", d.protectedStreamSSRC, fec.protectedSSRC)
 
 		return
 	}
 
-	protectedSeqs := decodeMask(uint64(fec.mask0), 15, fec.seqNumBase)
-	if fec.mask1 != 0 {
-		protectedSeqs = append(protectedSeqs, decodeMask(uint64(fec.mask1), 31, fec.seqNumBase+15)...)
-	}
-	if fec.mask2 != 0 {
-		protectedSeqs = append(protectedSeqs, decodeMask(fec.mask2, 63, fec.seqNumBase+46)...)
+	if fec.retransmission != nil {
+		if d.insertMediaPacket(*fec.retransmission) {
+			d.retransmittedPackets = append(d.retransmittedPackets, *fec.retransmission)
+		}
+
+		return
 	}
 
+	protectedSeqs := fec.protectedSeqs
 	if len(protectedSeqs) == 0 {
 		d.logger.Warn("empty fec packet mask")
 
@@ -169,9 +196,11 @@ func (d *fecDecoder) insertFECPacket(fecPkt rtp.Packet) { //nolint:cyclop
 		case isNewerSeq(d.recoveredPackets[recoveredPacketIt].SequenceNumber, protectedSeqs[protectedSeqIt]):
 			recoveredPacketIt++
 		default:
+			// Copy the packet, recoveredPackets is re-sorted and re-allocated as packets arrive.
+			recovered := d.recoveredPackets[recoveredPacketIt]
 			protectedPackets = append(protectedPackets, &protectedPacket{
 				seq:    protectedSeqs[protectedSeqIt],
-				packet: &d.recoveredPackets[recoveredPacketIt],
+				packet: &recovered,
 			})
 			protectedSeqIt++
 			recoveredPacketIt++
@@ -222,6 +251,8 @@ func (d *fecDecoder) attemptRecovery() []rtp.Packet {
 			recovered, err := d.recoverPacket(&fecPkt) //nolint:gosec
 			if err != nil {
 				d.logger.Errorf("failed to recover packet: %v", err)
+
+				continue
 			}
 
 			recoveredPackets = append(recoveredPackets, recovered)
@@ -329,11 +360,10 @@ type fecPacketState struct {
 
 type flexFec struct {
 	protectedSSRC uint32
-	seqNumBase    uint16
-	mask0         uint16
-	mask1         uint32
-	mask2         uint64
+	protectedSeqs []uint16
 	payload       []byte
+	// retransmission is the source packet carried by a FEC packet with the R bit set.
+	retransmission *rtp.Packet
 }
 
 type protectedPacket struct {
@@ -399,12 +429,17 @@ func parseFlexFEC03Header(data []byte) (flexFec, error) {
 		}
 	}
 
+	protectedSeqs := decodeMask(uint64(maskPart0), 15, seqNumBase)
+	if maskPart1 != 0 {
+		protectedSeqs = append(protectedSeqs, decodeMask(uint64(maskPart1), 31, seqNumBase+15)...)
+	}
+	if maskPart2 != 0 {
+		protectedSeqs = append(protectedSeqs, decodeMask(maskPart2, 63, seqNumBase+46)...)
+	}
+
 	return flexFec{
 		protectedSSRC: protectedSSRC,
-		seqNumBase:    seqNumBase,
-		mask0:         maskPart0,
-		mask1:         maskPart1,
-		mask2:         maskPart2,
+		protectedSeqs: protectedSeqs,
 		payload:       payload,
 	}, nil
 }
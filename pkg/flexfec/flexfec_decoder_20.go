@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package flexfec
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/pion/logging"
+	"github.com/pion/rtp"
+)
+
+// FlexDecoder20Factory is a factory for FlexFEC-20 (RFC 8627) decoders.
+type FlexDecoder20Factory struct{}
+
+// NewDecoder creates new FlexFEC-20 decoder.
+func (f FlexDecoder20Factory) NewDecoder(
+	ssrc uint32, protectedSSRC uint32, loggerFactory logging.LoggerFactory,
+) FlexDecoder {
+	return newFECDecoder20(ssrc, protectedSSRC, loggerFactory)
+}
+
+// newFECDecoder20 creates a decoder sharing the FlexFEC-03 recovery pipeline,
+// parsing FEC packets as defined by RFC 8627.
+func newFECDecoder20(ssrc uint32, protectedStreamSSRC uint32, loggerFactory logging.LoggerFactory) *fecDecoder {
+	decoder := newFECDecoder(ssrc, protectedStreamSSRC, loggerFactory)
+	decoder.parseHeader = parseFlexFEC20Header
+
+	return decoder
+}
+
+// parseFlexFEC20Header parses the FEC header of RFC 8627 repair packets. The protected
+// SSRC is carried in the CSRC list of the packet, or in the header itself for retransmissions.
+// https://datatracker.ietf.org/doc/html/rfc8627#section-4.2.2
+func parseFlexFEC20Header(fecPkt rtp.Packet) (flexFec, error) {
+	data := fecPkt.Payload
+	if len(data) < BaseFecHeaderSize {
+		return flexFec{}, fmt.Errorf("%w: length %d", errPacketTruncated, len(data))
+	}
+
+	rBit := (data[0] & 0x80) != 0
+	fBit := (data[0] & 0x40) != 0
+
+	if rBit {
+		if fBit {
+			return flexFec{}, errInflexibleGeneratorMatrix
+		}
+
+		return parseFlexFEC20Retransmission(data)
+	}
+
+	if len(fecPkt.CSRC) != 1 {
+		return flexFec{}, fmt.Errorf("%w: count %d", errMultipleSSRCProtection, len(fecPkt.CSRC))
+	}
+
+	seqNumBase := binary.BigEndian.Uint16(data[8:10])
+	var (
+		protectedSeqs []uint16
+		payload       []byte
+		err           error
+	)
+	if fBit {
+		protectedSeqs, payload, err = parseFixedMask(data, seqNumBase)
+	} else {
+		protectedSeqs, payload, err = parseFlexibleMask(data, seqNumBase)
+	}
+	if err != nil {
+		return flexFec{}, err
+	}
+
+	return flexFec{
+		protectedSSRC: fecPkt.CSRC[0],
+		protectedSeqs: protectedSeqs,
+		payload:       payload,
+	}, nil
+}
+
+// parseFlexFEC20Retransmission parses a FEC packet with the R bit set, which carries
+// a source packet whose header has its version field replaced by the R and F bits.
+// https://datatracker.ietf.org/doc/html/rfc8627#section-4.2.2.3
+func parseFlexFEC20Retransmission(data []byte) (flexFec, error) {
+	buf := make([]byte, len(data))
+	copy(buf, data)
+
+	// set version to 2
+	buf[0] = (buf[0] & 0x3f) | 0x80
+
+	packet := &rtp.Packet{}
+	if err := packet.Unmarshal(buf); err != nil {
+		return flexFec{}, fmt.Errorf("unmarshal retransmission: %w", err)
+	}
+
+	return flexFec{
+		protectedSSRC:  packet.SSRC,
+		retransmission: packet,
+	}, nil
+}
+
+// parseFlexibleMask parses the variable length mask which follows the SN base.
+// https://datatracker.ietf.org/doc/html/rfc8627#section-4.2.2.1
+func parseFlexibleMask(data []byte, seqNumBase uint16) ([]uint16, []byte, error) {
+	kBit0 := (data[10] & 0x80) != 0
+	maskPart0 := binary.BigEndian.Uint16(data[10:12]) & 0x7FFF
+	protectedSeqs := decodeMask(uint64(maskPart0), 15, seqNumBase)
+	if kBit0 {
+		return protectedSeqs, data[12:], nil
+	}
+
+	if len(data) < 16 {
+		return nil, nil, fmt.Errorf("%w: length %d", errPacketTruncated, len(data))
+	}
+
+	kBit1 := (data[12] & 0x80) != 0
+	maskPart1 := binary.BigEndian.Uint32(data[12:16]) & 0x7FFFFFFF
+	protectedSeqs = append(protectedSeqs, decodeMask(uint64(maskPart1), 31, seqNumBase+15)...)
+	if kBit1 {
+		return protectedSeqs, data[16:], nil
+	}
+
+	if len(data) < 24 {
+		return nil, nil, fmt.Errorf("%w: length %d", errPacketTruncated, len(data))
+	}
+
+	// The last mask has no k bit.
+	maskPart2 := binary.BigEndian.Uint64(data[16:24])
+	protectedSeqs = append(protectedSeqs, decodeMask(maskPart2, 64, seqNumBase+46)...)
+
+	return protectedSeqs, data[24:], nil
+}
+
+// parseFixedMask parses the L and D fields of a fixed mask, which describe a row or
+// column of the source block.
+// https://datatracker.ietf.org/doc/html/rfc8627#section-4.2.2.2
+func parseFixedMask(data []byte, seqNumBase uint16) ([]uint16, []byte, error) {
+	columns := uint16(data[10])
+	rows := uint16(data[11])
+	if columns == 0 {
+		return nil, nil, fmt.Errorf("%w: L %d, D %d", errInvalidFixedMask, columns, rows)
+	}
+
+	var protectedSeqs []uint16
+	if rows <= 1 {
+		// Non-interleaved row FEC protects L consecutive packets.
+		for i := range columns {
+			protectedSeqs = append(protectedSeqs, seqNumBase+i)
+		}
+	} else {
+		// Interleaved column FEC protects D packets spaced by L.
+		for i := range rows {
+			protectedSeqs = append(protectedSeqs, seqNumBase+i*columns)
+		}
+	}
+
+	return protectedSeqs, data[12:], nil
+}
@@ -30,16 +30,31 @@ type FlexEncoder interface {
 	EncodeFec(mediaPackets []rtp.Packet, numFecPackets uint32) []rtp.Packet
 }
 
-// FlexEncoder20 implementation is WIP, contains bugs and no tests. Check out FlexEncoder03.
+// MaskTypeSetter is implemented by FlexEncoders which support changing their MaskType.
+// It is used by the FecInterceptor in adaptive mode.
+type MaskTypeSetter interface {
+	SetMaskType(maskType MaskType)
+}
+
+// FlexEncoder20 implements the Fec encoding mechanism for the flexible mask variant of FlexFEC as
+// defined by RFC 8627.
 type FlexEncoder20 struct {
 	fecBaseSn   uint16
 	payloadType uint8
 	ssrc        uint32
 	coverage    *ProtectionCoverage
+	maskType    MaskType
+}
+
+// FlexEncoder20Factory is a factory for FlexFEC-20 (RFC 8627) encoders.
+type FlexEncoder20Factory struct{}
+
+// NewEncoder creates new FlexFEC-20 encoder.
+func (f FlexEncoder20Factory) NewEncoder(payloadType uint8, ssrc uint32) FlexEncoder {
+	return NewFlexEncoder(payloadType, ssrc)
 }
 
 // NewFlexEncoder returns a new FlexEncoder20.
-// FlexEncoder20 implementation is WIP, contains bugs and no tests. Check out FlexEncoder03.
 func NewFlexEncoder(payloadType uint8, ssrc uint32) *FlexEncoder20 {
 	return &FlexEncoder20{
 		payloadType: payloadType,
@@ -48,14 +63,30 @@ func NewFlexEncoder(payloadType uint8, ssrc uint32) *FlexEncoder20 {
 	}
 }
 
+// SetMaskType sets the MaskType used to protect the following batches of media packets.
+func (flex *FlexEncoder20) SetMaskType(maskType MaskType) {
+	flex.maskType = maskType
+}
+
 // EncodeFec returns a list of generated RTP packets with FEC payloads that protect the specified mediaPackets.
-// This method does not account for missing RTP packets in the mediaPackets array nor does it account for
-// them being passed out of order.
+// This method returns nil in case of missing RTP packets in the mediaPackets array or packets passed out of order.
 func (flex *FlexEncoder20) EncodeFec(mediaPackets []rtp.Packet, numFecPackets uint32) []rtp.Packet {
+	if len(mediaPackets) == 0 {
+		return nil
+	}
+
+	for i := 1; i < len(mediaPackets); i++ {
+		if mediaPackets[i].SequenceNumber != mediaPackets[i-1].SequenceNumber+1 {
+			// Packets are not in order or there are missing packets
+			return nil
+		}
+	}
+
 	// Start by defining which FEC packets cover which media packets
 	if flex.coverage == nil {
-		flex.coverage = NewCoverage(mediaPackets, numFecPackets)
+		flex.coverage = NewCoverageWithMaskType(mediaPackets, numFecPackets, flex.maskType)
 	} else {
+		flex.coverage.SetMaskType(flex.maskType)
 		flex.coverage.UpdateCoverage(mediaPackets, numFecPackets)
 	}
 
@@ -64,24 +95,33 @@ func (flex *FlexEncoder20) EncodeFec(mediaPackets []rtp.Packet, numFecPackets ui
 	}
 
 	// Generate FEC payloads
-	fecPackets := make([]rtp.Packet, numFecPackets)
+	fecPackets := make([]rtp.Packet, 0, numFecPackets)
 	for fecPacketIndex := range numFecPackets {
-		fecPackets[fecPacketIndex] = flex.encodeFlexFecPacket(fecPacketIndex, mediaPackets[0].SequenceNumber)
+		fecPacket, ok := flex.encodeFlexFecPacket(fecPacketIndex, mediaPackets[0].SequenceNumber)
+		if ok {
+			fecPackets = append(fecPackets, fecPacket)
+		}
 	}
 
 	return fecPackets
 }
 
-func (flex *FlexEncoder20) encodeFlexFecPacket(fecPacketIndex uint32, mediaBaseSn uint16) rtp.Packet {
+func (flex *FlexEncoder20) encodeFlexFecPacket(fecPacketIndex uint32, mediaBaseSn uint16) (rtp.Packet, bool) {
 	mediaPacketsIt := flex.coverage.GetCoveredBy(fecPacketIndex)
-	flexFecHeader := flex.encodeFlexFecHeader(
+	if !mediaPacketsIt.HasNext() {
+		return rtp.Packet{}, false
+	}
+
+	flexFecPayload, ok := flex.encodeFlexFecPayload(
 		mediaPacketsIt,
 		flex.coverage.ExtractMask1(fecPacketIndex),
 		flex.coverage.ExtractMask2(fecPacketIndex),
 		flex.coverage.ExtractMask3(fecPacketIndex),
 		mediaBaseSn,
 	)
-	flexFecRepairPayload := flex.encodeFlexFecRepairPayload(mediaPacketsIt.Reset())
+	if !ok {
+		return rtp.Packet{}, false
+	}
 
 	packet := rtp.Packet{
 		Header: rtp.Header{
@@ -93,22 +133,23 @@ func (flex *FlexEncoder20) encodeFlexFecPacket(fecPacketIndex uint32, mediaBaseS
 			SequenceNumber: flex.fecBaseSn,
 			Timestamp:      54243243,
 			SSRC:           flex.ssrc,
-			CSRC:           []uint32{},
+			// The protected SSRC is carried in the CSRC list of the FEC packet.
+			CSRC: []uint32{mediaPacketsIt.First().SSRC},
 		},
-		Payload: append(flexFecHeader, flexFecRepairPayload...),
+		Payload: flexFecPayload,
 	}
 	flex.fecBaseSn++
 
-	return packet
+	return packet, true
 }
 
-func (flex *FlexEncoder20) encodeFlexFecHeader(
+func (flex *FlexEncoder20) encodeFlexFecPayload(
 	mediaPackets *util.MediaPacketIterator,
 	mask1 uint16,
 	optionalMask2 uint32,
 	optionalMask3 uint64,
 	mediaBaseSn uint16,
-) []byte {
+) ([]byte, bool) {
 	/*
 	   0                   1                   2                   3
 	        0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
@@ -132,78 +173,71 @@ func (flex *FlexEncoder20) encodeFlexFecHeader(
 
 	// Get header size - This depends on the size of the bitmask.
 	headerSize := BaseFecHeaderSize
-	if optionalMask2 > 0 {
+	if optionalMask2 > 0 || optionalMask3 > 0 {
 		headerSize += 4
 	}
 	if optionalMask3 > 0 {
 		headerSize += 8
 	}
 
-	// Allocate the FlexFec header
-	flexFecHeader := make([]byte, headerSize)
+	// Find the maximum payload size among all media packets
+	maxPayloadSize := 0
+	for mediaPackets.HasNext() {
+		maxPayloadSize = max(maxPayloadSize, mediaPackets.Next().MarshalSize()-BaseRTPHeaderSize)
+	}
+	mediaPackets.Reset()
+
+	flexFecPayload := make([]byte, headerSize+maxPayloadSize)
+	flexFecHeader := flexFecPayload[:headerSize]
+	flexFecRepairPayload := flexFecPayload[headerSize:]
 
-	// XOR the relevant fields for the header
-	// TO DO - CHECK TO SEE IF THE MARSHALTO() call works with this.
-	tmpMediaPacketBuf := make([]byte, headerSize)
 	for mediaPackets.HasNext() {
 		mediaPacket := mediaPackets.Next()
-		n, err := mediaPacket.MarshalTo(tmpMediaPacketBuf)
-
-		if n == 0 || err != nil {
-			return nil
+		buf, err := mediaPacket.Marshal()
+		if err != nil {
+			return nil, false
 		}
 
 		// XOR the first 2 bytes of the header: V, P, X, CC, M, PT fields
-		flexFecHeader[0] ^= tmpMediaPacketBuf[0]
-		flexFecHeader[1] ^= tmpMediaPacketBuf[1]
+		flexFecHeader[0] ^= buf[0]
+		flexFecHeader[1] ^= buf[1]
 
 		// XOR the length recovery field
-		lengthRecoveryVal := uint16(mediaPacket.MarshalSize() - BaseRTPHeaderSize) //nolint:gosec // G115
-		flexFecHeader[2] ^= uint8(lengthRecoveryVal >> 8)                          //nolint:gosec // G115
-		flexFecHeader[3] ^= uint8(lengthRecoveryVal)                               //nolint:gosec // G115
+		lengthRecoveryVal := uint16(len(buf) - BaseRTPHeaderSize) //nolint:gosec // G115
+		flexFecHeader[2] ^= uint8(lengthRecoveryVal >> 8)         //nolint:gosec // G115
+		flexFecHeader[3] ^= uint8(lengthRecoveryVal)              //nolint:gosec // G115
 
 		// XOR the 5th to 8th bytes of the header: the timestamp field
-		flexFecHeader[4] ^= flexFecHeader[4]
-		flexFecHeader[5] ^= flexFecHeader[5]
-		flexFecHeader[6] ^= flexFecHeader[6]
-		flexFecHeader[7] ^= flexFecHeader[7]
+		for i := 4; i < 8; i++ {
+			flexFecHeader[i] ^= buf[i]
+		}
+
+		// XOR everything following the fixed RTP header: CSRCs, extensions, payload and padding.
+		for byteIndex, b := range buf[BaseRTPHeaderSize:] {
+			flexFecRepairPayload[byteIndex] ^= b
+		}
 	}
 
+	// The R and F bits replace the RTP version, this is a flexible mask FEC packet.
+	flexFecHeader[0] &= 0b00111111
+
 	// Write the base SN for the batch of media packets
 	binary.BigEndian.PutUint16(flexFecHeader[8:10], mediaBaseSn)
 
-	// Write the bitmasks to the header
+	// Write the bitmasks to the header, the k bit is set on the last mask.
 	binary.BigEndian.PutUint16(flexFecHeader[10:12], mask1)
 
-	if optionalMask2 > 0 {
-		binary.BigEndian.PutUint32(flexFecHeader[12:16], optionalMask2)
+	if optionalMask2 == 0 && optionalMask3 == 0 {
 		flexFecHeader[10] |= 0b10000000
-	}
-	if optionalMask3 > 0 {
-		binary.BigEndian.PutUint64(flexFecHeader[16:24], optionalMask3)
-		flexFecHeader[12] |= 0b10000000
-	}
-
-	return flexFecHeader
-}
-
-func (flex *FlexEncoder20) encodeFlexFecRepairPayload(mediaPackets *util.MediaPacketIterator) []byte {
-	flexFecPayload := make([]byte, len(mediaPackets.First().Payload))
+	} else {
+		binary.BigEndian.PutUint32(flexFecHeader[12:16], optionalMask2)
 
-	for mediaPackets.HasNext() {
-		mediaPacketPayload := mediaPackets.Next().Payload
-
-		if len(flexFecPayload) < len(mediaPacketPayload) {
-			// Expected FEC packet payload is bigger that what we can currently store,
-			// we need to resize.
-			flexFecPayloadTmp := make([]byte, len(mediaPacketPayload))
-			copy(flexFecPayloadTmp, flexFecPayload)
-			flexFecPayload = flexFecPayloadTmp
-		}
-		for byteIndex := range mediaPacketPayload {
-			flexFecPayload[byteIndex] ^= mediaPacketPayload[byteIndex]
+		if optionalMask3 == 0 {
+			flexFecHeader[12] |= 0b10000000
+		} else {
+			binary.BigEndian.PutUint64(flexFecHeader[16:24], optionalMask3)
 		}
 	}
 
-	return flexFecPayload
+	return flexFecPayload, true
 }
@@ -35,6 +35,7 @@ type FlexEncoder03 struct {
 	payloadType uint8
 	ssrc        uint32
 	coverage    *ProtectionCoverage
+	maskType    MaskType
 }
 
 // FlexEncoder03Factory is a factory for FlexFEC-03 encoders.
@@ -54,6 +55,11 @@ func NewFlexEncoder03(payloadType uint8, ssrc uint32) *FlexEncoder03 {
 	}
 }
 
+// SetMaskType sets the MaskType used to protect the following batches of media packets.
+func (flex *FlexEncoder03) SetMaskType(maskType MaskType) {
+	flex.maskType = maskType
+}
+
 // EncodeFec returns a list of generated RTP packets with FEC payloads that protect the specified mediaPackets.
 // This method returns nil in case of missing RTP packets in the mediaPackets array or packets passed out of order.
 func (flex *FlexEncoder03) EncodeFec(mediaPackets []rtp.Packet, numFecPackets uint32) []rtp.Packet {
@@ -72,8 +78,9 @@ func (flex *FlexEncoder03) EncodeFec(mediaPackets []rtp.Packet, numFecPackets ui
 
 	// Start by defining which FEC packets cover which media packets
 	if flex.coverage == nil {
-		flex.coverage = NewCoverage(mediaPackets, numFecPackets)
+		flex.coverage = NewCoverageWithMaskType(mediaPackets, numFecPackets, flex.maskType)
 	} else {
+		flex.coverage.SetMaskType(flex.maskType)
 		flex.coverage.UpdateCoverage(mediaPackets, numFecPackets)
 	}
 
@@ -3,6 +3,16 @@
 
 package flexfec
 
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+)
+
+var errInvalidMaxOverhead = errors.New("max FEC overhead must be between 0 and 1")
+
 // FecOption can be used to set initial options on Fec encoder interceptors.
 type FecOption func(d *FecInterceptor) error
 
@@ -32,3 +42,57 @@ func FECEncoderFactory(factory EncoderFactory) FecOption {
 		return nil
 	}
 }
+
+// AdaptiveProtection enables the adaptive mode, where the number of FEC packets generated for
+// each batch of media packets and their MaskType follow the loss reported by the remote peer.
+// NumFECPackets is ignored in adaptive mode.
+func AdaptiveProtection() FecOption {
+	return func(f *FecInterceptor) error {
+		f.adaptive = true
+
+		return nil
+	}
+}
+
+// MaxFECOverhead sets the maximum share of the bitrate that the adaptive mode spends on FEC
+// packets, between 0 and 1. It defaults to 0.5.
+func MaxFECOverhead(maxOverhead float64) FecOption {
+	return func(f *FecInterceptor) error {
+		if maxOverhead < 0 || maxOverhead > 1 {
+			return fmt.Errorf("%w: %v", errInvalidMaxOverhead, maxOverhead)
+		}
+		f.maxOverhead = maxOverhead
+
+		return nil
+	}
+}
+
+// FECEncoderClock sets the clock the bitrate of the media packets is measured with.
+func FECEncoderClock(clock interceptor.Clock) FecOption {
+	return func(f *FecInterceptor) error {
+		f.clock = clock
+
+		return nil
+	}
+}
+
+// FecDecoderOption can be used to set initial options on Fec decoder interceptors.
+type FecDecoderOption func(d *FecDecoderInterceptor) error
+
+// FECDecoderFactory sets the custom factory for constructing the FEC Decoders.
+func FECDecoderFactory(factory DecoderFactory) FecDecoderOption {
+	return func(d *FecDecoderInterceptor) error {
+		d.decoderFactory = factory
+
+		return nil
+	}
+}
+
+// FECDecoderLoggerFactory sets a logger factory for the decoder interceptor.
+func FECDecoderLoggerFactory(loggerFactory logging.LoggerFactory) FecDecoderOption {
+	return func(d *FecDecoderInterceptor) error {
+		d.loggerFactory = loggerFactory
+
+		return nil
+	}
+}
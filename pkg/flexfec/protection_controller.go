@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package flexfec
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pion/interceptor/pkg/rtpfb"
+)
+
+const (
+	// protectionFactor is the number of FEC packets generated per expected lost media packet.
+	protectionFactor = 2.0
+	// minProtectedLossRate is the loss rate below which no FEC packets are generated.
+	minProtectedLossRate = 0.01
+	// burstyLossLength is the average number of consecutive lost packets from which
+	// the loss is considered bursty.
+	burstyLossLength = 1.5
+	// lossSmoothingFactor is the weight of new loss observations in the moving averages.
+	lossSmoothingFactor = 0.3
+)
+
+// protectionController picks the amount of FEC and the MaskType of a stream from
+// the loss observed by the remote peer.
+type protectionController struct {
+	lossRate    float64
+	burstLength float64
+}
+
+func newProtectionController() *protectionController {
+	return &protectionController{
+		// Until the loss pattern is known, protect against bursts like the fixed mode does.
+		burstLength: burstyLossLength,
+	}
+}
+
+func (c *protectionController) updateLossRate(lossRate float64) {
+	c.lossRate += lossSmoothingFactor * (lossRate - c.lossRate)
+}
+
+func (c *protectionController) updateBurstLength(burstLength float64) {
+	c.burstLength += lossSmoothingFactor * (burstLength - c.burstLength)
+}
+
+// onPacketReports updates the loss statistics from the feedback about packets of a single stream.
+func (c *protectionController) onPacketReports(reports []rtpfb.PacketReport) {
+	if len(reports) == 0 {
+		return
+	}
+
+	// Order the packets by the distance between their RTP sequence numbers, which
+	// also holds across a wrap around.
+	sort.Slice(reports, func(i, j int) bool {
+		return int16(reports[i].RTPSequenceNumber-reports[j].RTPSequenceNumber) < 0 //nolint:gosec // G115
+	})
+
+	lost, bursts := 0, 0
+	for i, report := range reports {
+		if report.Arrived {
+			continue
+		}
+		lost++
+		if i == 0 || reports[i-1].Arrived {
+			bursts++
+		}
+	}
+
+	c.updateLossRate(float64(lost) / float64(len(reports)))
+	if bursts > 0 {
+		c.updateBurstLength(float64(lost) / float64(bursts))
+	}
+}
+
+// protection returns the number of FEC packets to generate for numMediaPackets, keeping
+// the share of FEC packets under maxOverhead, and the MaskType to use.
+func (c *protectionController) protection(numMediaPackets uint32, maxOverhead float64) (uint32, MaskType) {
+	if c.lossRate < minProtectedLossRate {
+		return 0, MaskBursty
+	}
+
+	numFecPackets := math.Ceil(float64(numMediaPackets) * c.lossRate * protectionFactor)
+	if maxOverhead < 1 {
+		numFecPackets = min(numFecPackets, math.Floor(maxOverhead*float64(numMediaPackets)/(1-maxOverhead)))
+	}
+	numFecPackets = max(0, min(numFecPackets, float64(numMediaPackets)))
+
+	maskType := MaskBursty
+	if c.burstLength < burstyLossLength {
+		maskType = MaskRandom
+	}
+
+	return uint32(numFecPackets), maskType
+}
+
+// overhead returns the share of the bitrate currently spent on FEC.
+func (c *protectionController) overhead(numMediaPackets uint32, maxOverhead float64) float64 {
+	numFecPackets, _ := c.protection(numMediaPackets, maxOverhead)
+	if numFecPackets == 0 {
+		return 0
+	}
+
+	return float64(numFecPackets) / float64(numMediaPackets+numFecPackets)
+}
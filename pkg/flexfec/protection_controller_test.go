@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package flexfec
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/pion/interceptor/pkg/rtpfb"
+	"github.com/stretchr/testify/assert"
+)
+
+func feedbackWithLoss(lost ...bool) []rtpfb.PacketReport {
+	return feedbackWithLossFrom(0, lost...)
+}
+
+func feedbackWithLossFrom(first uint16, lost ...bool) []rtpfb.PacketReport {
+	reports := make([]rtpfb.PacketReport, 0, len(lost))
+	for i, isLost := range lost {
+		reports = append(reports, rtpfb.PacketReport{
+			SequenceNumber:    uint64(i),         //nolint:gosec // G115
+			RTPSequenceNumber: first + uint16(i), //nolint:gosec // G115
+			Arrived:           !isLost,
+		})
+	}
+
+	return reports
+}
+
+func TestProtectionController_NoLoss(t *testing.T) {
+	controller := newProtectionController()
+	for range 10 {
+		controller.updateLossRate(0)
+	}
+
+	numFecPackets, _ := controller.protection(10, 0.5)
+	assert.Equal(t, uint32(0), numFecPackets)
+	assert.Equal(t, 0.0, controller.overhead(10, 0.5))
+}
+
+func TestProtectionController_ProtectionFollowsLoss(t *testing.T) {
+	controller := newProtectionController()
+	for range 50 {
+		controller.updateLossRate(0.1)
+	}
+
+	numFecPackets, maskType := controller.protection(10, 0.5)
+	assert.Equal(t, uint32(2), numFecPackets)
+	assert.Equal(t, MaskBursty, maskType, "loss pattern is unknown")
+	assert.InDelta(t, 2.0/12.0, controller.overhead(10, 0.5), 1e-9)
+
+	for range 50 {
+		controller.updateLossRate(0.4)
+	}
+	numFecPackets, _ = controller.protection(10, 0.5)
+	assert.Equal(t, uint32(8), numFecPackets)
+
+	// The overhead is capped, 2 FEC packets for 10 media packets is 1/6 of the bitrate.
+	numFecPackets, _ = controller.protection(10, 0.2)
+	assert.Equal(t, uint32(2), numFecPackets)
+
+	numFecPackets, _ = controller.protection(10, 1)
+	assert.Equal(t, uint32(8), numFecPackets)
+}
+
+func TestProtectionController_MaskTypeFollowsLossPattern(t *testing.T) {
+	controller := newProtectionController()
+
+	// Isolated losses.
+	for range 10 {
+		controller.onPacketReports(feedbackWithLoss(false, true, false, false, true, false, false, false, false, false))
+	}
+	numFecPackets, maskType := controller.protection(10, 0.5)
+	assert.Equal(t, uint32(4), numFecPackets)
+	assert.Equal(t, MaskRandom, maskType)
+
+	// Bursts of 4 lost packets.
+	for range 10 {
+		controller.onPacketReports(feedbackWithLoss(false, true, true, true, true, false, false, false, false, false))
+	}
+	_, maskType = controller.protection(10, 0.5)
+	assert.Equal(t, MaskBursty, maskType)
+}
+
+func TestProtectionController_SequenceNumberWrapAround(t *testing.T) {
+	controller := newProtectionController()
+
+	// Bursts of 2 lost packets, 65535 and 0, reported in reverse order.
+	for range 10 {
+		reports := feedbackWithLossFrom(65531, false, false, false, false, true, true, false, false, false, false)
+		slices.Reverse(reports)
+		controller.onPacketReports(reports)
+	}
+	_, maskType := controller.protection(10, 0.5)
+	assert.Equal(t, MaskBursty, maskType)
+	assert.InDelta(t, 2, controller.burstLength, 0.1)
+}
@@ -14,6 +14,12 @@ const (
 
 type adaptiveThresholdOption func(*adaptiveThreshold)
 
+func setThresholdNow(nowFn now) adaptiveThresholdOption {
+	return func(at *adaptiveThreshold) {
+		at.now = nowFn
+	}
+}
+
 func setInitialThreshold(t time.Duration) adaptiveThresholdOption {
 	return func(at *adaptiveThreshold) {
 		at.thresh = t
@@ -33,6 +39,7 @@ func setInitialThreshold(t time.Duration) adaptiveThresholdOption {
 // Communication (WebRTC)](https://c3lab.poliba.it/images/6/65/Gcc-analysis.pdf)
 // for a more detailed description.
 type adaptiveThreshold struct {
+	now                    now
 	thresh                 time.Duration
 	overuseCoefficientUp   float64
 	overuseCoefficientDown float64
@@ -46,6 +53,7 @@ type adaptiveThreshold struct {
 // values taken from draft-ietf-rmcat-gcc-02.
 func newAdaptiveThreshold(opts ...adaptiveThresholdOption) *adaptiveThreshold {
 	at := &adaptiveThreshold{
+		now:                    time.Now,
 		thresh:                 time.Duration(12500 * float64(time.Microsecond)),
 		overuseCoefficientUp:   0.01,
 		overuseCoefficientDown: 0.00018,
@@ -80,7 +88,7 @@ func (a *adaptiveThreshold) compare(estimate, _ time.Duration) (usage, time.Dura
 }
 
 func (a *adaptiveThreshold) update(estimate time.Duration) {
-	now := time.Now()
+	now := a.now()
 	if a.lastUpdate.IsZero() {
 		a.lastUpdate = now
 	}
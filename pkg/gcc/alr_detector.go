@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"time"
+)
+
+const (
+	// alrBandwidthUsage is the share of the target bitrate the budget of the ALR
+	// detector grows with.
+	alrBandwidthUsage = 0.65
+	// alrWindow is the duration of unused budget the ALR detector accumulates at most.
+	alrWindow = 500 * time.Millisecond
+	// alrStartRatio is the share of unused budget from which the sender is application limited.
+	alrStartRatio = 0.8
+	// alrStopRatio is the share of unused budget under which the sender is not
+	// application limited anymore.
+	alrStopRatio = 0.5
+	// alrRateWindow is the window the send bitrate is measured over.
+	alrRateWindow = time.Second
+)
+
+// ALRStats contains the state of the application limited region (ALR) detection.
+type ALRStats struct {
+	// InALR is true while the sender sends less than the target bitrate allows.
+	InALR bool
+	// ALRStart is the start of the current application limited region.
+	ALRStart time.Time
+	// SendBitrate is the bitrate the packets left the pacer at over the last second.
+	SendBitrate int
+}
+
+type alrSample struct {
+	sent time.Time
+	size int
+}
+
+// alrDetector detects application limited regions (ALR), in which the sender
+// sends less than the target bitrate allows. It accumulates a budget growing with
+// a share of the target bitrate, and spent by the packets actually sent.
+type alrDetector struct {
+	bitrate    int
+	budget     float64
+	lastUpdate time.Time
+	inALR      bool
+	alrStart   time.Time
+	samples    []alrSample
+	sentBytes  int
+}
+
+func newALRDetector(bitrate int) *alrDetector {
+	return &alrDetector{bitrate: bitrate}
+}
+
+func (d *alrDetector) maxBudget() float64 {
+	return alrBandwidthUsage * float64(d.bitrate) / 8 * alrWindow.Seconds()
+}
+
+func (d *alrDetector) setBitrate(bitrate int) {
+	d.bitrate = bitrate
+	d.budget = min(d.budget, d.maxBudget())
+}
+
+// update grows the budget with the time elapsed since the last update.
+func (d *alrDetector) update(now time.Time) {
+	if d.lastUpdate.IsZero() {
+		d.lastUpdate = now
+	}
+	if elapsed := now.Sub(d.lastUpdate); elapsed > 0 {
+		d.budget = min(d.budget+alrBandwidthUsage*float64(d.bitrate)/8*elapsed.Seconds(), d.maxBudget())
+		d.lastUpdate = now
+	}
+
+	ratio := 0.0
+	if maxBudget := d.maxBudget(); maxBudget > 0 {
+		ratio = d.budget / maxBudget
+	}
+	switch {
+	case !d.inALR && ratio > alrStartRatio:
+		d.inALR = true
+		d.alrStart = now
+	case d.inALR && ratio < alrStopRatio:
+		d.inALR = false
+	}
+}
+
+// onSent spends the budget for a packet of size bytes sent at now.
+func (d *alrDetector) onSent(now time.Time, size int) {
+	d.update(now)
+	d.budget = max(d.budget-float64(size), -d.maxBudget())
+	d.update(now)
+
+	d.samples = append(d.samples, alrSample{sent: now, size: size})
+	d.sentBytes += size
+}
+
+// sendBitrate returns the bitrate the packets were sent at over the last alrRateWindow.
+func (d *alrDetector) sendBitrate(now time.Time) int {
+	expired := 0
+	for _, sample := range d.samples {
+		if now.Sub(sample.sent) < alrRateWindow {
+			break
+		}
+		d.sentBytes -= sample.size
+		expired++
+	}
+	d.samples = d.samples[expired:]
+
+	return int(float64(d.sentBytes) * 8 / alrRateWindow.Seconds())
+}
+
+// stats returns the state of the detector at now.
+func (d *alrDetector) stats(now time.Time) ALRStats {
+	d.update(now)
+
+	stats := ALRStats{InALR: d.inALR, SendBitrate: d.sendBitrate(now)}
+	if d.inALR {
+		stats.ALRStart = d.alrStart
+	}
+
+	return stats
+}
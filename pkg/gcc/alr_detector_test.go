@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestALRDetector(t *testing.T) {
+	detector := newALRDetector(1_000_000)
+	start := time.Time{}.Add(time.Hour)
+	packetSize := 1_000_000 / 8 / 100
+
+	// Sending at the target bitrate.
+	now := start
+	for range 100 {
+		detector.onSent(now, packetSize)
+		now = now.Add(10 * time.Millisecond)
+	}
+	stats := detector.stats(now)
+	assert.False(t, stats.InALR)
+	assert.InDelta(t, 1_000_000, stats.SendBitrate, 10_000)
+
+	// Sending at a fifth of the target bitrate.
+	for range 200 {
+		detector.onSent(now, packetSize/5)
+		now = now.Add(10 * time.Millisecond)
+	}
+	stats = detector.stats(now)
+	assert.True(t, stats.InALR)
+	assert.True(t, stats.ALRStart.After(start.Add(time.Second)))
+	assert.True(t, stats.ALRStart.Before(now))
+	assert.InDelta(t, 1_000_000/5, stats.SendBitrate, 2_000)
+
+	// The target bitrate drops to the sent bitrate.
+	detector.setBitrate(1_000_000 / 5)
+	for range 100 {
+		detector.onSent(now, packetSize/5)
+		now = now.Add(10 * time.Millisecond)
+	}
+	stats = detector.stats(now)
+	assert.False(t, stats.InALR)
+	assert.True(t, stats.ALRStart.IsZero())
+}
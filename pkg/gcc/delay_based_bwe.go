@@ -71,7 +71,12 @@ func newDelayController(delayConfig delayControllerConfig, loggerFactory logging
 		},
 	)
 	delayController.rateController = rateController
-	overuseDetector := newOveruseDetector(newAdaptiveThreshold(), 10*time.Millisecond, rateController.onDelayStats)
+	overuseDetector := newOveruseDetector(
+		delayConfig.nowFn,
+		newAdaptiveThreshold(setThresholdNow(delayConfig.nowFn)),
+		10*time.Millisecond,
+		rateController.onDelayStats,
+	)
 	slopeEstimator := newSlopeEstimator(newKalman(), overuseDetector.onDelayStats)
 	arrivalGroupAccumulator := newArrivalGroupAccumulator()
 
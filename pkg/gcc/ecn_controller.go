@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"time"
+
+	"github.com/pion/interceptor/internal/cc"
+	"github.com/pion/rtcp"
+)
+
+const (
+	// ecnAlphaGain is the gain of the moving average of the CE marked fraction, as
+	// in TCP Prague.
+	ecnAlphaGain = 1.0 / 16
+	// minECNWindow is the shortest window the CE marks are counted over.
+	minECNWindow = 100 * time.Millisecond
+)
+
+// ECNStats contains the state of the response to ECN congestion experienced (CE) marks.
+type ECNStats struct {
+	// MarkedRatio is the fraction of the ECN capable packets received CE marked in
+	// the last window.
+	MarkedRatio float64
+	// Alpha is the moving average of the CE marked fraction. The target bitrate is
+	// reduced by half of it at the end of each window with CE marks.
+	Alpha float64
+}
+
+// ecnController implements a scalable congestion response to CE marks, as used
+// by TCP Prague for L4S. The marks are counted over windows of a round trip
+// time, and at the end of each window with marks the target bitrate is reduced
+// in proportion to the moving average of the marked fraction. A few marks
+// cause a small reduction, a fully marked window halves the target bitrate.
+type ecnController struct {
+	alpha       float64
+	markedRatio float64
+	windowStart time.Time
+	capable     int
+	marked      int
+}
+
+func newECNController() *ecnController {
+	// As in DCTCP, alpha starts at 1 so that the first marks halve the bitrate.
+	return &ecnController{alpha: 1}
+}
+
+// onAcks counts the CE marks of the acknowledgments of a feedback received at now,
+// and returns the factor the target bitrate must be reduced by. It returns 1 until
+// a window with CE marks ends.
+func (c *ecnController) onAcks(now time.Time, acks []cc.Acknowledgment, rtt time.Duration) float64 {
+	for _, ack := range acks {
+		if ack.Arrival.IsZero() || ack.ECN == rtcp.ECNNonECT {
+			continue
+		}
+		c.capable++
+		if ack.ECN == rtcp.ECNCE {
+			c.marked++
+		}
+	}
+	if c.windowStart.IsZero() {
+		c.windowStart = now
+	}
+	if now.Sub(c.windowStart) < max(rtt, minECNWindow) {
+		return 1
+	}
+
+	capable, marked := c.capable, c.marked
+	c.windowStart = now
+	c.capable = 0
+	c.marked = 0
+	if capable == 0 {
+		return 1
+	}
+
+	c.markedRatio = float64(marked) / float64(capable)
+	c.alpha += ecnAlphaGain * (c.markedRatio - c.alpha)
+	if marked == 0 {
+		return 1
+	}
+
+	return 1 - c.alpha/2
+}
+
+func (c *ecnController) stats() ECNStats {
+	return ECNStats{MarkedRatio: c.markedRatio, Alpha: c.alpha}
+}
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor/internal/cc"
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func ecnAcks(n, marked int, ecn rtcp.ECN) []cc.Acknowledgment {
+	acks := make([]cc.Acknowledgment, n)
+	for i := range acks {
+		acks[i] = cc.Acknowledgment{Departure: time.Now(), Arrival: time.Now(), ECN: ecn}
+		if i < marked {
+			acks[i].ECN = rtcp.ECNCE
+		}
+	}
+
+	return acks
+}
+
+func TestECNController(t *testing.T) {
+	c := newECNController()
+	now := time.Now()
+
+	// Marks are counted until the window of a round trip time ends.
+	assert.Equal(t, 1.0, c.onAcks(now, ecnAcks(10, 1, rtcp.ECNECT1), 200*time.Millisecond))
+	assert.Equal(t, 1.0, c.onAcks(now.Add(150*time.Millisecond), ecnAcks(10, 1, rtcp.ECNECT1), 200*time.Millisecond))
+	factor := c.onAcks(now.Add(200*time.Millisecond), nil, 200*time.Millisecond)
+	alpha := 1 + ecnAlphaGain*(0.1-1)
+	assert.InDelta(t, 1-alpha/2, factor, 1e-9)
+	assert.InDelta(t, 0.1, c.stats().MarkedRatio, 1e-9)
+	assert.InDelta(t, alpha, c.stats().Alpha, 1e-9)
+
+	// Windows without marks do not reduce the bitrate, but decay alpha.
+	now = now.Add(200 * time.Millisecond)
+	assert.Equal(t, 1.0, c.onAcks(now, ecnAcks(10, 0, rtcp.ECNECT1), 0))
+	now = now.Add(minECNWindow)
+	assert.Equal(t, 1.0, c.onAcks(now, nil, 0))
+	assert.Less(t, c.stats().Alpha, alpha)
+
+	// Packets which are not ECN capable are ignored.
+	now = now.Add(minECNWindow)
+	assert.Equal(t, 1.0, c.onAcks(now, ecnAcks(10, 0, rtcp.ECNNonECT), 0))
+	now = now.Add(minECNWindow)
+	assert.Equal(t, 1.0, c.onAcks(now, nil, 0))
+	assert.Equal(t, 0.0, c.stats().MarkedRatio)
+}
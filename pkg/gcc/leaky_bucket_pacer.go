@@ -6,10 +6,13 @@ package gcc
 import (
 	"container/list"
 	"errors"
+	"maps"
 	"sync"
 	"time"
 
 	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/cc"
+	"github.com/pion/interceptor/pkg/pacing"
 	"github.com/pion/logging"
 	"github.com/pion/rtp"
 )
@@ -21,11 +24,25 @@ type item struct {
 	payload    *[]byte
 	size       int
 	attributes interceptor.Attributes
+	class      pacing.Class
+	enqueued   time.Time
 }
 
 // LeakyBucketPacer implements a leaky bucket pacing algorithm.
+//
+// Packets are queued per pacing.Class, and sent from the queue with the highest
+// priority first, so that audio and retransmissions preempt a burst of video
+// packets. The class of the packets of streams added with AddStream only
+// depends on their attributes, use AddStreamInfo to classify them by media
+// kind.
+//
+// Probe clusters added with Probe are sent at their bitrate, padded with RTX
+// packets on the first stream added with AddStreamInfo which has a RTX stream.
+// The pacer numbers the packets of the RTX streams, as both the retransmissions
+// and the padding are sent on them.
 type LeakyBucketPacer struct {
-	log logging.LeveledLogger
+	log   logging.LeveledLogger
+	clock interceptor.Clock
 
 	f                 float64
 	targetBitrate     int
@@ -33,32 +50,69 @@ type LeakyBucketPacer struct {
 
 	pacingInterval time.Duration
 
-	qLock sync.RWMutex
-	queue *list.List
-	done  chan struct{}
+	qLock  sync.RWMutex
+	queues map[pacing.Class]*list.List
+	stats  map[pacing.Class]pacing.QueueStats
+	done   chan struct{}
 
 	ssrcToWriter map[uint32]interceptor.RTPWriter
+	ssrcToInfo   map[uint32]*interceptor.StreamInfo
+	rtxStreams   []*interceptor.StreamInfo
 	writerLock   sync.RWMutex
 
+	probeLock   sync.Mutex
+	probes      []*probeCluster
+	nextProbeID int
+
+	// Only accessed by Run.
+	rtxSequenceNumbers map[uint32]uint16
+	paddingHistory     []*rtp.Packet
+	paddingHistoryNext int
+	paddingIndex       int
+	lastTimestamp      uint32
+
 	pool *sync.Pool
 }
 
+// LeakyBucketPacerOption configures a LeakyBucketPacer.
+type LeakyBucketPacerOption func(*LeakyBucketPacer)
+
+// LeakyBucketPacerClock sets the clock the packets are paced by.
+func LeakyBucketPacerClock(clock interceptor.Clock) LeakyBucketPacerOption {
+	return func(p *LeakyBucketPacer) {
+		p.clock = clock
+	}
+}
+
 // NewLeakyBucketPacer initializes a new LeakyBucketPacer.
-func NewLeakyBucketPacer(initialBitrate int) *LeakyBucketPacer {
-	return newLeakyBucketPacer(initialBitrate, logging.NewDefaultLoggerFactory())
+func NewLeakyBucketPacer(initialBitrate int, opts ...LeakyBucketPacerOption) *LeakyBucketPacer {
+	return newLeakyBucketPacer(initialBitrate, logging.NewDefaultLoggerFactory(), opts...)
 }
 
-func newLeakyBucketPacer(initialBitrate int, loggerFactory logging.LoggerFactory) *LeakyBucketPacer {
+func newLeakyBucketPacer(
+	initialBitrate int, loggerFactory logging.LoggerFactory, opts ...LeakyBucketPacerOption,
+) *LeakyBucketPacer {
 	pacer := &LeakyBucketPacer{
 		log:            loggerFactory.NewLogger("pacer"),
+		clock:          interceptor.SystemClock(),
 		f:              1.5,
 		targetBitrate:  initialBitrate,
 		pacingInterval: 5 * time.Millisecond,
 		qLock:          sync.RWMutex{},
-		queue:          list.New(),
+		queues:         map[pacing.Class]*list.List{},
+		stats:          map[pacing.Class]pacing.QueueStats{},
 		done:           make(chan struct{}),
 		ssrcToWriter:   map[uint32]interceptor.RTPWriter{},
+		ssrcToInfo:     map[uint32]*interceptor.StreamInfo{},
 		pool:           &sync.Pool{},
+
+		rtxSequenceNumbers: map[uint32]uint16{},
+	}
+	for _, opt := range opts {
+		opt(pacer)
+	}
+	for _, class := range pacing.Classes() {
+		pacer.queues[class] = list.New()
 	}
 	pacer.pool = &sync.Pool{
 		New: func() any {
@@ -80,6 +134,33 @@ func (p *LeakyBucketPacer) AddStream(ssrc uint32, writer interceptor.RTPWriter)
 	p.ssrcToWriter[ssrc] = writer
 }
 
+// AddStreamInfo adds a new stream and its corresponding writer to the pacer. The
+// writer is also used for the RTX and FEC streams of the stream, and its packets
+// are classified by the media kind of the stream.
+func (p *LeakyBucketPacer) AddStreamInfo(info *interceptor.StreamInfo, writer interceptor.RTPWriter) {
+	p.writerLock.Lock()
+	defer p.writerLock.Unlock()
+
+	for _, ssrc := range []uint32{info.SSRC, info.SSRCRetransmission, info.SSRCForwardErrorCorrection} {
+		if ssrc == 0 {
+			continue
+		}
+		p.ssrcToWriter[ssrc] = writer
+		p.ssrcToInfo[ssrc] = info
+	}
+	if info.SSRCRetransmission != 0 && pacing.Classify(info, &rtp.Header{SSRC: info.SSRC}, nil) == pacing.ClassVideo {
+		p.rtxStreams = append(p.rtxStreams, info)
+	}
+}
+
+// QueueStats returns the queueing statistics per priority class of the pacer.
+func (p *LeakyBucketPacer) QueueStats() map[pacing.Class]pacing.QueueStats {
+	p.qLock.RLock()
+	defer p.qLock.RUnlock()
+
+	return maps.Clone(p.stats)
+}
+
 // SetTargetBitrate updates the target bitrate at which the pacer is allowed to
 // send packets. The pacer may exceed this limit by p.f.
 func (p *LeakyBucketPacer) SetTargetBitrate(rate int) {
@@ -106,13 +187,22 @@ func (p *LeakyBucketPacer) Write(header *rtp.Header, payload []byte, attributes
 	copy(*buf, payload)
 	hdr := header.Clone()
 
+	p.writerLock.RLock()
+	class := pacing.Classify(p.ssrcToInfo[header.SSRC], header, attributes)
+	p.writerLock.RUnlock()
+
 	p.qLock.Lock()
-	p.queue.PushBack(&item{
+	p.queues[class].PushBack(&item{
 		header:     &hdr,
 		payload:    buf,
 		size:       len(payload),
 		attributes: attributes,
+		class:      class,
+		enqueued:   p.clock.Now(),
 	})
+	stats := p.stats[class]
+	stats.Queued++
+	p.stats[class] = stats
 	p.qLock.Unlock()
 
 	return header.MarshalSize() + len(payload), nil
@@ -120,23 +210,28 @@ func (p *LeakyBucketPacer) Write(header *rtp.Header, payload []byte, attributes
 
 // Run starts the LeakyBucketPacer.
 func (p *LeakyBucketPacer) Run() {
-	ticker := time.NewTicker(p.pacingInterval)
+	ticker := p.clock.NewTicker(p.pacingInterval)
 	defer ticker.Stop()
 
-	lastSent := time.Now()
+	lastSent := p.clock.Now()
 	for {
 		select {
 		case <-p.done:
 			return
-		case now := <-ticker.C:
+		case now := <-ticker.Ch():
 			budget := int(float64(now.Sub(lastSent).Milliseconds()) * float64(p.getTargetBitrate()) / 8000.0)
+			probe := p.currentProbe(now)
+			if probe != nil {
+				budget = max(budget, probe.budget(now, p.pacingInterval))
+			}
 			p.qLock.Lock()
-			for p.queue.Len() != 0 && budget > 0 {
-				p.log.Infof("budget=%v, len(queue)=%v, targetBitrate=%v", budget, p.queue.Len(), p.getTargetBitrate())
-				next, ok := p.queue.Remove(p.queue.Front()).(*item)
+			for p.queueLen() != 0 && budget > 0 {
+				p.log.Infof("budget=%v, len(queue)=%v, targetBitrate=%v", budget, p.queueLen(), p.getTargetBitrate())
+				next, ok := p.pop(now)
 				p.qLock.Unlock()
 				if !ok {
 					p.log.Warnf("failed to access leaky bucket pacer queue, cast failed")
+					p.qLock.Lock()
 
 					continue
 				}
@@ -152,21 +247,70 @@ func (p *LeakyBucketPacer) Run() {
 					continue
 				}
 
-				n, err := writer.Write(next.header, (*next.payload)[:next.size], next.attributes)
+				payload := (*next.payload)[:next.size]
+				p.onMediaSent(next.header, payload, next.class)
+				attributes := next.attributes
+				if probe != nil {
+					attributes = maps.Clone(attributes)
+					if attributes == nil {
+						attributes = make(interceptor.Attributes)
+					}
+					attributes.Set(cc.ProbeClusterAttributesKey, probe.id)
+				}
+				n, err := writer.Write(next.header, payload, attributes)
 				if err != nil {
 					p.log.Errorf("failed to write packet: %v", err)
 				}
 				lastSent = now
 				budget -= n
+				if probe != nil {
+					probe.onSent(next.header.MarshalSize() + next.size)
+				}
 
 				p.pool.Put(next.payload)
 				p.qLock.Lock()
 			}
 			p.qLock.Unlock()
+
+			if probe != nil {
+				p.pad(probe, now)
+				p.finishProbe(probe, now)
+			}
 		}
 	}
 }
 
+func (p *LeakyBucketPacer) queueLen() int {
+	n := 0
+	for _, queue := range p.queues {
+		n += queue.Len()
+	}
+
+	return n
+}
+
+// pop removes the oldest packet of the class with the highest priority from the queues.
+func (p *LeakyBucketPacer) pop(now time.Time) (*item, bool) {
+	for _, class := range pacing.Classes() {
+		queue := p.queues[class]
+		if queue.Len() == 0 {
+			continue
+		}
+		next, ok := queue.Remove(queue.Front()).(*item)
+		if !ok {
+			return nil, false
+		}
+		stats := p.stats[class]
+		stats.Queued--
+		stats.OnSent(next.header.MarshalSize()+next.size, max(now.Sub(next.enqueued), 0))
+		p.stats[class] = stats
+
+		return next, true
+	}
+
+	return nil, false
+}
+
 // Close closes the LeakyBucketPacer.
 func (p *LeakyBucketPacer) Close() error {
 	close(p.done)
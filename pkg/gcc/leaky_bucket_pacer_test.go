@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/cc"
+	"github.com/pion/interceptor/pkg/pacing"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeakyBucketPacer_Priority(t *testing.T) {
+	pacer := NewLeakyBucketPacer(0)
+	defer func() {
+		assert.NoError(t, pacer.Close())
+	}()
+
+	// The RTX sequence numbers are rewritten by the pacer, packets are identified by their payload.
+	written := make(chan byte, 10)
+	record := interceptor.RTPWriterFunc(func(h *rtp.Header, payload []byte, _ interceptor.Attributes) (int, error) {
+		written <- payload[0]
+
+		return h.MarshalSize() + len(payload), nil
+	})
+	pacer.AddStreamInfo(&interceptor.StreamInfo{
+		SSRC:                       1,
+		SSRCRetransmission:         2,
+		SSRCForwardErrorCorrection: 3,
+		MimeType:                   "video/VP8",
+	}, record)
+	pacer.AddStreamInfo(&interceptor.StreamInfo{SSRC: 4, MimeType: "audio/opus"}, record)
+
+	for _, p := range []struct {
+		ssrc uint32
+		id   byte
+		attr interceptor.Attributes
+	}{
+		{1, 1, nil},
+		{1, 2, nil},
+		{3, 3, nil},
+		{1, 4, interceptor.Attributes{interceptor.RetransmittedAttributesKey: true}},
+		{2, 5, nil},
+		{4, 6, nil},
+	} {
+		payload := make([]byte, 100)
+		payload[0] = p.id
+		_, err := pacer.Write(&rtp.Header{SSRC: p.ssrc}, payload, p.attr)
+		assert.NoError(t, err)
+	}
+	stats := pacer.QueueStats()
+	assert.Equal(t, 2, stats[pacing.ClassVideo].Queued)
+	assert.Equal(t, 2, stats[pacing.ClassRetransmission].Queued)
+
+	pacer.SetTargetBitrate(10_000_000)
+	for _, id := range []byte{6, 4, 5, 1, 2, 3} {
+		select {
+		case got := <-written:
+			assert.Equal(t, id, got)
+		case <-time.After(time.Second):
+			assert.FailNow(t, "no RTP packet written")
+		}
+	}
+
+	stats = pacer.QueueStats()
+	for _, class := range pacing.Classes() {
+		assert.Equal(t, 0, stats[class].Queued)
+	}
+	assert.Equal(t, uint64(2), stats[pacing.ClassVideo].Sent)
+	assert.Equal(t, uint64(1), stats[pacing.ClassAudio].Sent)
+}
+
+func TestLeakyBucketPacer_Probe(t *testing.T) {
+	type writtenPacket struct {
+		header       rtp.Header
+		payload      []byte
+		probeCluster any
+	}
+
+	pacer := NewLeakyBucketPacer(0)
+	defer func() {
+		assert.NoError(t, pacer.Close())
+	}()
+
+	written := make(chan writtenPacket, 100)
+	record := interceptor.RTPWriterFunc(func(h *rtp.Header, payload []byte, a interceptor.Attributes) (int, error) {
+		written <- writtenPacket{h.Clone(), append([]byte{}, payload...), a.Get(cc.ProbeClusterAttributesKey)}
+
+		return h.MarshalSize() + len(payload), nil
+	})
+	pacer.AddStreamInfo(&interceptor.StreamInfo{
+		SSRC:                      1,
+		SSRCRetransmission:        2,
+		PayloadType:               96,
+		PayloadTypeRetransmission: 97,
+		MimeType:                  "video/VP8",
+	}, record)
+
+	receive := func(t *testing.T, bitrate int) []writtenPacket {
+		t.Helper()
+
+		var packets []writtenPacket
+		size := 0
+		for size < bitrate/8*int(probeDuration)/int(time.Second) {
+			select {
+			case p := <-written:
+				packets = append(packets, p)
+				size += p.header.MarshalSize() + len(p.payload) + int(p.header.PaddingSize)
+			case <-time.After(time.Second):
+				assert.FailNow(t, "probe cluster not sent")
+			}
+		}
+
+		return packets
+	}
+
+	// Without media packets, the probe cluster is made of padding only packets.
+	id := pacer.Probe(1_000_000)
+	packets := receive(t, 1_000_000)
+	assert.GreaterOrEqual(t, len(packets), minProbePackets)
+	for i, p := range packets {
+		assert.Equal(t, id, p.probeCluster)
+		assert.Equal(t, uint32(2), p.header.SSRC)
+		assert.Equal(t, uint8(97), p.header.PayloadType)
+		assert.Equal(t, uint16(i), p.header.SequenceNumber) //nolint:gosec // G115
+		assert.Equal(t, uint8(maxPaddingSize), p.header.PaddingSize)
+		assert.Empty(t, p.payload)
+	}
+
+	// The media packets are sent in the probe cluster, and resent as padding.
+	_, err := pacer.Write(&rtp.Header{SSRC: 1, PayloadType: 96, SequenceNumber: 100}, []byte{1, 2, 3}, nil)
+	assert.NoError(t, err)
+	nextID := pacer.Probe(500_000)
+	assert.Equal(t, id+1, nextID)
+	packets = receive(t, 500_000)
+	assert.Equal(t, uint32(1), packets[0].header.SSRC)
+	assert.Equal(t, nextID, packets[0].probeCluster)
+	for _, p := range packets[1:] {
+		assert.Equal(t, nextID, p.probeCluster)
+		assert.Equal(t, uint32(2), p.header.SSRC)
+		assert.Equal(t, []byte{0, 100, 1, 2, 3}, p.payload)
+	}
+}
@@ -31,6 +31,7 @@ type LossStats struct {
 }
 
 type lossBasedBandwidthEstimator struct {
+	now            now
 	lock           sync.Mutex
 	maxBitrate     int
 	minBitrate     int
@@ -42,8 +43,11 @@ type lossBasedBandwidthEstimator struct {
 	log            logging.LeveledLogger
 }
 
-func newLossBasedBWE(initialBitrate int, loggerFactory logging.LoggerFactory) *lossBasedBandwidthEstimator {
+func newLossBasedBWE(
+	nowFn now, initialBitrate int, loggerFactory logging.LoggerFactory,
+) *lossBasedBandwidthEstimator {
 	return &lossBasedBandwidthEstimator{
+		now:            nowFn,
 		lock:           sync.Mutex{},
 		maxBitrate:     100_000_000, // 100 mbit
 		minBitrate:     100_000,     // 100 kbit
@@ -86,30 +90,53 @@ func (e *lossBasedBandwidthEstimator) updateLossEstimate(results []cc.Acknowledg
 	e.lock.Lock()
 	defer e.lock.Unlock()
 
+	now := e.now()
 	lossRatio := float64(packetsLost) / float64(len(results))
-	e.averageLoss = e.average(time.Since(e.lastLossUpdate), e.averageLoss, lossRatio)
-	e.lastLossUpdate = time.Now()
+	e.averageLoss = e.average(now.Sub(e.lastLossUpdate), e.averageLoss, lossRatio)
+	e.lastLossUpdate = now
 
 	increaseLoss := math.Max(e.averageLoss, lossRatio)
 	decreaseLoss := math.Min(e.averageLoss, lossRatio)
 
-	if increaseLoss < increaseLossThreshold && time.Since(e.lastIncrease) > increaseTimeThreshold {
+	if increaseLoss < increaseLossThreshold && now.Sub(e.lastIncrease) > increaseTimeThreshold {
 		e.log.Infof(
 			"loss controller increasing; averageLoss: %v, decreaseLoss: %v, increaseLoss: %v",
 			e.averageLoss, decreaseLoss, increaseLoss,
 		)
-		e.lastIncrease = time.Now()
+		e.lastIncrease = now
 		e.bitrate = clampInt(int(increaseFactor*float64(e.bitrate)), e.minBitrate, e.maxBitrate)
-	} else if decreaseLoss > decreaseLossThreshold && time.Since(e.lastDecrease) > decreaseTimeThreshold {
+	} else if decreaseLoss > decreaseLossThreshold && now.Sub(e.lastDecrease) > decreaseTimeThreshold {
 		e.log.Infof(
 			"loss controller decreasing; averageLoss: %v, decreaseLoss: %v, increaseLoss: %v",
 			e.averageLoss, decreaseLoss, increaseLoss,
 		)
-		e.lastDecrease = time.Now()
+		e.lastDecrease = now
 		e.bitrate = clampInt(int(float64(e.bitrate)*(1-0.5*decreaseLoss)), e.minBitrate, e.maxBitrate)
 	}
 }
 
+// onProbeResult raises the estimate to the bitrate a probe cluster was delivered at,
+// unless packets are being lost.
+func (e *lossBasedBandwidthEstimator) onProbeResult(bitrate int) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.averageLoss >= increaseLossThreshold {
+		return
+	}
+	e.bitrate = clampInt(max(e.bitrate, bitrate), e.minBitrate, e.maxBitrate)
+}
+
+// onCongestionMarks reduces the estimate by factor in response to ECN CE marks.
+func (e *lossBasedBandwidthEstimator) onCongestionMarks(factor float64) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.log.Infof("loss controller decreasing on CE marks; factor: %v", factor)
+	e.lastDecrease = e.now()
+	e.bitrate = clampInt(int(factor*float64(e.bitrate)), e.minBitrate, e.maxBitrate)
+}
+
 func (e *lossBasedBandwidthEstimator) average(delta time.Duration, prev, sample float64) float64 {
 	return sample + math.Exp(-float64(delta.Milliseconds())/200.0)*(prev-sample)
 }
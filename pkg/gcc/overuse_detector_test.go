@@ -94,7 +94,7 @@ func TestOveruseDetectorWithoutDelay(t *testing.T) {
 			dsw := func(ds DelayStats) {
 				out <- ds
 			}
-			od := newOveruseDetector(tc.thresh, tc.delay, dsw)
+			od := newOveruseDetector(time.Now, tc.thresh, tc.delay, dsw)
 			go func() {
 				defer close(out)
 				for _, e := range tc.estimates {
@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/cc"
+	"github.com/pion/interceptor/pkg/pacing"
+	"github.com/pion/rtp"
+)
+
+const (
+	// probeDuration is the minimum duration of a probe cluster.
+	probeDuration = 15 * time.Millisecond
+	// maxProbeDuration is the duration after which a probe cluster ends, even if it
+	// could not send enough packets.
+	maxProbeDuration = 100 * time.Millisecond
+	// minProbePackets is the minimum number of packets of a probe cluster.
+	minProbePackets = 5
+	// maxPaddingSize is the padding size of padding only packets.
+	maxPaddingSize = 255
+	// paddingHistorySize is the number of sent media packets kept to be resent as
+	// redundant padding.
+	paddingHistorySize = 16
+	// rtxOSNLength is the length of the original sequence number prefixing the payload
+	// of a RTX packet.
+	rtxOSNLength = 2
+)
+
+// prober is implemented by the pacers able to send probe clusters.
+type prober interface {
+	Probe(bitrate int) int
+}
+
+// probeCluster is a burst of packets sent at a bitrate above the target bitrate,
+// to find out if the link can carry it. Packets are padded on a RTX stream when
+// there are not enough media packets to send.
+type probeCluster struct {
+	id          int
+	bitrate     int
+	start       time.Time
+	sentBytes   int
+	sentPackets int
+}
+
+// budget returns the number of bytes the cluster may still send at now, with
+// packets sent every interval.
+func (c *probeCluster) budget(now time.Time, interval time.Duration) int {
+	elapsed := min(now.Sub(c.start)+interval, probeDuration)
+
+	return int(float64(c.bitrate)/8*elapsed.Seconds()) - c.sentBytes
+}
+
+func (c *probeCluster) onSent(size int) {
+	c.sentBytes += size
+	c.sentPackets++
+}
+
+func (c *probeCluster) done(now time.Time) bool {
+	if now.Sub(c.start) >= maxProbeDuration {
+		return true
+	}
+
+	return c.sentPackets >= minProbePackets && c.budget(now, probeDuration) <= 0
+}
+
+// Probe adds a probe cluster at bitrate in bits per second to the pacer, and
+// returns its ID. The packets sent in the cluster have their ID set in their
+// attributes, so that their acknowledgments can be used to estimate the bitrate
+// the link delivered. Probe clusters are sent one after the other.
+func (p *LeakyBucketPacer) Probe(bitrate int) int {
+	p.probeLock.Lock()
+	defer p.probeLock.Unlock()
+
+	p.nextProbeID++
+	p.probes = append(p.probes, &probeCluster{id: p.nextProbeID, bitrate: bitrate})
+
+	return p.nextProbeID
+}
+
+// currentProbe returns the probe cluster to send at now, if any.
+func (p *LeakyBucketPacer) currentProbe(now time.Time) *probeCluster {
+	p.probeLock.Lock()
+	defer p.probeLock.Unlock()
+
+	if len(p.probes) == 0 {
+		return nil
+	}
+	probe := p.probes[0]
+	if probe.start.IsZero() {
+		probe.start = now
+	}
+
+	return probe
+}
+
+func (p *LeakyBucketPacer) finishProbe(probe *probeCluster, now time.Time) {
+	if !probe.done(now) {
+		return
+	}
+
+	p.probeLock.Lock()
+	defer p.probeLock.Unlock()
+
+	p.probes = p.probes[1:]
+}
+
+// paddingStream returns the first added stream with a RTX stream, which padding is sent on.
+func (p *LeakyBucketPacer) paddingStream() (*interceptor.StreamInfo, interceptor.RTPWriter) {
+	p.writerLock.RLock()
+	defer p.writerLock.RUnlock()
+
+	if len(p.rtxStreams) == 0 {
+		return nil, nil
+	}
+	info := p.rtxStreams[0]
+
+	return info, p.ssrcToWriter[info.SSRCRetransmission]
+}
+
+// pad sends padding on the padding stream until the budget of probe is spent.
+func (p *LeakyBucketPacer) pad(probe *probeCluster, now time.Time) {
+	info, writer := p.paddingStream()
+	if info == nil {
+		return
+	}
+
+	for probe.budget(now, p.pacingInterval) > 0 {
+		header, payload := p.paddingPacket(info)
+		attributes := interceptor.Attributes{cc.ProbeClusterAttributesKey: probe.id}
+		if _, err := writer.Write(header, payload, attributes); err != nil {
+			p.log.Errorf("failed to write padding: %v", err)
+
+			return
+		}
+		probe.onSent(header.MarshalSize() + len(payload) + int(header.PaddingSize))
+	}
+}
+
+// paddingPacket returns a RTX packet of the padding stream, resending one of the
+// last sent media packets if any, or a padding only packet otherwise.
+func (p *LeakyBucketPacer) paddingPacket(info *interceptor.StreamInfo) (*rtp.Header, []byte) {
+	if len(p.paddingHistory) == 0 {
+		return &rtp.Header{
+			Version:        2,
+			Padding:        true,
+			PaddingSize:    maxPaddingSize,
+			PayloadType:    info.PayloadTypeRetransmission,
+			SequenceNumber: p.nextRTXSequenceNumber(info.SSRCRetransmission),
+			Timestamp:      p.lastTimestamp,
+			SSRC:           info.SSRCRetransmission,
+		}, nil
+	}
+
+	packet := p.paddingHistory[p.paddingIndex%len(p.paddingHistory)]
+	p.paddingIndex++
+
+	header := packet.Header.Clone()
+	header.SSRC = info.SSRCRetransmission
+	header.PayloadType = info.PayloadTypeRetransmission
+	header.SequenceNumber = p.nextRTXSequenceNumber(info.SSRCRetransmission)
+	payload := make([]byte, rtxOSNLength+len(packet.Payload))
+	binary.BigEndian.PutUint16(payload, packet.SequenceNumber)
+	copy(payload[rtxOSNLength:], packet.Payload)
+
+	return &header, payload
+}
+
+// onMediaSent rewrites the sequence numbers of the RTX streams, which are shared
+// by the retransmissions and the padding, and keeps the media packets of the
+// padding stream to be resent as padding.
+func (p *LeakyBucketPacer) onMediaSent(header *rtp.Header, payload []byte, class pacing.Class) {
+	info, _ := p.paddingStream()
+
+	p.writerLock.RLock()
+	streamInfo := p.ssrcToInfo[header.SSRC]
+	p.writerLock.RUnlock()
+	if streamInfo != nil && streamInfo.SSRCRetransmission == header.SSRC {
+		header.SequenceNumber = p.nextRTXSequenceNumber(header.SSRC)
+	}
+
+	if info == nil || header.SSRC != info.SSRC || class != pacing.ClassVideo {
+		return
+	}
+	p.lastTimestamp = header.Timestamp
+
+	packet := &rtp.Packet{Header: header.Clone(), Payload: make([]byte, len(payload))}
+	copy(packet.Payload, payload)
+	if len(p.paddingHistory) < paddingHistorySize {
+		p.paddingHistory = append(p.paddingHistory, packet)
+	} else {
+		p.paddingHistory[p.paddingHistoryNext] = packet
+	}
+	p.paddingHistoryNext = (p.paddingHistoryNext + 1) % paddingHistorySize
+}
+
+func (p *LeakyBucketPacer) nextRTXSequenceNumber(ssrc uint32) uint16 {
+	sequenceNumber := p.rtxSequenceNumbers[ssrc]
+	p.rtxSequenceNumbers[ssrc] = sequenceNumber + 1
+
+	return sequenceNumber
+}
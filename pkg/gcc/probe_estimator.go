@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/pion/interceptor/internal/cc"
+)
+
+const (
+	// minProbeReceivedShare is the share of the minimum number of packets of a probe
+	// cluster that must be received to estimate its delivered rate.
+	minProbeReceivedShare = 0.8
+	// maxProbeInterval is the longest send or receive interval of a valid probe cluster.
+	maxProbeInterval = time.Second
+	// maxProbeRatio is the ratio of the send and receive rates of a probe cluster over
+	// which the estimate is discounted, as the link could not carry the probe.
+	maxProbeRatio = 1.1
+	// probeDiscount is the share of the receive rate used as estimate for probes the
+	// link could not carry.
+	probeDiscount = 0.95
+	// probeClusterTimeout is the time after which the acknowledgments of a probe cluster
+	// are discarded.
+	probeClusterTimeout = 5 * time.Second
+)
+
+// probeResult is the bitrate a probe cluster was delivered at, 0 if it could not
+// be estimated. The result is complete once all packets of the cluster were
+// acknowledged, or the cluster timed out.
+type probeResult struct {
+	id       int
+	bitrate  int
+	complete bool
+}
+
+// probeClusterAcks accumulates the acknowledgments of a probe cluster.
+type probeClusterAcks struct {
+	sent      int
+	firstSent time.Time
+	lastSent  time.Time
+	acked     int
+	complete  bool
+
+	firstDeparture time.Time
+	lastDeparture  time.Time
+	lastSentSize   int
+	firstArrival   time.Time
+	lastArrival    time.Time
+	firstRecvSize  int
+	bytes          int
+	received       int
+}
+
+func (c *probeClusterAcks) add(ack cc.Acknowledgment) {
+	c.acked++
+	if ack.Arrival.IsZero() {
+		return
+	}
+	if c.received == 0 || ack.Departure.Before(c.firstDeparture) {
+		c.firstDeparture = ack.Departure
+	}
+	if c.received == 0 || ack.Departure.After(c.lastDeparture) {
+		c.lastDeparture = ack.Departure
+		c.lastSentSize = ack.Size
+	}
+	if c.received == 0 || ack.Arrival.Before(c.firstArrival) {
+		c.firstArrival = ack.Arrival
+		c.firstRecvSize = ack.Size
+	}
+	if c.received == 0 || ack.Arrival.After(c.lastArrival) {
+		c.lastArrival = ack.Arrival
+	}
+	c.bytes += ack.Size
+	c.received++
+}
+
+// bitrate returns the bitrate the cluster was delivered at, if enough of its packets
+// were received.
+func (c *probeClusterAcks) bitrate() (int, bool) {
+	if float64(c.received) < minProbeReceivedShare*minProbePackets {
+		return 0, false
+	}
+
+	sendInterval := c.lastDeparture.Sub(c.firstDeparture)
+	receiveInterval := c.lastArrival.Sub(c.firstArrival)
+	if sendInterval <= 0 || sendInterval > maxProbeInterval ||
+		receiveInterval <= 0 || receiveInterval > maxProbeInterval {
+		return 0, false
+	}
+
+	// The last packet sent and the first packet received are not part of the intervals.
+	sendRate := float64(c.bytes-c.lastSentSize) * 8 / sendInterval.Seconds()
+	receiveRate := float64(c.bytes-c.firstRecvSize) * 8 / receiveInterval.Seconds()
+	if sendRate > maxProbeRatio*receiveRate {
+		return int(probeDiscount * receiveRate), true
+	}
+
+	return int(min(sendRate, receiveRate)), true
+}
+
+// done returns true once all packets of the cluster were acknowledged, and the
+// cluster ended, as a packet departed at latest, the longest a cluster is sent
+// after its first packet.
+func (c *probeClusterAcks) done(latest time.Time) bool {
+	first := c.firstSent
+	if c.sent == 0 {
+		first = c.firstDeparture
+	}
+
+	return c.acked >= c.sent && latest.Sub(first) >= maxProbeDuration
+}
+
+// probeEstimator estimates the bitrate the probe clusters were delivered at, from
+// the acknowledgments of their packets. The acknowledgments are grouped by the
+// probe cluster ID the pacer set on the packets.
+type probeEstimator struct {
+	clusters map[int]*probeClusterAcks
+}
+
+func newProbeEstimator() *probeEstimator {
+	return &probeEstimator{clusters: map[int]*probeClusterAcks{}}
+}
+
+func (e *probeEstimator) cluster(id int) *probeClusterAcks {
+	cluster, ok := e.clusters[id]
+	if !ok {
+		cluster = &probeClusterAcks{}
+		e.clusters[id] = cluster
+	}
+
+	return cluster
+}
+
+// onSent counts a packet of the probe cluster id which departed at departure.
+func (e *probeEstimator) onSent(id int, departure time.Time) {
+	cluster := e.cluster(id)
+	if cluster.sent == 0 {
+		cluster.firstSent = departure
+	}
+	cluster.sent++
+	cluster.lastSent = departure
+}
+
+// onAcks adds acknowledgments and returns the estimates of the probe clusters they
+// belong to. The last result of a cluster is complete, no result follows it.
+func (e *probeEstimator) onAcks(acks []cc.Acknowledgment) []probeResult {
+	updated := map[int]bool{}
+	var lastDeparture time.Time
+	for _, ack := range acks {
+		if ack.Departure.After(lastDeparture) {
+			lastDeparture = ack.Departure
+		}
+		if ack.ProbeClusterID == 0 {
+			continue
+		}
+		e.cluster(ack.ProbeClusterID).add(ack)
+		updated[ack.ProbeClusterID] = true
+	}
+
+	var results []probeResult
+	for id, cluster := range e.clusters {
+		timedOut := lastDeparture.Sub(cluster.lastSent) > probeClusterTimeout &&
+			lastDeparture.Sub(cluster.lastDeparture) > probeClusterTimeout
+		if timedOut {
+			delete(e.clusters, id)
+		}
+		if cluster.complete {
+			continue
+		}
+		cluster.complete = timedOut || cluster.done(lastDeparture)
+		if !updated[id] && !cluster.complete {
+			continue
+		}
+		bitrate, ok := cluster.bitrate()
+		if ok || cluster.complete {
+			results = append(results, probeResult{id: id, bitrate: bitrate, complete: cluster.complete})
+		}
+	}
+	slices.SortFunc(results, func(a, b probeResult) int {
+		return cmp.Compare(a.id, b.id)
+	})
+
+	return results
+}
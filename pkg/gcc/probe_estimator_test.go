@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor/internal/cc"
+	"github.com/stretchr/testify/assert"
+)
+
+func probeAcks(id, count int, start time.Time, sendInterval, receiveInterval time.Duration) []cc.Acknowledgment {
+	acks := make([]cc.Acknowledgment, 0, count)
+	for i := range count {
+		acks = append(acks, cc.Acknowledgment{
+			SequenceNumber: uint16(i), //nolint:gosec // G115
+			Size:           1200,
+			Departure:      start.Add(time.Duration(i) * sendInterval),
+			Arrival:        start.Add(50*time.Millisecond + time.Duration(i)*receiveInterval),
+			ProbeClusterID: id,
+		})
+	}
+
+	return acks
+}
+
+func probeSent(estimator *probeEstimator, id, count int, start time.Time, sendInterval time.Duration) {
+	for i := range count {
+		estimator.onSent(id, start.Add(time.Duration(i)*sendInterval))
+	}
+}
+
+func TestProbeEstimator(t *testing.T) {
+	start := time.Time{}.Add(time.Hour)
+
+	t.Run("delivered", func(t *testing.T) {
+		estimator := newProbeEstimator()
+		probeSent(estimator, 1, 10, start, time.Millisecond)
+		results := estimator.onAcks(probeAcks(1, 10, start, time.Millisecond, time.Millisecond))
+		assert.Equal(t, []probeResult{{id: 1, bitrate: 9_600_000}}, results)
+
+		// The result is complete once a packet sent after the cluster ended is acknowledged.
+		results = estimator.onAcks(probeAcks(0, 1, start.Add(maxProbeDuration), 0, 0))
+		assert.Equal(t, []probeResult{{id: 1, bitrate: 9_600_000, complete: true}}, results)
+		assert.Empty(t, estimator.onAcks(probeAcks(1, 1, start, 0, 0)))
+	})
+
+	t.Run("not_delivered", func(t *testing.T) {
+		estimator := newProbeEstimator()
+		results := estimator.onAcks(probeAcks(1, 10, start, time.Millisecond, 2*time.Millisecond))
+		assert.Equal(t, []probeResult{{id: 1, bitrate: 4_560_000}}, results)
+	})
+
+	t.Run("accumulates_feedback", func(t *testing.T) {
+		estimator := newProbeEstimator()
+		probeSent(estimator, 2, 10, start, time.Millisecond)
+		acks := probeAcks(2, 10, start, time.Millisecond, time.Millisecond)
+		lost := acks[9]
+		lost.Arrival = time.Time{}
+		assert.Empty(t, estimator.onAcks(append([]cc.Acknowledgment{lost}, acks[:3]...)))
+		assert.Empty(t, estimator.onAcks([]cc.Acknowledgment{{Departure: start, Arrival: start, Size: 1200}}))
+		assert.Equal(t, []probeResult{{id: 2, bitrate: 9_600_000}}, estimator.onAcks(acks[3:5]))
+
+		// All packets are acknowledged, the last one as lost.
+		late := acks[5:9]
+		late = append(late, cc.Acknowledgment{Departure: start.Add(time.Second), Arrival: start.Add(time.Second)})
+		assert.Equal(t, []probeResult{{id: 2, bitrate: 9_600_000, complete: true}}, estimator.onAcks(late))
+	})
+
+	t.Run("times_out", func(t *testing.T) {
+		estimator := newProbeEstimator()
+		probeSent(estimator, 1, 10, start, time.Millisecond)
+		assert.Empty(t, estimator.onAcks(probeAcks(1, 3, start, time.Millisecond, time.Millisecond)))
+		results := estimator.onAcks(probeAcks(0, 1, start.Add(probeClusterTimeout+time.Second), 0, 0))
+		assert.Equal(t, []probeResult{{id: 1, complete: true}}, results)
+		assert.Empty(t, estimator.clusters)
+	})
+}
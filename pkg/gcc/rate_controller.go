@@ -31,6 +31,7 @@ type rateController struct {
 	latestRTT          time.Duration
 	latestReceivedRate int
 	latestDecreaseRate *exponentialMovingAverage
+	increaseCap        int
 }
 
 type exponentialMovingAverage struct {
@@ -76,6 +77,14 @@ func (c *rateController) onReceivedRate(rate int) {
 	c.latestReceivedRate = rate
 }
 
+// setIncreaseCap sets the bitrate the target bitrate is not increased over, unless a
+// probe was delivered at a higher bitrate. A cap of 0 removes it.
+func (c *rateController) setIncreaseCap(increaseCap int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.increaseCap = increaseCap
+}
+
 func (c *rateController) updateRTT(rtt time.Duration) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -83,12 +92,15 @@ func (c *rateController) updateRTT(rtt time.Duration) {
 }
 
 func (c *rateController) onDelayStats(ds DelayStats) {
-	now := time.Now()
+	now := c.now()
+
+	c.lock.Lock()
 
 	if !c.init {
 		c.delayStats = ds
 		c.delayStats.State = stateIncrease
 		c.init = true
+		c.lock.Unlock()
 
 		return
 	}
@@ -96,40 +108,59 @@ func (c *rateController) onDelayStats(ds DelayStats) {
 	c.delayStats.State = c.delayStats.State.transition(ds.Usage)
 
 	if c.delayStats.State == stateHold {
+		c.lock.Unlock()
+
 		return
 	}
 
-	var next DelayStats
-
-	c.lock.Lock()
-
 	switch c.delayStats.State {
 	case stateHold:
 		// should never occur due to check above, but makes the linter happy
 	case stateIncrease:
-		c.target = clampInt(c.increase(now), c.minBitrate, c.maxBitrate)
-		next = DelayStats{
-			Measurement:      c.delayStats.Measurement,
-			Estimate:         c.delayStats.Estimate,
-			Threshold:        c.delayStats.Threshold,
-			LastReceiveDelta: c.delayStats.LastReceiveDelta,
-			Usage:            c.delayStats.Usage,
-			State:            c.delayStats.State,
-			TargetBitrate:    c.target,
+		target := clampInt(c.increase(now), c.minBitrate, c.maxBitrate)
+		if c.increaseCap > 0 && target > c.target {
+			target = max(c.target, min(target, c.increaseCap))
 		}
-
+		c.target = target
 	case stateDecrease:
 		c.target = clampInt(c.decrease(), c.minBitrate, c.maxBitrate)
-		next = DelayStats{
-			Measurement:      c.delayStats.Measurement,
-			Estimate:         c.delayStats.Estimate,
-			Threshold:        c.delayStats.Threshold,
-			LastReceiveDelta: c.delayStats.LastReceiveDelta,
-			Usage:            c.delayStats.Usage,
-			State:            c.delayStats.State,
-			TargetBitrate:    c.target,
-		}
 	}
+	next := c.delayStats
+	next.TargetBitrate = c.target
+
+	c.lock.Unlock()
+
+	c.dsWriter(next)
+}
+
+// onProbeResult raises the target bitrate to the bitrate a probe cluster was
+// delivered at, unless the delay controller is decreasing the target bitrate.
+func (c *rateController) onProbeResult(bitrate int) {
+	c.lock.Lock()
+
+	if c.delayStats.State == stateDecrease || bitrate <= c.target {
+		c.lock.Unlock()
+
+		return
+	}
+	c.target = clampInt(bitrate, c.minBitrate, c.maxBitrate)
+	c.lastUpdate = c.now()
+	next := c.delayStats
+	next.TargetBitrate = c.target
+
+	c.lock.Unlock()
+
+	c.dsWriter(next)
+}
+
+// onCongestionMarks reduces the target bitrate by factor in response to ECN CE marks.
+func (c *rateController) onCongestionMarks(factor float64) {
+	c.lock.Lock()
+
+	c.target = clampInt(int(factor*float64(c.target)), c.minBitrate, c.maxBitrate)
+	c.lastUpdate = c.now()
+	next := c.delayStats
+	next.TargetBitrate = c.target
 
 	c.lock.Unlock()
 
@@ -37,7 +37,7 @@ func TestRateControllerRun(t *testing.T) {
 		},
 	}
 
-	t0 := time.Time{}
+	t0 := time.Now()
 	mockNoFn := func() time.Time {
 		t0 = t0.Add(100 * time.Millisecond)
 
@@ -76,3 +76,60 @@ func TestRateControllerRun(t *testing.T) {
 		})
 	}
 }
+
+func TestRateController_ProbeResult(t *testing.T) {
+	var updates []DelayStats
+	controller := newRateController(time.Now, 100_000, 1_000, 1_000_000, func(ds DelayStats) {
+		updates = append(updates, ds)
+	})
+
+	// A lower probe result does not change the target bitrate.
+	controller.onProbeResult(50_000)
+	assert.Empty(t, updates)
+
+	controller.onProbeResult(500_000)
+	assert.Equal(t, []DelayStats{{TargetBitrate: 500_000}}, updates)
+
+	// The target bitrate is bounded by the maximum bitrate.
+	controller.onProbeResult(2_000_000)
+	assert.Equal(t, 1_000_000, updates[1].TargetBitrate)
+
+	// Probe results are ignored while the target bitrate decreases.
+	controller.onDelayStats(DelayStats{Usage: usageNormal})
+	controller.onDelayStats(DelayStats{Usage: usageOver})
+	assert.Equal(t, stateDecrease, updates[2].State)
+	controller.onProbeResult(2_000_000)
+	assert.Len(t, updates, 3)
+}
+
+func TestRateController_IncreaseCap(t *testing.T) {
+	var updates []DelayStats
+	now := time.Now()
+	clock := func() time.Time {
+		return now
+	}
+	controller := newRateController(clock, 100_000, 1_000, 1_000_000, func(ds DelayStats) {
+		updates = append(updates, ds)
+	})
+	controller.onDelayStats(DelayStats{Usage: usageNormal})
+
+	// Increases stop at the cap.
+	controller.setIncreaseCap(104_000)
+	controller.onDelayStats(DelayStats{Usage: usageNormal})
+	controller.onDelayStats(DelayStats{Usage: usageNormal})
+	assert.Equal(t, 104_000, updates[0].TargetBitrate)
+	assert.Equal(t, 104_000, updates[1].TargetBitrate)
+
+	// A cap under the target bitrate pauses the increases, probe results are not capped.
+	controller.setIncreaseCap(50_000)
+	controller.onDelayStats(DelayStats{Usage: usageNormal})
+	assert.Equal(t, 104_000, updates[2].TargetBitrate)
+	controller.onProbeResult(300_000)
+	assert.Equal(t, 300_000, updates[3].TargetBitrate)
+
+	// The last probe result is a second old when the cap is removed.
+	now = now.Add(time.Second)
+	controller.setIncreaseCap(0)
+	controller.onDelayStats(DelayStats{Usage: usageNormal})
+	assert.Less(t, 300_000, updates[4].TargetBitrate)
+}
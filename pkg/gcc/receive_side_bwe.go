@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/cc"
+	"github.com/pion/logging"
+)
+
+// receiveSideInitialBitrate is the default initial bitrate of a ReceiveSideBWE. It is
+// higher than the one of the SendSideBWE as the estimate caps the sender once sent.
+const receiveSideInitialBitrate = 1_000_000
+
+// ErrReceiveSideBWEClosed is raised when ReceiveSideBWE.OnPacket is called after ReceiveSideBWE.Close.
+var ErrReceiveSideBWEClosed = errors.New("ReceiveSideBWE closed")
+
+// ReceiveSideBWE implements the delay based part of GCC on the receiving side, as used
+// to generate REMB feedback. It runs the same arrival groups, slope estimator, overuse
+// detector and rate controller as the SendSideBWE, on the departure times the sender
+// signals in the packets and their local arrival times.
+type ReceiveSideBWE struct {
+	delayController *delayController
+
+	onTargetBitrateChange func(bitrate int)
+
+	lock          sync.Mutex
+	latestStats   DelayStats
+	latestBitrate int
+	minBitrate    int
+	maxBitrate    int
+	clock         interceptor.Clock
+
+	close     chan struct{}
+	closeLock sync.RWMutex
+
+	loggerFactory logging.LoggerFactory
+}
+
+// ReceiveSideBWEOption configures a receive side bandwidth estimator.
+type ReceiveSideBWEOption func(*ReceiveSideBWE) error
+
+// ReceiveSideBWEInitialBitrate sets the initial bitrate of the receive side bandwidth estimator.
+func ReceiveSideBWEInitialBitrate(rate int) ReceiveSideBWEOption {
+	return func(e *ReceiveSideBWE) error {
+		e.latestBitrate = rate
+
+		return nil
+	}
+}
+
+// ReceiveSideBWEMaxBitrate sets the maximum bitrate of the receive side bandwidth estimator.
+func ReceiveSideBWEMaxBitrate(rate int) ReceiveSideBWEOption {
+	return func(e *ReceiveSideBWE) error {
+		e.maxBitrate = rate
+
+		return nil
+	}
+}
+
+// ReceiveSideBWEMinBitrate sets the minimum bitrate of the receive side bandwidth estimator.
+func ReceiveSideBWEMinBitrate(rate int) ReceiveSideBWEOption {
+	return func(e *ReceiveSideBWE) error {
+		e.minBitrate = rate
+
+		return nil
+	}
+}
+
+// ReceiveSideBWEClock sets the clock of the receive side bandwidth estimator.
+func ReceiveSideBWEClock(clock interceptor.Clock) ReceiveSideBWEOption {
+	return func(e *ReceiveSideBWE) error {
+		e.clock = clock
+
+		return nil
+	}
+}
+
+// ReceiveSideBWELoggerFactory sets the logger factory for the receive side bandwidth estimator.
+func ReceiveSideBWELoggerFactory(factory logging.LoggerFactory) ReceiveSideBWEOption {
+	return func(e *ReceiveSideBWE) error {
+		e.loggerFactory = factory
+
+		return nil
+	}
+}
+
+// NewReceiveSideBWE creates a new receive side bandwidth estimator.
+func NewReceiveSideBWE(opts ...ReceiveSideBWEOption) (*ReceiveSideBWE, error) {
+	receive := &ReceiveSideBWE{
+		latestBitrate: receiveSideInitialBitrate,
+		minBitrate:    minBitrate,
+		maxBitrate:    maxBitrate,
+		clock:         interceptor.SystemClock(),
+		close:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if err := opt(receive); err != nil {
+			return nil, err
+		}
+	}
+	if receive.loggerFactory == nil {
+		receive.loggerFactory = logging.NewDefaultLoggerFactory()
+	}
+	receive.delayController = newDelayController(delayControllerConfig{
+		nowFn:          receive.clock.Now,
+		initialBitrate: receive.latestBitrate,
+		minBitrate:     receive.minBitrate,
+		maxBitrate:     receive.maxBitrate,
+	}, receive.loggerFactory)
+
+	receive.delayController.onUpdate(receive.onDelayUpdate)
+
+	return receive, nil
+}
+
+// OnPacket adds a received packet of size bytes to the bandwidth estimator. The
+// departure time is the send time signaled by the sender, only its differences
+// between packets are used.
+func (e *ReceiveSideBWE) OnPacket(departure, arrival time.Time, size int) error {
+	e.closeLock.RLock()
+	defer e.closeLock.RUnlock()
+
+	if e.isClosed() {
+		return ErrReceiveSideBWEClosed
+	}
+
+	e.delayController.updateDelayEstimate([]cc.Acknowledgment{{
+		Size:      size,
+		Departure: departure,
+		Arrival:   arrival,
+	}})
+
+	return nil
+}
+
+// UpdateRTT sets the round trip time to the sender, which speeds up the increase of
+// the estimate after a decrease.
+func (e *ReceiveSideBWE) UpdateRTT(rtt time.Duration) {
+	e.delayController.updateRTT(rtt)
+}
+
+// GetTargetBitrate returns the current estimate in bits per second.
+func (e *ReceiveSideBWE) GetTargetBitrate() int {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return e.latestBitrate
+}
+
+// GetStats returns some internal statistics of the bandwidth estimator.
+func (e *ReceiveSideBWE) GetStats() map[string]any {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return map[string]any{
+		"delayTargetBitrate": e.latestStats.TargetBitrate,
+		"delayMeasurement":   float64(e.latestStats.Measurement.Microseconds()) / 1000.0,
+		"delayEstimate":      float64(e.latestStats.Estimate.Microseconds()) / 1000.0,
+		"delayThreshold":     float64(e.latestStats.Threshold.Microseconds()) / 1000.0,
+		"usage":              e.latestStats.Usage.String(),
+		"state":              e.latestStats.State.String(),
+	}
+}
+
+// OnTargetBitrateChange sets the callback that is called when the estimate in bits
+// per second changes.
+func (e *ReceiveSideBWE) OnTargetBitrateChange(f func(bitrate int)) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.onTargetBitrateChange = f
+}
+
+func (e *ReceiveSideBWE) isClosed() bool {
+	select {
+	case <-e.close:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops and closes the bandwidth estimator.
+func (e *ReceiveSideBWE) Close() error {
+	e.closeLock.Lock()
+	defer e.closeLock.Unlock()
+
+	if e.isClosed() {
+		return nil
+	}
+	close(e.close)
+
+	return e.delayController.Close()
+}
+
+func (e *ReceiveSideBWE) onDelayUpdate(delayStats DelayStats) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.latestStats = delayStats
+	if delayStats.TargetBitrate == e.latestBitrate {
+		return
+	}
+	e.latestBitrate = delayStats.TargetBitrate
+
+	if e.onTargetBitrateChange != nil {
+		go e.onTargetBitrateChange(e.latestBitrate)
+	}
+}
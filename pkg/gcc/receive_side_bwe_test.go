@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiveSideBWE(t *testing.T) {
+	bwe, err := NewReceiveSideBWE(ReceiveSideBWEInitialBitrate(2_000_000))
+	require.NoError(t, err)
+	assert.Equal(t, 2_000_000, bwe.GetTargetBitrate())
+
+	changes := make(chan int, 100)
+	bwe.OnTargetBitrateChange(func(bitrate int) {
+		changes <- bitrate
+	})
+
+	// 1200 bytes every 10ms, about 1 Mbps, with a queue building up on the path.
+	start := time.Now()
+	for i := range 100 {
+		departure := start.Add(time.Duration(i) * 10 * time.Millisecond)
+		arrival := departure.Add(time.Duration(i) * 2 * time.Millisecond)
+		require.NoError(t, bwe.OnPacket(departure, arrival, 1200))
+		time.Sleep(time.Millisecond)
+	}
+
+	timeout := time.After(time.Second)
+	for decreased := false; !decreased; {
+		select {
+		case bitrate := <-changes:
+			decreased = bitrate < 2_000_000
+		case <-timeout:
+			require.Fail(t, "estimate did not decrease")
+		}
+	}
+	assert.Less(t, bwe.GetTargetBitrate(), 2_000_000)
+	assert.Equal(t, "decrease", bwe.GetStats()["state"])
+
+	assert.NoError(t, bwe.Close())
+	assert.NoError(t, bwe.Close())
+	assert.ErrorIs(t, bwe.OnPacket(start, start, 1200), ErrReceiveSideBWEClosed)
+}
@@ -5,23 +5,41 @@ package gcc
 
 import (
 	"errors"
-	"math"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/pion/interceptor"
 	"github.com/pion/interceptor/internal/cc"
-	"github.com/pion/interceptor/internal/ntp"
 	"github.com/pion/logging"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 )
 
 const (
-	transportCCURI = "http://www.ietf.org/id/draft-holmer-rmcat-transport-wide-cc-extensions-01"
-	latestBitrate  = 10_000
-	minBitrate     = 5_000
-	maxBitrate     = 50_000_000
+	latestBitrate = 10_000
+	minBitrate    = 5_000
+	maxBitrate    = 50_000_000
+
+	// firstStartupProbeFactor and secondStartupProbeFactor are the multiples of the
+	// initial bitrate probed once the first stream is added.
+	firstStartupProbeFactor  = 3
+	secondStartupProbeFactor = 6
+	// exponentialProbeFactor is the multiple of the result of a startup probe probed
+	// next, as long as the probes are delivered.
+	exponentialProbeFactor = 2
+	// exponentialProbeThreshold is the share of the probed bitrate a startup probe must
+	// be delivered at to probe further.
+	exponentialProbeThreshold = 0.7
+	// alrProbeFactor is the multiple of the target bitrate probed while the sender
+	// is application limited.
+	alrProbeFactor = 2
+	// alrProbeInterval is the interval between the probes while the sender is
+	// application limited.
+	alrProbeInterval = 5 * time.Second
+	// alrIncreaseCapFactor is the multiple of the send bitrate the delay based target
+	// bitrate is not increased over while the sender is application limited.
+	alrIncreaseCapFactor = 1.5
 )
 
 // ErrSendSideBWEClosed is raised when SendSideBWE.WriteRTCP is called after SendSideBWE.Close.
@@ -39,22 +57,66 @@ type Pacer interface {
 type Stats struct {
 	LossStats
 	DelayStats
+	ALRStats
+	ECNStats
 }
 
 // SendSideBWE implements a combination of loss and delay based GCC.
+//
+// When the pacer supports probing, as the LeakyBucketPacer does, the link is
+// probed above the target bitrate once the first stream is added, and
+// periodically while the application sends less than the target bitrate allows.
+// While the application sends less than the target bitrate allows, the target
+// bitrate is not increased over a multiple of the bitrate actually sent.
+// The bitrate a probe is delivered at raises the target bitrate right away. At
+// startup, twice the delivered bitrate is probed next, as long as the probes are
+// delivered at most of their bitrate, once all packets of a probe were
+// acknowledged or it timed out.
+// ECN CE marks reported by RFC 8888 feedback reduce the target bitrate in
+// proportion to the marked fraction, as TCP Prague does for L4S. The outgoing
+// packets can be marked ECT(1) with SendSideBWEMarkECT1.
+// The packets are numbered with transport wide sequence numbers by a
+// twcc.HeaderExtensionInterceptor registered after the congestion control
+// interceptor, so that it numbers the packets before they are queued in the
+// pacer. The SendSideBWE keeps these numbers, records the departure of the
+// packets as they leave the pacer in the interceptor.SentPacketRegistry set in
+// their attributes, and numbers the padding of the probes from the same
+// registry. Without such an interceptor, SendSideBWENumberPackets makes the
+// SendSideBWE number all packets itself. The startup probes are sent once the
+// first packet left the pacer.
+//
+// REMB packets received from the remote are applied as an upper bound on the
+// target bitrate. REMB packets for disjoint sets of SSRCs add up, a REMB packet
+// replaces the previous ones sharing one of its SSRCs. Until TWCC or RFC 8888
+// feedback is received, the REMB estimate is used as target bitrate, so that
+// peers only supporting REMB can be used with the SendSideBWE.
 type SendSideBWE struct {
 	pacer           Pacer
 	lossController  *lossBasedBandwidthEstimator
 	delayController *delayController
-	feedbackAdapter *cc.FeedbackAdapter
+	sendSide        *cc.SendSide
+	probeEstimator  *probeEstimator
+	ecnController   *ecnController
 
 	onTargetBitrateChange func(bitrate int)
 
-	lock          sync.Mutex
-	latestStats   Stats
-	latestBitrate int
-	minBitrate    int
-	maxBitrate    int
+	lock             sync.Mutex
+	latestStats      Stats
+	latestBitrate    int
+	minBitrate       int
+	maxBitrate       int
+	estimatedBitrate int
+	feedbackReceived bool
+	rembs            []rembEstimate
+	alr              *alrDetector
+	startupProbed    bool
+	lastALRProbe     time.Time
+	alrStats         ALRStats
+	startupProbes    map[int]int
+	maxStartupProbe  int
+	lastProbeBitrate int
+	ecnStats         ECNStats
+	clock            interceptor.Clock
 
 	close     chan struct{}
 	closeLock sync.RWMutex
@@ -62,6 +124,12 @@ type SendSideBWE struct {
 	loggerFactory logging.LoggerFactory
 }
 
+// rembEstimate is the estimate of the last REMB packet for a set of SSRCs.
+type rembEstimate struct {
+	ssrcs   []uint32
+	bitrate int
+}
+
 // Option configures a bandwidth estimator.
 type Option func(*SendSideBWE) error
 
@@ -101,6 +169,37 @@ func SendSideBWEPacer(p Pacer) Option {
 	}
 }
 
+// SendSideBWEMarkECT1 asks the transport to send the packets with the ECT(1) ECN
+// codepoint, signaling that the sender responds to CE marks as defined by L4S.
+func SendSideBWEMarkECT1() Option {
+	return func(e *SendSideBWE) error {
+		e.sendSide.MarkECT1 = true
+
+		return nil
+	}
+}
+
+// SendSideBWENumberPackets makes the bandwidth estimator number the transport
+// wide sequence numbers of all packets as they leave the pacer, replacing the
+// numbers set by earlier interceptors. It is used when no
+// twcc.HeaderExtensionInterceptor is registered.
+func SendSideBWENumberPackets() Option {
+	return func(e *SendSideBWE) error {
+		e.sendSide.NumberPackets = true
+
+		return nil
+	}
+}
+
+// SendSideBWEClock sets the clock of the bandwidth estimator and of its default pacer.
+func SendSideBWEClock(clock interceptor.Clock) Option {
+	return func(e *SendSideBWE) error {
+		e.clock = clock
+
+		return nil
+	}
+}
+
 // WithLoggerFactory sets the logger factory for the bandwidth estimator.
 func WithLoggerFactory(factory logging.LoggerFactory) Option {
 	return func(e *SendSideBWE) error {
@@ -116,13 +215,17 @@ func NewSendSideBWE(opts ...Option) (*SendSideBWE, error) {
 		pacer:                 nil,
 		lossController:        nil,
 		delayController:       nil,
-		feedbackAdapter:       cc.NewFeedbackAdapter(),
+		sendSide:              cc.NewSendSide(),
+		probeEstimator:        newProbeEstimator(),
+		ecnController:         newECNController(),
 		onTargetBitrateChange: nil,
 		lock:                  sync.Mutex{},
 		latestStats:           Stats{},
 		latestBitrate:         latestBitrate,
 		minBitrate:            minBitrate,
 		maxBitrate:            maxBitrate,
+		startupProbes:         map[int]int{},
+		clock:                 interceptor.SystemClock(),
 		close:                 make(chan struct{}),
 	}
 	for _, opt := range opts {
@@ -133,12 +236,14 @@ func NewSendSideBWE(opts ...Option) (*SendSideBWE, error) {
 	if send.loggerFactory == nil {
 		send.loggerFactory = logging.NewDefaultLoggerFactory()
 	}
+	send.sendSide.Clock = send.clock
 	if send.pacer == nil {
-		send.pacer = newLeakyBucketPacer(send.latestBitrate, send.loggerFactory)
+		send.pacer = newLeakyBucketPacer(send.latestBitrate, send.loggerFactory, LeakyBucketPacerClock(send.clock))
 	}
-	send.lossController = newLossBasedBWE(send.latestBitrate, send.loggerFactory)
+	send.alr = newALRDetector(send.latestBitrate)
+	send.lossController = newLossBasedBWE(send.clock.Now, send.latestBitrate, send.loggerFactory)
 	send.delayController = newDelayController(delayControllerConfig{
-		nowFn:          time.Now,
+		nowFn:          send.clock.Now,
 		initialBitrate: send.latestBitrate,
 		minBitrate:     send.minBitrate,
 		maxBitrate:     send.maxBitrate,
@@ -151,39 +256,118 @@ func NewSendSideBWE(opts ...Option) (*SendSideBWE, error) {
 
 // AddStream adds a new stream to the bandwidth estimator.
 func (e *SendSideBWE) AddStream(info *interceptor.StreamInfo, writer interceptor.RTPWriter) interceptor.RTPWriter {
-	var hdrExtID uint8
-	for _, e := range info.RTPHeaderExtensions {
-		if e.URI == transportCCURI {
-			hdrExtID = uint8(e.ID) //nolint:gosec // G115
+	e.sendSide.AddStream(e.pacer, info, writer,
+		func(now time.Time, header *rtp.Header, size int, attributes interceptor.Attributes) {
+			probeClusterID, _ := attributes.Get(cc.ProbeClusterAttributesKey).(int)
+			e.onSent(now, header.MarshalSize()+size, probeClusterID)
+			e.startupProbe()
+		},
+	)
 
-			break
-		}
+	return e.pacer
+}
+
+// startupProbe probes multiples of the initial bitrate once the first packet is sent.
+func (e *SendSideBWE) startupProbe() {
+	pacer, ok := e.pacer.(prober)
+	if !ok {
+		return
 	}
 
-	e.pacer.AddStream(info.SSRC, interceptor.RTPWriterFunc(
-		func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
-			if hdrExtID != 0 {
-				if attributes == nil {
-					attributes = make(interceptor.Attributes)
-				}
-				attributes.Set(cc.TwccExtensionAttributesKey, hdrExtID)
-			}
-			if err := e.feedbackAdapter.OnSent(time.Now(), header, len(payload), attributes); err != nil {
-				return 0, err
-			}
+	e.lock.Lock()
+	defer e.lock.Unlock()
 
-			return writer.Write(header, payload, attributes)
-		},
-	))
+	if e.startupProbed {
+		return
+	}
+	e.startupProbed = true
+	e.maxStartupProbe = 0
+	clear(e.startupProbes)
+	for _, factor := range []int{firstStartupProbeFactor, secondStartupProbeFactor} {
+		e.probeStartup(pacer, factor*e.latestBitrate)
+	}
+}
 
-	return e.pacer
+// probeStartup probes bitrate during startup. It must be called with the lock held.
+func (e *SendSideBWE) probeStartup(pacer prober, bitrate int) {
+	bitrate = min(bitrate, e.maxBitrate)
+	if bitrate <= e.maxStartupProbe {
+		return
+	}
+	e.maxStartupProbe = bitrate
+	e.startupProbes[pacer.Probe(bitrate)] = bitrate
+}
+
+// OnNetworkChange probes the link again as at startup. It should be called when the
+// network route changes.
+func (e *SendSideBWE) OnNetworkChange() {
+	e.lock.Lock()
+	e.startupProbed = false
+	e.lock.Unlock()
+
+	e.startupProbe()
+}
+
+// onProbeResult raises the target bitrate to the bitrate a probe was delivered at,
+// and probes further during startup.
+func (e *SendSideBWE) onProbeResult(result probeResult) {
+	if result.bitrate > 0 {
+		e.lossController.onProbeResult(result.bitrate)
+		e.delayController.onProbeResult(result.bitrate)
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if result.bitrate > 0 {
+		e.lastProbeBitrate = result.bitrate
+	}
+	if !result.complete {
+		return
+	}
+	probed, ok := e.startupProbes[result.id]
+	if !ok {
+		return
+	}
+	delete(e.startupProbes, result.id)
+	if pacer, ok := e.pacer.(prober); ok && float64(result.bitrate) >= exponentialProbeThreshold*float64(probed) {
+		e.probeStartup(pacer, exponentialProbeFactor*result.bitrate)
+	}
+}
+
+// onSent updates the ALR detector with a packet of size bytes leaving the pacer,
+// and counts the packets of the probe clusters. While the sender is application
+// limited, increases of the target bitrate are capped and the link is probed.
+func (e *SendSideBWE) onSent(now time.Time, size int, probeClusterID int) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if probeClusterID != 0 {
+		e.probeEstimator.onSent(probeClusterID, now)
+	}
+
+	e.alr.onSent(now, size)
+	e.alrStats = e.alr.stats(now)
+	increaseCap := 0
+	if e.alrStats.InALR {
+		increaseCap = max(int(alrIncreaseCapFactor*float64(e.alrStats.SendBitrate)), e.minBitrate)
+	}
+	e.delayController.setIncreaseCap(increaseCap)
+
+	pacer, ok := e.pacer.(prober)
+	if !ok {
+		return
+	}
+	if !e.alrStats.InALR || now.Sub(e.lastALRProbe) < alrProbeInterval {
+		return
+	}
+	e.lastALRProbe = now
+	pacer.Probe(min(alrProbeFactor*e.latestBitrate, e.maxBitrate))
 }
 
 // WriteRTCP adds some RTCP feedback to the bandwidth estimator.
-//
-//nolint:cyclop
 func (e *SendSideBWE) WriteRTCP(pkts []rtcp.Packet, _ interceptor.Attributes) error {
-	now := time.Now()
+	now := e.clock.Now()
 	e.closeLock.RLock()
 	defer e.closeLock.RUnlock()
 
@@ -192,47 +376,37 @@ func (e *SendSideBWE) WriteRTCP(pkts []rtcp.Packet, _ interceptor.Attributes) er
 	}
 
 	for _, pkt := range pkts {
-		var acks []cc.Acknowledgment
-		var err error
-		var feedbackSentTime time.Time
-		switch fb := pkt.(type) {
-		case *rtcp.TransportLayerCC:
-			acks, err = e.feedbackAdapter.OnTransportCCFeedback(now, fb)
-			if err != nil {
-				return err
-			}
-			for i, ack := range acks {
-				if i == 0 {
-					feedbackSentTime = ack.Arrival
-
-					continue
-				}
-				if ack.Arrival.After(feedbackSentTime) {
-					feedbackSentTime = ack.Arrival
-				}
-			}
-		case *rtcp.CCFeedbackReport:
-			acks = e.feedbackAdapter.OnRFC8888Feedback(now, fb)
-			feedbackSentTime = ntp.ToTime(uint64(fb.ReportTimestamp) << 16)
-		default:
+		if remb, ok := pkt.(*rtcp.ReceiverEstimatedMaximumBitrate); ok {
+			e.onREMB(remb)
+
 			continue
 		}
-
-		feedbackMinRTT := time.Duration(math.MaxInt)
-		for _, ack := range acks {
-			if ack.Arrival.IsZero() {
-				continue
-			}
-			pendingTime := feedbackSentTime.Sub(ack.Arrival)
-			rtt := now.Sub(ack.Departure) - pendingTime
-			feedbackMinRTT = time.Duration(min(int(rtt), int(feedbackMinRTT)))
+		feedback, ok, err := e.sendSide.OnFeedback(now, pkt)
+		if err != nil {
+			return err
 		}
-		if feedbackMinRTT < math.MaxInt {
-			e.delayController.updateRTT(feedbackMinRTT)
+		if !ok {
+			continue
+		}
+		acks := feedback.Acks
+		if feedback.HasRTT {
+			e.delayController.updateRTT(feedback.RTT)
 		}
 
 		e.lossController.updateLossEstimate(acks)
 		e.delayController.updateDelayEstimate(acks)
+		e.lock.Lock()
+		results := e.probeEstimator.onAcks(acks)
+		ecnFactor := e.ecnController.onAcks(now, acks, feedback.RTT)
+		e.ecnStats = e.ecnController.stats()
+		e.lock.Unlock()
+		if ecnFactor < 1 {
+			e.lossController.onCongestionMarks(ecnFactor)
+			e.delayController.onCongestionMarks(ecnFactor)
+		}
+		for _, result := range results {
+			e.onProbeResult(result)
+		}
 	}
 
 	return nil
@@ -251,7 +425,16 @@ func (e *SendSideBWE) GetStats() map[string]any {
 	e.lock.Lock()
 	defer e.lock.Unlock()
 
+	rembCap, _ := e.rembCap()
+
 	return map[string]any{
+		"rembCap":            rembCap,
+		"rembLimited":        e.feedbackReceived && rembCap > 0 && rembCap < e.estimatedBitrate,
+		"probeBitrate":       e.lastProbeBitrate,
+		"inALR":              e.alrStats.InALR,
+		"alrSendBitrate":     e.alrStats.SendBitrate,
+		"ecnMarkedRatio":     e.ecnStats.MarkedRatio,
+		"ecnAlpha":           e.ecnStats.Alpha,
 		"lossTargetBitrate":  e.latestStats.LossStats.TargetBitrate,
 		"averageLoss":        e.latestStats.AverageLoss,
 		"delayTargetBitrate": e.latestStats.DelayStats.TargetBitrate,
@@ -263,6 +446,18 @@ func (e *SendSideBWE) GetStats() map[string]any {
 	}
 }
 
+// Stats returns the internal statistics of the bandwidth estimator.
+func (e *SendSideBWE) Stats() Stats {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	stats := e.latestStats
+	stats.ALRStats = e.alrStats
+	stats.ECNStats = e.ecnStats
+
+	return stats
+}
+
 // OnTargetBitrateChange sets the callback that is called when the target
 // bitrate in bits per second changes.
 func (e *SendSideBWE) OnTargetBitrateChange(f func(bitrate int)) {
@@ -297,20 +492,82 @@ func (e *SendSideBWE) onDelayUpdate(delayStats DelayStats) {
 	defer e.lock.Unlock()
 
 	lossStats := e.lossController.getEstimate(delayStats.TargetBitrate)
-	bitrateChanged := false
-	bitrate := min(delayStats.TargetBitrate, lossStats.TargetBitrate)
-	if bitrate != e.latestBitrate {
-		bitrateChanged = true
-		e.latestBitrate = bitrate
-		e.pacer.SetTargetBitrate(e.latestBitrate)
+	e.estimatedBitrate = min(delayStats.TargetBitrate, lossStats.TargetBitrate)
+	e.feedbackReceived = true
+	e.latestStats = Stats{
+		LossStats:  lossStats,
+		DelayStats: delayStats,
 	}
 
-	if bitrateChanged && e.onTargetBitrateChange != nil {
-		go e.onTargetBitrateChange(bitrate)
+	e.updateTargetBitrate()
+}
+
+// onREMB replaces the estimates of the SSRCs of the REMB packet.
+func (e *SendSideBWE) onREMB(remb *rtcp.ReceiverEstimatedMaximumBitrate) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	rembs := e.rembs[:0]
+	for _, estimate := range e.rembs {
+		if !sharesSSRC(estimate.ssrcs, remb.SSRCs) {
+			rembs = append(rembs, estimate)
+		}
 	}
+	e.rembs = append(rembs, rembEstimate{ssrcs: remb.SSRCs, bitrate: int(remb.Bitrate)})
 
-	e.latestStats = Stats{
-		LossStats:  lossStats,
-		DelayStats: delayStats,
+	e.updateTargetBitrate()
+}
+
+// sharesSSRC returns true if the SSRC sets overlap, an empty set stands for all SSRCs.
+func sharesSSRC(a, b []uint32) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+	for _, ssrc := range a {
+		if slices.Contains(b, ssrc) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rembCap returns the sum of the REMB estimates, if any.
+func (e *SendSideBWE) rembCap() (int, bool) {
+	if len(e.rembs) == 0 {
+		return 0, false
+	}
+	rembCap := 0
+	for _, estimate := range e.rembs {
+		rembCap += estimate.bitrate
+	}
+
+	return rembCap, true
+}
+
+// updateTargetBitrate applies the REMB cap to the estimate and notifies the target bitrate
+// if it changed. It must be called with the lock held.
+func (e *SendSideBWE) updateTargetBitrate() {
+	bitrate := e.latestBitrate
+	rembCap, capped := e.rembCap()
+	switch {
+	case e.feedbackReceived && capped:
+		bitrate = min(e.estimatedBitrate, rembCap)
+	case e.feedbackReceived:
+		bitrate = e.estimatedBitrate
+	case capped:
+		bitrate = rembCap
+	}
+	bitrate = max(min(bitrate, e.maxBitrate), e.minBitrate)
+
+	if bitrate == e.latestBitrate {
+		return
+	}
+	e.latestBitrate = bitrate
+	e.alr.setBitrate(bitrate)
+	e.pacer.SetTargetBitrate(e.latestBitrate)
+
+	if e.onTargetBitrateChange != nil {
+		go e.onTargetBitrateChange(bitrate)
 	}
 }
@@ -7,8 +7,12 @@ import (
 	"fmt"
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/cc"
+	"github.com/pion/interceptor/internal/ntp"
+	"github.com/pion/interceptor/pkg/netem"
 	"github.com/pion/interceptor/pkg/twcc"
 	"github.com/pion/logging"
 	"github.com/pion/rtcp"
@@ -57,7 +61,7 @@ func TestSendSideBWE(t *testing.T) {
 	rtpPayload := make([]byte, 1460)
 	streamInfo := &interceptor.StreamInfo{
 		SSRC:                1,
-		RTPHeaderExtensions: []interceptor.RTPHeaderExtension{{URI: transportCCURI, ID: 1}},
+		RTPHeaderExtensions: []interceptor.RTPHeaderExtension{{URI: cc.TransportCCURI, ID: 1}},
 	}
 
 	bwe, err := NewSendSideBWE(WithLoggerFactory(logging.NewDefaultLoggerFactory()))
@@ -99,6 +103,65 @@ func TestSendSideBWE(t *testing.T) {
 	require.Less(t, latestBitrate, bwe.GetTargetBitrate())
 }
 
+func TestSendSideBWE_TWCCHeaderExtensionInterceptor(t *testing.T) {
+	stamperClock := netem.NewVirtualClock(time.Unix(1_000, 0))
+	clock := netem.NewVirtualClock(time.Unix(2_000, 0))
+	bwe, err := NewSendSideBWE(SendSideBWEPacer(NewNoOpPacer()), SendSideBWEClock(clock))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, bwe.Close())
+	}()
+
+	streamInfo := &interceptor.StreamInfo{
+		SSRC:                1,
+		RTPHeaderExtensions: []interceptor.RTPHeaderExtension{{URI: cc.TransportCCURI, ID: 1}},
+	}
+	var sequenceNumbers []uint16
+	var registries []*interceptor.SentPacketRegistry
+	pacer := bwe.AddStream(streamInfo, interceptor.RTPWriterFunc(
+		func(header *rtp.Header, _ []byte, attributes interceptor.Attributes) (int, error) {
+			var ext rtp.TransportCCExtension
+			assert.NoError(t, ext.Unmarshal(header.GetExtension(1)))
+			sequenceNumbers = append(sequenceNumbers, ext.TransportSequence)
+			registry, ok := attributes.Get(interceptor.SentPacketRegistryAttributesKey).(*interceptor.SentPacketRegistry)
+			assert.True(t, ok)
+			registries = append(registries, registry)
+
+			return 0, nil
+		},
+	))
+
+	// The stamper is registered after the congestion control interceptor, so it
+	// numbers the packets before the pacer.
+	factory, err := twcc.NewHeaderExtensionInterceptor(twcc.HeaderExtensionClock(stamperClock))
+	require.NoError(t, err)
+	stamper, err := factory.NewInterceptor("")
+	require.NoError(t, err)
+	writer := stamper.BindLocalStream(streamInfo, pacer)
+	for i := range uint16(3) {
+		_, err = writer.Write(&rtp.Header{SSRC: 1, SequenceNumber: i}, make([]byte, 100), nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []uint16{0, 1, 2}, sequenceNumbers)
+
+	// Packets generated by the pacer, such as padding, are numbered from the
+	// registry of the stamper.
+	_, err = pacer.Write(&rtp.Header{SSRC: 1, SequenceNumber: 3}, make([]byte, 100), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{0, 1, 2, 3}, sequenceNumbers)
+	assert.Equal(t, uint16(4), registries[0].NextTransportSequenceNumber())
+
+	// The departures are taken as the packets leave the pacer.
+	for i, registry := range registries {
+		assert.Same(t, registries[0], registry)
+		packet, ok := registry.GetByTransportSequenceNumber(uint16(i)) //nolint:gosec // G115
+		assert.True(t, ok)
+		assert.Equal(t, uint16(i), packet.SequenceNumber) //nolint:gosec // G115
+		assert.Equal(t, clock.Now(), packet.Departure)
+	}
+	assert.Equal(t, uint64(4), registries[0].NextIndex())
+}
+
 func TestSendSideBWE_ErrorOnWriteRTCPAtClosedState(t *testing.T) {
 	bwe, err := NewSendSideBWE()
 	require.NoError(t, err)
@@ -112,6 +175,291 @@ func TestSendSideBWE_ErrorOnWriteRTCPAtClosedState(t *testing.T) {
 	require.Equal(t, bwe.isClosed(), true)
 }
 
+func TestSendSideBWE_REMB(t *testing.T) {
+	bwe, err := NewSendSideBWE(SendSideBWEInitialBitrate(1_000_000), SendSideBWEPacer(NewNoOpPacer()))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, bwe.Close())
+	}()
+
+	writeREMB := func(bitrate float32, ssrcs ...uint32) {
+		t.Helper()
+
+		require.NoError(t, bwe.WriteRTCP([]rtcp.Packet{&rtcp.ReceiverEstimatedMaximumBitrate{
+			Bitrate: bitrate,
+			SSRCs:   ssrcs,
+		}}, nil))
+	}
+
+	// Without feedback, REMB is the rate source.
+	writeREMB(300_000, 1)
+	assert.Equal(t, 300_000, bwe.GetTargetBitrate())
+
+	// Estimates of disjoint SSRC sets add up, overlapping ones are replaced.
+	writeREMB(200_000, 2)
+	assert.Equal(t, 500_000, bwe.GetTargetBitrate())
+	writeREMB(100_000, 1, 3)
+	assert.Equal(t, 300_000, bwe.GetTargetBitrate())
+	assert.Equal(t, 300_000, bwe.GetStats()["rembCap"])
+
+	// With feedback, REMB caps the estimate.
+	bwe.onDelayUpdate(DelayStats{TargetBitrate: 800_000})
+	assert.Equal(t, 300_000, bwe.GetTargetBitrate())
+	assert.Equal(t, true, bwe.GetStats()["rembLimited"])
+
+	writeREMB(2_000_000)
+	assert.Equal(t, 800_000, bwe.GetTargetBitrate())
+	assert.Equal(t, 2_000_000, bwe.GetStats()["rembCap"])
+	assert.Equal(t, false, bwe.GetStats()["rembLimited"])
+}
+
+// mockProbingPacer sends packets immediately and records the probed bitrates.
+type mockProbingPacer struct {
+	*NoOpPacer
+	probes []int
+}
+
+func (m *mockProbingPacer) Probe(bitrate int) int {
+	m.probes = append(m.probes, bitrate)
+
+	return len(m.probes)
+}
+
+func TestSendSideBWE_Probing(t *testing.T) {
+	pacer := &mockProbingPacer{NoOpPacer: NewNoOpPacer()}
+	bwe, err := NewSendSideBWE(SendSideBWEInitialBitrate(100_000), SendSideBWEPacer(pacer), SendSideBWENumberPackets())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, bwe.Close())
+	}()
+
+	streamInfo := &interceptor.StreamInfo{
+		SSRC:                1,
+		RTPHeaderExtensions: []interceptor.RTPHeaderExtension{{URI: cc.TransportCCURI, ID: 1}},
+	}
+	var sequenceNumbers []uint16
+	writer := bwe.AddStream(streamInfo, interceptor.RTPWriterFunc(
+		func(header *rtp.Header, _ []byte, _ interceptor.Attributes) (int, error) {
+			ext := rtp.TransportCCExtension{}
+			assert.NoError(t, ext.Unmarshal(header.GetExtension(1)))
+			sequenceNumbers = append(sequenceNumbers, ext.TransportSequence)
+
+			return 0, nil
+		},
+	))
+	bwe.AddStream(&interceptor.StreamInfo{SSRC: 2}, writer)
+	assert.Empty(t, pacer.probes)
+
+	// The transport wide sequence numbers are set as the packets leave the pacer,
+	// the startup probes are sent after the first packet.
+	header := &rtp.Header{SSRC: 1}
+	for range 3 {
+		assert.NoError(t, header.SetExtension(1, []byte{0, 100}))
+		_, err = writer.Write(header, make([]byte, 100), nil)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []uint16{0, 1, 2}, sequenceNumbers)
+	assert.Equal(t, []int{300_000, 600_000}, pacer.probes)
+
+	// The link is probed at twice the target bitrate while application limited.
+	now := time.Now()
+	for range 60 {
+		bwe.onSent(now, 100, 0)
+		now = now.Add(100 * time.Millisecond)
+	}
+	assert.Equal(t, []int{300_000, 600_000, 200_000, 200_000}, pacer.probes)
+}
+
+func TestSendSideBWE_ProbeResult(t *testing.T) {
+	pacer := &mockProbingPacer{NoOpPacer: NewNoOpPacer()}
+	bwe, err := NewSendSideBWE(SendSideBWEInitialBitrate(100_000), SendSideBWEPacer(pacer))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, bwe.Close())
+	}()
+	writer := bwe.AddStream(&interceptor.StreamInfo{SSRC: 1}, interceptor.RTPWriterFunc(
+		func(*rtp.Header, []byte, interceptor.Attributes) (int, error) {
+			return 0, nil
+		},
+	))
+	_, err = writer.Write(&rtp.Header{SSRC: 1}, make([]byte, 100), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{300_000, 600_000}, pacer.probes)
+
+	// The target bitrate jumps to the delivered bitrate, twice the bitrate of a
+	// delivered startup probe is probed next, once all of its packets are
+	// acknowledged.
+	bwe.onProbeResult(probeResult{id: 2, bitrate: 500_000})
+	assert.Equal(t, 500_000, bwe.GetTargetBitrate())
+	assert.Len(t, pacer.probes, 2)
+	bwe.onProbeResult(probeResult{id: 2, bitrate: 590_000, complete: true})
+	assert.Equal(t, 590_000, bwe.GetTargetBitrate())
+	assert.Equal(t, 590_000, bwe.GetStats()["probeBitrate"])
+	assert.Equal(t, []int{300_000, 600_000, 1_180_000}, pacer.probes)
+
+	// Startup probing stops once a probe is not delivered.
+	bwe.onProbeResult(probeResult{id: 3, bitrate: 700_000, complete: true})
+	assert.Equal(t, 700_000, bwe.GetTargetBitrate())
+	assert.Len(t, pacer.probes, 3)
+
+	// Probes below the target bitrate are ignored.
+	bwe.onProbeResult(probeResult{id: 1, bitrate: 250_000, complete: true})
+	assert.Equal(t, 700_000, bwe.GetTargetBitrate())
+	assert.Len(t, pacer.probes, 3)
+
+	bwe.OnNetworkChange()
+	assert.Equal(t, []int{300_000, 600_000, 1_180_000, 2_100_000, 4_200_000}, pacer.probes)
+}
+
+func TestSendSideBWE_ALR(t *testing.T) {
+	bwe, err := NewSendSideBWE(SendSideBWEInitialBitrate(1_000_000), SendSideBWEPacer(NewNoOpPacer()))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, bwe.Close())
+	}()
+
+	// Sending at a tenth of the target bitrate.
+	now := time.Now()
+	for range 200 {
+		bwe.onSent(now, 1250, 0)
+		now = now.Add(100 * time.Millisecond)
+	}
+	stats := bwe.Stats()
+	assert.True(t, stats.InALR)
+	assert.False(t, stats.ALRStart.IsZero())
+	assert.Equal(t, 100_000, stats.SendBitrate)
+	assert.Equal(t, true, bwe.GetStats()["inALR"])
+	assert.Equal(t, 100_000, bwe.GetStats()["alrSendBitrate"])
+	assert.Equal(t, 150_000, bwe.delayController.increaseCap)
+
+	// Sending at the target bitrate.
+	for range 200 {
+		bwe.onSent(now, 12500, 0)
+		now = now.Add(100 * time.Millisecond)
+	}
+	assert.False(t, bwe.Stats().InALR)
+	assert.Equal(t, 0, bwe.delayController.increaseCap)
+}
+
+func TestSendSideBWE_ECN(t *testing.T) {
+	bwe, err := NewSendSideBWE(
+		SendSideBWEInitialBitrate(1_000_000), SendSideBWEPacer(NewNoOpPacer()), SendSideBWEMarkECT1(),
+	)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, bwe.Close())
+	}()
+
+	var marks []any
+	writer := bwe.AddStream(&interceptor.StreamInfo{SSRC: 1}, interceptor.RTPWriterFunc(
+		func(_ *rtp.Header, _ []byte, attributes interceptor.Attributes) (int, error) {
+			marks = append(marks, attributes.Get(interceptor.ECNAttributesKey))
+
+			return 0, nil
+		},
+	))
+	for i := range 20 {
+		_, err = writer.Write(&rtp.Header{SSRC: 1, SequenceNumber: uint16(i)}, make([]byte, 1000), nil) //nolint:gosec
+		require.NoError(t, err)
+	}
+	assert.Len(t, marks, 20)
+	for _, mark := range marks {
+		assert.Equal(t, rtcp.ECNECT1, mark)
+	}
+
+	// Half of the packets are received CE marked.
+	feedback := func(begin uint16) []rtcp.Packet {
+		metricBlocks := make([]rtcp.CCFeedbackMetricBlock, 10)
+		for i := range metricBlocks {
+			metricBlocks[i] = rtcp.CCFeedbackMetricBlock{Received: true, ECN: rtcp.ECNECT1}
+			if i%2 == 0 {
+				metricBlocks[i].ECN = rtcp.ECNCE
+			}
+		}
+
+		return []rtcp.Packet{&rtcp.CCFeedbackReport{
+			ReportBlocks:    []rtcp.CCFeedbackReportBlock{{MediaSSRC: 1, BeginSequence: begin, MetricBlocks: metricBlocks}},
+			ReportTimestamp: uint32(ntp.ToNTP(time.Now()) >> 16), //nolint:gosec // G115
+		}}
+	}
+	require.NoError(t, bwe.WriteRTCP(feedback(0), nil))
+	assert.Equal(t, 1_000_000, bwe.GetTargetBitrate())
+
+	// The marks reduce the target bitrate once the window ends.
+	bwe.lock.Lock()
+	bwe.ecnController.windowStart = time.Now().Add(-time.Second)
+	bwe.lock.Unlock()
+	require.NoError(t, bwe.WriteRTCP(feedback(10), nil))
+
+	stats := bwe.Stats()
+	assert.Equal(t, 0.5, stats.MarkedRatio)
+	assert.InDelta(t, 1+ecnAlphaGain*(0.5-1), stats.Alpha, 1e-9)
+	assert.Less(t, bwe.GetTargetBitrate(), 1_000_000)
+	assert.Equal(t, 0.5, bwe.GetStats()["ecnMarkedRatio"])
+}
+
+func TestSendSideBWE_Clock(t *testing.T) {
+	clock := netem.NewVirtualClock(time.Unix(1_700_000_000, 0))
+	bwe, err := NewSendSideBWE(
+		SendSideBWEInitialBitrate(300_000), SendSideBWEPacer(NewNoOpPacer()), SendSideBWEClock(clock),
+		SendSideBWENumberPackets(),
+	)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, bwe.Close())
+	}()
+
+	// A 1 Mbit/s bottleneck, the feedback is sent every 100ms by the receiver.
+	link := netem.NewLink(clock, netem.LinkConfig{Bandwidth: 1_000_000, QueueSize: 50_000, Delay: 20 * time.Millisecond})
+	recorder := twcc.NewRecorder(5000)
+	start := clock.Now()
+	writer := bwe.AddStream(&interceptor.StreamInfo{
+		SSRC:                1,
+		RTPHeaderExtensions: []interceptor.RTPHeaderExtension{{URI: cc.TransportCCURI, ID: 1}},
+	}, interceptor.RTPWriterFunc(func(header *rtp.Header, payload []byte, _ interceptor.Attributes) (int, error) {
+		data, err := (&rtp.Packet{Header: *header, Payload: payload}).Marshal()
+		if err != nil {
+			return 0, err
+		}
+		link.Send(data, rtcp.ECNNonECT, func(data []byte, _ rtcp.ECN) {
+			var received rtp.Header
+			_, err := received.Unmarshal(data)
+			require.NoError(t, err)
+			var tcc rtp.TransportCCExtension
+			require.NoError(t, tcc.Unmarshal(received.GetExtension(1)))
+			recorder.Record(received.SSRC, tcc.TransportSequence, clock.Now().Sub(start).Microseconds())
+		})
+
+		return len(data), nil
+	}))
+
+	// The source sends at the target bitrate for 30 seconds of simulated time.
+	var sequenceNumber uint16
+	budget := 0.0
+	for step := range 3000 {
+		budget += float64(bwe.GetTargetBitrate()) / 8 / 100
+		for ; budget >= 1200; budget -= 1200 {
+			_, err = writer.Write(&rtp.Header{SSRC: 1, SequenceNumber: sequenceNumber}, make([]byte, 1200), nil)
+			require.NoError(t, err)
+			sequenceNumber++
+		}
+		if step%10 == 0 {
+			feedback := recorder.BuildFeedbackPacket()
+			clock.AfterFunc(20*time.Millisecond, func() {
+				assert.NoError(t, bwe.WriteRTCP(feedback, nil))
+			})
+		}
+		clock.Advance(10 * time.Millisecond)
+	}
+
+	// The estimate ramps up to the bottleneck, without filling its queue for long.
+	stats := link.Stats()
+	assert.Greater(t, float64(stats.Delivered*1212*8)/30, 600_000.0)
+	assert.Less(t, float64(stats.Dropped), 0.05*float64(stats.Sent))
+	assert.Greater(t, bwe.GetTargetBitrate(), 500_000)
+	assert.Less(t, bwe.GetTargetBitrate(), 1_500_000)
+}
+
 func BenchmarkSendSideBWE_WriteRTCP(b *testing.B) {
 	numSequencesPerTwccReport := []int{10, 100, 500, 1000}
 
@@ -35,6 +35,7 @@ type GeneratorInterceptor struct {
 	interceptor.NoOp
 
 	interval           time.Duration
+	clock              interceptor.Clock
 	streams            sync.Map
 	immediatePLINeeded chan []uint32
 
@@ -50,6 +51,7 @@ type GeneratorInterceptor struct {
 func NewGeneratorInterceptor(opts ...GeneratorOption) (*GeneratorInterceptor, error) {
 	generatorInterceptor := &GeneratorInterceptor{
 		interval:           3 * time.Second,
+		clock:              interceptor.SystemClock(),
 		immediatePLINeeded: make(chan []uint32, 1),
 		close:              make(chan struct{}),
 	}
@@ -147,11 +149,11 @@ func (r *GeneratorInterceptor) loop(rtcpWriter interceptor.RTCPWriter) {
 	}
 }
 
-func (r *GeneratorInterceptor) createLoopTicker() (*time.Ticker, <-chan time.Time) {
+func (r *GeneratorInterceptor) createLoopTicker() (interceptor.Ticker, <-chan time.Time) {
 	if r.interval > 0 {
-		ticker := time.NewTicker(r.interval)
+		ticker := r.clock.NewTicker(r.interval)
 
-		return ticker, ticker.C
+		return ticker, ticker.Ch()
 	}
 
 	return nil, make(chan time.Time)
@@ -6,6 +6,7 @@ package intervalpli
 import (
 	"time"
 
+	"github.com/pion/interceptor"
 	"github.com/pion/logging"
 )
 
@@ -38,3 +39,12 @@ func GeneratorInterval(interval time.Duration) GeneratorOption {
 		return nil
 	}
 }
+
+// GeneratorClock sets the clock the PLIs are sent by.
+func GeneratorClock(clock interceptor.Clock) GeneratorOption {
+	return func(r *GeneratorInterceptor) error {
+		r.clock = clock
+
+		return nil
+	}
+}
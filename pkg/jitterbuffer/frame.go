@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package jitterbuffer
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+// ErrFrameIncomplete is returned if the frame at the playout head misses packets.
+var ErrFrameIncomplete = errors.New("frame at playout head is incomplete")
+
+// Frame holds the packets of a complete frame in sequence number order: every packet
+// sharing the RTP timestamp of the frame, up to the one with the marker bit set.
+type Frame struct {
+	Timestamp uint32
+	Packets   []*rtp.Packet
+}
+
+// PopFrame pops the frame at the current playout head once it is complete.
+// A time based jitter buffer pops the frame if it is due for playout now, see PopFrameDue.
+func (jb *JitterBuffer) PopFrame() (*Frame, error) {
+	return jb.PopFrameDue(jb.clock.Now())
+}
+
+// PopFrameDue pops the frame at the current playout head once it is complete and, for a
+// time based jitter buffer, due for playout at the given time. ErrFrameIncomplete is
+// returned while packets of the frame are missing.
+//
+// An incomplete frame is skipped when the next frame is due for playout or, without clock
+// rate, when the jitter buffer holds as many packets as it buffers before playback. The
+// FrameSkipped event is emitted, the consumer can not decode the following frames until
+// the next keyframe. The next frame is skipped as well when the packet before it was lost,
+// as its first packets may be missing.
+func (jb *JitterBuffer) PopFrameDue(now time.Time) (*Frame, error) {
+	jb.mutex.Lock()
+	defer jb.mutex.Unlock()
+	if jb.state != Emitting {
+		return nil, ErrPopWhileBuffering
+	}
+
+	for {
+		first, err := jb.packets.findNext(jb.playoutHead)
+		if err != nil {
+			jb.stats.UnderflowCount++
+			jb.emit(BufferUnderflow)
+
+			return nil, err
+		}
+		if jb.delay != nil && now.Before(jb.delay.playoutTime(first.Timestamp)) {
+			return nil, ErrPacketNotDue
+		}
+
+		if packets, ok := jb.assembleFrame(first); ok {
+			for _, packet := range packets {
+				_, _ = jb.packets.PopAt(packet.SequenceNumber)
+			}
+			jb.playoutHead = packets[len(packets)-1].SequenceNumber + 1
+			jb.playedOut = true
+			jb.frameStart = true
+
+			return &Frame{Timestamp: first.Timestamp, Packets: packets}, nil
+		}
+
+		next, err := jb.packets.findNextTimestamp(jb.playoutHead, first.Timestamp)
+		if err != nil || !jb.canSkipTo(next, now) {
+			return nil, ErrFrameIncomplete
+		}
+		jb.skipTo(next.SequenceNumber)
+	}
+}
+
+// nextFramePlayoutTime returns the playout time of the frame after the one at the playout
+// head, at which an incomplete frame at the playout head is skipped.
+func (jb *JitterBuffer) nextFramePlayoutTime() (time.Time, bool) {
+	jb.mutex.Lock()
+	defer jb.mutex.Unlock()
+	if jb.delay == nil {
+		return time.Time{}, false
+	}
+	first, err := jb.packets.findNext(jb.playoutHead)
+	if err != nil {
+		return time.Time{}, false
+	}
+	next, err := jb.packets.findNextTimestamp(jb.playoutHead, first.Timestamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return jb.delay.playoutTime(next.Timestamp), true
+}
+
+// assembleFrame returns the packets of the frame starting with first, if it is complete.
+func (jb *JitterBuffer) assembleFrame(first *rtp.Packet) ([]*rtp.Packet, bool) {
+	// The missing packets at the playout head may be the start of the frame, and
+	// so may be the ones lost before it when the previous frame was skipped.
+	if jb.playedOut && (first.SequenceNumber != jb.playoutHead || !jb.frameStart) {
+		return nil, false
+	}
+
+	packets := []*rtp.Packet{first}
+	for packet := first; !packet.Marker; {
+		next, err := jb.packets.Find(packet.SequenceNumber + 1)
+		if err != nil {
+			return nil, false
+		}
+		// A new timestamp also ends the frame, for senders not setting the marker bit.
+		if next.Timestamp != first.Timestamp {
+			break
+		}
+		packets = append(packets, next)
+		packet = next
+	}
+
+	return packets, true
+}
+
+func (jb *JitterBuffer) canSkipTo(next *rtp.Packet, now time.Time) bool {
+	if jb.delay != nil {
+		return !now.Before(jb.delay.playoutTime(next.Timestamp))
+	}
+
+	return jb.packets.Length() >= jb.minStartCount
+}
+
+// skipTo drops the packets before sequence number sqNum and moves the playout head to it.
+// The packet at sqNum starts a frame only if the one before it was received, which then
+// ends the skipped frame.
+func (jb *JitterBuffer) skipTo(sqNum uint16) {
+	_, err := jb.packets.Find(sqNum - 1)
+	jb.frameStart = err == nil
+	for seq := jb.playoutHead; seq != sqNum; seq++ {
+		_, _ = jb.packets.PopAt(seq)
+	}
+	jb.playoutHead = sqNum
+	jb.playedOut = true
+	jb.stats.SkippedFrameCount++
+	jb.emit(FrameSkipped)
+}
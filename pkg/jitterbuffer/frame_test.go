@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package jitterbuffer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func framePacket(seq uint16, timestamp uint32, marker bool) *rtp.Packet {
+	return &rtp.Packet{
+		Header:  rtp.Header{SequenceNumber: seq, Timestamp: timestamp, Marker: marker},
+		Payload: []byte{byte(seq)},
+	}
+}
+
+func frameSequenceNumbers(frame *Frame) []uint16 {
+	seqs := make([]uint16, 0, len(frame.Packets))
+	for _, packet := range frame.Packets {
+		seqs = append(seqs, packet.SequenceNumber)
+	}
+
+	return seqs
+}
+
+func TestJitterBuffer_PopFrame(t *testing.T) {
+	jb := New(WithMinimumPacketCount(1))
+	_, err := jb.PopFrame()
+	assert.ErrorIs(t, err, ErrPopWhileBuffering)
+
+	jb.Push(framePacket(65535, 100, false))
+	jb.Push(framePacket(0, 100, true))
+	frame, err := jb.PopFrame()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(100), frame.Timestamp)
+	assert.Equal(t, []uint16{65535, 0}, frameSequenceNumbers(frame))
+
+	// The frame is only popped once its gap is filled.
+	jb.Push(framePacket(1, 200, false))
+	jb.Push(framePacket(3, 200, true))
+	_, err = jb.PopFrame()
+	assert.ErrorIs(t, err, ErrFrameIncomplete)
+
+	jb.Push(framePacket(2, 200, false))
+	frame, err = jb.PopFrame()
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{1, 2, 3}, frameSequenceNumbers(frame))
+
+	// Without marker bit, a new timestamp ends the frame.
+	jb.Push(framePacket(4, 300, false))
+	jb.Push(framePacket(5, 400, false))
+	frame, err = jb.PopFrame()
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{4}, frameSequenceNumbers(frame))
+
+	_, err = jb.PopFrame()
+	assert.ErrorIs(t, err, ErrFrameIncomplete)
+}
+
+func TestJitterBuffer_SkipsIncompleteFrames(t *testing.T) {
+	skipped := 0
+	jb := New(WithMinimumPacketCount(4))
+	jb.Listen(FrameSkipped, func(Event, *JitterBuffer) {
+		skipped++
+	})
+
+	jb.Push(framePacket(0, 100, false))
+	jb.Push(framePacket(1, 100, true))
+	jb.Push(framePacket(3, 200, true))
+	jb.Push(framePacket(4, 300, false))
+	jb.Push(framePacket(5, 300, true))
+
+	frame, err := jb.PopFrame()
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{0, 1}, frameSequenceNumbers(frame))
+
+	// The start of the frame at the playout head is missing.
+	_, err = jb.PopFrame()
+	assert.ErrorIs(t, err, ErrFrameIncomplete)
+
+	// It is skipped once the buffer holds as many packets as it buffers before playback.
+	jb.Push(framePacket(6, 400, false))
+	jb.Push(framePacket(7, 400, true))
+	frame, err = jb.PopFrame()
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{4, 5}, frameSequenceNumbers(frame))
+	assert.Equal(t, 1, skipped)
+	assert.Equal(t, uint32(1), jb.Stats().SkippedFrameCount)
+
+	jb.Push(framePacket(2, 200, false))
+	assert.Equal(t, uint32(1), jb.Stats().LateCount)
+}
+
+func TestJitterBuffer_PopFrameDue(t *testing.T) {
+	start := time.Unix(1000, 0)
+	jb := New(WithClockRate(90000), WithPlayoutDelay(10*time.Millisecond, 10*time.Millisecond))
+
+	jb.PushAt(framePacket(0, 0, false), start)
+	jb.PushAt(framePacket(1, 0, true), start)
+	jb.PushAt(framePacket(3, 1800, true), start.Add(20*time.Millisecond))
+	jb.PushAt(framePacket(4, 3600, false), start.Add(40*time.Millisecond))
+	jb.PushAt(framePacket(5, 3600, true), start.Add(40*time.Millisecond))
+
+	_, err := jb.PopFrameDue(start)
+	assert.ErrorIs(t, err, ErrPacketNotDue)
+
+	frame, err := jb.PopFrameDue(start.Add(10 * time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{0, 1}, frameSequenceNumbers(frame))
+
+	_, err = jb.PopFrameDue(start.Add(30 * time.Millisecond))
+	assert.ErrorIs(t, err, ErrFrameIncomplete)
+	skip, ok := jb.nextFramePlayoutTime()
+	assert.True(t, ok)
+	assert.Equal(t, start.Add(50*time.Millisecond), skip)
+
+	// The incomplete frame is skipped once the next one is due.
+	frame, err = jb.PopFrameDue(skip)
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{4, 5}, frameSequenceNumbers(frame))
+	assert.Equal(t, uint32(1), jb.Stats().SkippedFrameCount)
+}
+
+func TestJitterBuffer_SkipsFramesAfterLoss(t *testing.T) {
+	start := time.Unix(1000, 0)
+	jb := New(WithClockRate(90000), WithPlayoutDelay(10*time.Millisecond, 10*time.Millisecond))
+
+	// 11 and 12 are lost, the frame of 13 may have started with them.
+	jb.PushAt(framePacket(10, 100, false), start)
+	jb.PushAt(framePacket(13, 200, true), start)
+	jb.PushAt(framePacket(14, 300, false), start)
+	jb.PushAt(framePacket(15, 300, true), start)
+
+	frame, err := jb.PopFrameDue(start.Add(10 * time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, uint32(300), frame.Timestamp)
+	assert.Equal(t, []uint16{14, 15}, frameSequenceNumbers(frame))
+	assert.Equal(t, uint32(2), jb.Stats().SkippedFrameCount)
+}
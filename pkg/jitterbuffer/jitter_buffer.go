@@ -8,7 +8,9 @@ package jitterbuffer
 import (
 	"errors"
 	"sync"
+	"time"
 
+	"github.com/pion/interceptor"
 	"github.com/pion/rtp"
 )
 
@@ -23,6 +25,10 @@ var (
 	ErrBufferUnderrun = errors.New("invalid Peek: Empty jitter buffer")
 	// ErrPopWhileBuffering is returned if a jitter buffer is not in a playback state.
 	ErrPopWhileBuffering = errors.New("attempt to pop while buffering")
+	// ErrPacketNotDue is returned if the next packet of a time based jitter buffer is not due for playout yet.
+	ErrPacketNotDue = errors.New("packet not due for playout")
+	// ErrNoClockRate is returned if a time based operation is performed on a jitter buffer without clock rate.
+	ErrNoClockRate = errors.New("jitter buffer has no clock rate")
 )
 
 const (
@@ -42,6 +48,8 @@ const (
 	BufferUnderflow = "underflow"
 	// BufferOverflow is emitted when the buffer has exceeded its limit.
 	BufferOverflow = "overflow"
+	// FrameSkipped is emitted when an incomplete frame is skipped by PopFrame.
+	FrameSkipped = "frameSkipped"
 )
 
 func (jbs State) String() string {
@@ -65,6 +73,11 @@ type (
 // A JitterBuffer will accept Pushed packets, put them in sequence number
 // order, and allows removing in either sequence number order or via a
 // provided timestamp.
+//
+// When created with WithClockRate, the JitterBuffer is time based: instead of
+// waiting for a number of packets, it holds every packet back until its
+// playout time. The playout delay adapts to the interarrival jitter of the
+// pushed packets, within the bounds set by WithPlayoutDelay.
 type JitterBuffer struct {
 	packets       *PriorityQueue
 	minStartCount uint16
@@ -72,40 +85,61 @@ type JitterBuffer struct {
 	lastSequence  uint16
 	playoutHead   uint16
 	playoutReady  bool
+	playedOut     bool
+	frameStart    bool
 	state         State
 	stats         Stats
 	listeners     map[Event][]EventListener
+	clockRate     uint32
+	minDelay      time.Duration
+	maxDelay      time.Duration
+	delay         *delayEstimator
+	clock         interceptor.Clock
 	mutex         sync.Mutex
 }
 
 // Stats Track interesting statistics for the life of this JitterBuffer
-// outOfOrderCount will provide the number of times a packet was Pushed
+// OutOfOrderCount will provide the number of times a packet was Pushed
 //
 //	without its predecessor being present
 //
-// underflowCount will provide the count of attempts to Pop an empty buffer
-// overflowCount will track the number of times the jitter buffer exceeds its limit.
+// UnderflowCount will provide the count of attempts to Pop an empty buffer
+// OverflowCount will track the number of times the jitter buffer exceeds its limit.
+// LateCount will track the packets dropped as they arrived after their playout time
+// SkippedFrameCount will track the number of times PopFrame skipped an incomplete frame
+// Jitter and TargetDelay are the interarrival jitter and the playout delay of a time based buffer.
 type Stats struct {
-	outOfOrderCount uint32
-	underflowCount  uint32
-	overflowCount   uint32
+	OutOfOrderCount   uint32
+	UnderflowCount    uint32
+	OverflowCount     uint32
+	LateCount         uint32
+	SkippedFrameCount uint32
+	Jitter            time.Duration
+	TargetDelay       time.Duration
 }
 
 // New will initialize a jitter buffer and its associated statistics.
 func New(opts ...Option) *JitterBuffer {
 	jb := &JitterBuffer{
 		state:         Buffering,
-		stats:         Stats{0, 0, 0},
+		stats:         Stats{},
 		minStartCount: 50,
 		overflowLen:   100,
 		packets:       NewQueue(),
 		listeners:     make(map[Event][]EventListener),
+		minDelay:      defaultMinPlayoutDelay,
+		maxDelay:      defaultMaxPlayoutDelay,
+		clock:         interceptor.SystemClock(),
 	}
 
 	for _, o := range opts {
 		o(jb)
 	}
 
+	if jb.clockRate != 0 {
+		jb.delay = newDelayEstimator(jb.clockRate, jb.minDelay, jb.maxDelay)
+	}
+
 	return jb
 }
 
@@ -117,6 +151,29 @@ func WithMinimumPacketCount(count uint16) Option {
 	}
 }
 
+// WithClockRate makes the jitter buffer time based, the clock rate is the one of the
+// RTP timestamps of the pushed packets.
+func WithClockRate(clockRate uint32) Option {
+	return func(jb *JitterBuffer) {
+		jb.clockRate = clockRate
+	}
+}
+
+// WithPlayoutDelay will set the bounds of the playout delay of a time based jitter buffer.
+func WithPlayoutDelay(minDelay, maxDelay time.Duration) Option {
+	return func(jb *JitterBuffer) {
+		jb.minDelay = minDelay
+		jb.maxDelay = max(minDelay, maxDelay)
+	}
+}
+
+// WithBufferClock sets the clock Push, Pop and PopFrame take the current time from.
+func WithBufferClock(clock interceptor.Clock) Option {
+	return func(jb *JitterBuffer) {
+		jb.clock = clock
+	}
+}
+
 // Listen will register an event listener
 // The jitter buffer may emit events correspnding, interested listerns should
 // look at Event for available events.
@@ -124,6 +181,20 @@ func (jb *JitterBuffer) Listen(event Event, cb EventListener) {
 	jb.listeners[event] = append(jb.listeners[event], cb)
 }
 
+// Stats returns the statistics of the JitterBuffer.
+func (jb *JitterBuffer) Stats() Stats {
+	jb.mutex.Lock()
+	defer jb.mutex.Unlock()
+
+	stats := jb.stats
+	if jb.delay != nil {
+		stats.Jitter = jb.delay.jitter
+		stats.TargetDelay = jb.delay.targetDelay
+	}
+
+	return stats
+}
+
 // PlayoutHead returns the SequenceNumber that will be attempted to Pop next.
 func (jb *JitterBuffer) PlayoutHead() uint16 {
 	jb.mutex.Lock()
@@ -145,7 +216,7 @@ func (jb *JitterBuffer) updateStats(lastPktSeqNo uint16) {
 	// If we have at least one packet, and the next packet being pushed in is not
 	// at the expected sequence number increment the out of order count
 	if jb.packets.Length() > 0 && lastPktSeqNo != (jb.lastSequence+1) {
-		jb.stats.outOfOrderCount++
+		jb.stats.OutOfOrderCount++
 	}
 	jb.lastSequence = lastPktSeqNo
 }
@@ -154,15 +225,39 @@ func (jb *JitterBuffer) updateStats(lastPktSeqNo uint16) {
 // the data so if the memory is expected to be reused, the caller should
 // take this in to account and pass a copy of the packet they wish to buffer.
 func (jb *JitterBuffer) Push(packet *rtp.Packet) {
+	jb.push(packet, jb.clock.Now())
+}
+
+// PushAt pushes an RTP packet which arrived at the given time into the jitter buffer,
+// see Push.
+func (jb *JitterBuffer) PushAt(packet *rtp.Packet, arrival time.Time) {
+	jb.push(packet, arrival)
+}
+
+// push returns false if the packet was dropped as it arrived after its playout time.
+func (jb *JitterBuffer) push(packet *rtp.Packet, arrival time.Time) bool {
 	jb.mutex.Lock()
 	defer jb.mutex.Unlock()
 
+	// Once time based or frame playout started, packets older than the playout head are too late.
+	if jb.playedOut && isOlder(packet.SequenceNumber, jb.playoutHead) {
+		jb.stats.LateCount++
+
+		return false
+	}
+	if jb.delay != nil {
+		if !jb.playedOut && isOlder(packet.SequenceNumber, jb.playoutHead) {
+			jb.playoutHead = packet.SequenceNumber
+		}
+		jb.delay.update(arrival, packet.Timestamp)
+	}
+
 	if jb.packets.Length() == 0 {
 		jb.emit(StartBuffering)
 	}
 
 	if jb.packets.Length() > jb.overflowLen {
-		jb.stats.overflowCount++
+		jb.stats.OverflowCount++
 		jb.emit(BufferOverflow)
 	}
 
@@ -173,6 +268,13 @@ func (jb *JitterBuffer) Push(packet *rtp.Packet) {
 	jb.updateStats(packet.SequenceNumber)
 	jb.packets.Push(packet, packet.SequenceNumber)
 	jb.updateState()
+
+	return true
+}
+
+// isOlder returns true if the sequence number a is before b.
+func isOlder(a, b uint16) bool {
+	return int16(a-b) < 0 //nolint:gosec // G115
 }
 
 func (jb *JitterBuffer) emit(event Event) {
@@ -182,8 +284,13 @@ func (jb *JitterBuffer) emit(event Event) {
 }
 
 func (jb *JitterBuffer) updateState() {
-	// For now, we only look at the number of packets captured in the play buffer
-	if jb.packets.Length() >= jb.minStartCount && jb.state == Buffering {
+	// Without clock rate, we only look at the number of packets captured in the play buffer.
+	// Time based buffers hold every packet back until its playout time instead.
+	ready := jb.packets.Length() >= jb.minStartCount
+	if jb.delay != nil {
+		ready = jb.packets.Length() > 0
+	}
+	if ready && jb.state == Buffering {
 		jb.state = Emitting
 		jb.playoutReady = true
 		jb.emit(BeginPlayback)
@@ -211,15 +318,26 @@ func (jb *JitterBuffer) Peek(playoutHead bool) (*rtp.Packet, error) {
 }
 
 // Pop an RTP packet from the jitter buffer at the current playout head.
+// A time based jitter buffer pops the next packet if it is due for playout now, see PopDue.
 func (jb *JitterBuffer) Pop() (*rtp.Packet, error) {
+	return jb.PopDue(jb.clock.Now())
+}
+
+// PopDue pops the next RTP packet of a time based jitter buffer if it is due for playout
+// at the given time, ErrPacketNotDue is returned otherwise. Missing packets whose playout
+// time passed are skipped. Without clock rate, the time is ignored and PopDue behaves like Pop.
+func (jb *JitterBuffer) PopDue(now time.Time) (*rtp.Packet, error) {
 	jb.mutex.Lock()
 	defer jb.mutex.Unlock()
+	if jb.delay != nil {
+		return jb.popDue(now)
+	}
 	if jb.state != Emitting {
 		return nil, ErrPopWhileBuffering
 	}
 	packet, err := jb.packets.PopAt(jb.playoutHead)
 	if err != nil {
-		jb.stats.underflowCount++
+		jb.stats.UnderflowCount++
 		jb.emit(BufferUnderflow)
 
 		return nil, err
@@ -230,6 +348,47 @@ func (jb *JitterBuffer) Pop() (*rtp.Packet, error) {
 	return packet, nil
 }
 
+func (jb *JitterBuffer) popDue(now time.Time) (*rtp.Packet, error) {
+	if jb.state != Emitting {
+		return nil, ErrPopWhileBuffering
+	}
+	next, err := jb.packets.findNext(jb.playoutHead)
+	if err != nil {
+		jb.stats.UnderflowCount++
+		jb.emit(BufferUnderflow)
+
+		return nil, err
+	}
+	if now.Before(jb.delay.playoutTime(next.Timestamp)) {
+		return nil, ErrPacketNotDue
+	}
+
+	packet, err := jb.packets.PopAt(next.SequenceNumber)
+	if err != nil {
+		return nil, err
+	}
+	jb.playoutHead = packet.SequenceNumber + 1
+	jb.playedOut = true
+	jb.frameStart = packet.Marker
+
+	return packet, nil
+}
+
+// NextPlayoutTime returns the time the next packet of a time based jitter buffer is due for playout.
+func (jb *JitterBuffer) NextPlayoutTime() (time.Time, error) {
+	jb.mutex.Lock()
+	defer jb.mutex.Unlock()
+	if jb.delay == nil {
+		return time.Time{}, ErrNoClockRate
+	}
+	next, err := jb.packets.findNext(jb.playoutHead)
+	if err != nil {
+		return time.Time{}, ErrBufferUnderrun
+	}
+
+	return jb.delay.playoutTime(next.Timestamp), nil
+}
+
 // PopAtSequence will pop an RTP packet from the jitter buffer at the specified Sequence.
 func (jb *JitterBuffer) PopAtSequence(sq uint16) (*rtp.Packet, error) {
 	jb.mutex.Lock()
@@ -239,7 +398,7 @@ func (jb *JitterBuffer) PopAtSequence(sq uint16) (*rtp.Packet, error) {
 	}
 	packet, err := jb.packets.PopAt(sq)
 	if err != nil {
-		jb.stats.underflowCount++
+		jb.stats.UnderflowCount++
 		jb.emit(BufferUnderflow)
 
 		return nil, err
@@ -273,7 +432,7 @@ func (jb *JitterBuffer) PopAtTimestamp(ts uint32) (*rtp.Packet, error) {
 	}
 	packet, err := jb.packets.PopAtTimestamp(ts)
 	if err != nil {
-		jb.stats.underflowCount++
+		jb.stats.UnderflowCount++
 		jb.emit(BufferUnderflow)
 
 		return nil, err
@@ -292,7 +451,12 @@ func (jb *JitterBuffer) Clear(resetState bool) {
 	if resetState {
 		jb.lastSequence = 0
 		jb.state = Buffering
-		jb.stats = Stats{0, 0, 0}
+		jb.stats = Stats{}
 		jb.minStartCount = 50
+		jb.playoutReady = false
+		jb.playedOut = false
+		if jb.delay != nil {
+			jb.delay = newDelayEstimator(jb.clockRate, jb.minDelay, jb.maxDelay)
+		}
 	}
 }
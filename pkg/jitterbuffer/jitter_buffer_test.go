@@ -6,7 +6,9 @@ package jitterbuffer
 import (
 	"math"
 	"testing"
+	"time"
 
+	"github.com/pion/interceptor/pkg/netem"
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/assert"
 )
@@ -26,7 +28,7 @@ func TestJitterBuffer(t *testing.T) {
 
 		jb.Push(&rtp.Packet{Header: rtp.Header{SequenceNumber: 5012, Timestamp: 512}, Payload: []byte{0x02}})
 
-		assert.Equal(jb.stats.outOfOrderCount, uint32(1))
+		assert.Equal(jb.stats.OutOfOrderCount, uint32(1))
 		assert.Equal(jb.packets.Length(), uint16(4))
 		assert.Equal(jb.lastSequence, uint16(5012))
 	})
@@ -275,7 +277,105 @@ func TestJitterBuffer(t *testing.T) {
 		assert.Equal(jb.lastSequence, uint16(5002))
 		jb.Clear(true)
 		assert.Equal(jb.lastSequence, uint16(0))
-		assert.Equal(jb.stats.outOfOrderCount, uint32(0))
+		assert.Equal(jb.stats.OutOfOrderCount, uint32(0))
 		assert.Equal(jb.packets.Length(), uint16(0))
 	})
 }
+
+func TestJitterBuffer_TimeBased(t *testing.T) {
+	const frameDuration = 20 * time.Millisecond
+	start := time.Unix(1000, 0)
+	packetAt := func(seq uint16) *rtp.Packet {
+		return &rtp.Packet{Header: rtp.Header{SequenceNumber: seq, Timestamp: 3000 + uint32(seq)*1800}}
+	}
+	arrivalOf := func(seq uint16) time.Time {
+		return start.Add(time.Duration(seq) * frameDuration)
+	}
+
+	t.Run("Releases packets when due", func(t *testing.T) {
+		jb := New(WithClockRate(90000), WithPlayoutDelay(10*time.Millisecond, time.Second))
+		_, err := jb.NextPlayoutTime()
+		assert.ErrorIs(t, err, ErrBufferUnderrun)
+
+		jb.PushAt(packetAt(0), arrivalOf(0))
+		next, err := jb.NextPlayoutTime()
+		assert.NoError(t, err)
+		assert.Equal(t, arrivalOf(0).Add(10*time.Millisecond), next)
+
+		_, err = jb.PopDue(arrivalOf(0).Add(5 * time.Millisecond))
+		assert.ErrorIs(t, err, ErrPacketNotDue)
+
+		packet, err := jb.PopDue(next)
+		assert.NoError(t, err)
+		assert.Equal(t, uint16(0), packet.SequenceNumber)
+		assert.Equal(t, 10*time.Millisecond, jb.Stats().TargetDelay)
+	})
+
+	t.Run("Takes the time from its clock", func(t *testing.T) {
+		clock := netem.NewVirtualClock(arrivalOf(0))
+		jb := New(WithClockRate(90000), WithPlayoutDelay(10*time.Millisecond, time.Second), WithBufferClock(clock))
+		jb.Push(packetAt(0))
+
+		_, err := jb.Pop()
+		assert.ErrorIs(t, err, ErrPacketNotDue)
+
+		clock.Advance(10 * time.Millisecond)
+		packet, err := jb.Pop()
+		assert.NoError(t, err)
+		assert.Equal(t, uint16(0), packet.SequenceNumber)
+	})
+
+	t.Run("Adapts the delay to the jitter", func(t *testing.T) {
+		jb := New(WithClockRate(90000), WithPlayoutDelay(10*time.Millisecond, 50*time.Millisecond))
+		for seq := range uint16(200) {
+			arrival := arrivalOf(seq)
+			if seq%2 == 1 {
+				arrival = arrival.Add(15 * time.Millisecond)
+			}
+			jb.PushAt(packetAt(seq), arrival)
+		}
+		jittery := jb.Stats()
+		assert.Greater(t, jittery.Jitter, 10*time.Millisecond)
+		assert.Equal(t, 50*time.Millisecond, jittery.TargetDelay)
+
+		// The playout time follows the lowest transit delay.
+		next, err := jb.NextPlayoutTime()
+		assert.NoError(t, err)
+		assert.Equal(t, arrivalOf(0).Add(50*time.Millisecond), next)
+
+		for seq := range uint16(100) {
+			jb.PushAt(packetAt(200+seq), arrivalOf(200+seq))
+		}
+		steady := jb.Stats()
+		assert.Less(t, steady.Jitter, time.Millisecond)
+		assert.Less(t, steady.TargetDelay, jittery.TargetDelay)
+		assert.Greater(t, steady.TargetDelay, 10*time.Millisecond)
+	})
+
+	t.Run("Skips missing and drops late packets", func(t *testing.T) {
+		jb := New(WithClockRate(90000), WithPlayoutDelay(10*time.Millisecond, 10*time.Millisecond))
+
+		// Reordered packets are played out in order.
+		jb.PushAt(packetAt(1), arrivalOf(1))
+		jb.PushAt(packetAt(0), arrivalOf(1))
+		jb.PushAt(packetAt(3), arrivalOf(3))
+		for _, seq := range []uint16{0, 1, 3} {
+			packet, err := jb.PopDue(arrivalOf(3).Add(10 * time.Millisecond))
+			assert.NoError(t, err)
+			assert.Equal(t, seq, packet.SequenceNumber)
+		}
+
+		jb.PushAt(packetAt(2), arrivalOf(4))
+		assert.Equal(t, uint32(1), jb.Stats().LateCount)
+		assert.Equal(t, uint16(4), jb.PlayoutHead())
+
+		_, err := jb.PopDue(arrivalOf(4))
+		assert.ErrorIs(t, err, ErrInvalidOperation)
+		assert.Equal(t, uint32(1), jb.Stats().UnderflowCount)
+	})
+
+	t.Run("Requires a clock rate", func(t *testing.T) {
+		_, err := New().NextPlayoutTime()
+		assert.ErrorIs(t, err, ErrNoClockRate)
+	})
+}
@@ -4,9 +4,18 @@
 package jitterbuffer
 
 import (
+	"errors"
+	"time"
+
+	"github.com/pion/interceptor"
 	"github.com/pion/logging"
 )
 
+var (
+	errNegativePlayoutDelay = errors.New("playout delay must not be negative")
+	errInvalidPlayoutDelay  = errors.New("minimum playout delay must not exceed the maximum playout delay")
+)
+
 // ReceiverInterceptorOption can be used to configure ReceiverInterceptor.
 type ReceiverInterceptorOption func(d *ReceiverInterceptor) error
 
@@ -27,3 +36,42 @@ func WithLoggerFactory(loggerFactory logging.LoggerFactory) ReceiverInterceptorO
 		return nil
 	}
 }
+
+// WithClock sets the clock the packet arrivals and their playout are timed by.
+func WithClock(clock interceptor.Clock) ReceiverInterceptorOption {
+	return func(d *ReceiverInterceptor) error {
+		d.clock = clock
+
+		return nil
+	}
+}
+
+// WithAdaptivePlayoutDelay makes the interceptor release the packets of remote streams
+// with a clock rate when they are due for playout, with a playout delay adapting to the
+// interarrival jitter within the given bounds. The bounds must not be negative, and
+// minDelay must not exceed maxDelay.
+func WithAdaptivePlayoutDelay(minDelay, maxDelay time.Duration) ReceiverInterceptorOption {
+	return func(d *ReceiverInterceptor) error {
+		if minDelay < 0 || maxDelay < 0 {
+			return errNegativePlayoutDelay
+		}
+		if minDelay > maxDelay {
+			return errInvalidPlayoutDelay
+		}
+		d.timeBased = true
+		d.minDelay = minDelay
+		d.maxDelay = maxDelay
+
+		return nil
+	}
+}
+
+// WithFrameAssembly makes the interceptor return the packets of remote streams frame by
+// frame, once a frame is complete. A PLI is sent when an incomplete frame is skipped.
+func WithFrameAssembly() ReceiverInterceptorOption {
+	return func(d *ReceiverInterceptor) error {
+		d.frames = true
+
+		return nil
+	}
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package jitterbuffer
+
+import (
+	"time"
+)
+
+const (
+	// defaultMinPlayoutDelay is the lower bound of the target delay in time based mode.
+	defaultMinPlayoutDelay = 10 * time.Millisecond
+	// defaultMaxPlayoutDelay is the upper bound of the target delay in time based mode.
+	defaultMaxPlayoutDelay = 500 * time.Millisecond
+	// jitterGain is the gain of the interarrival jitter estimate, as in RFC 3550.
+	jitterGain = 16
+	// jitterDelayFactor is the number of jitter estimates covered by the target delay.
+	jitterDelayFactor = 4
+	// delayDecreaseGain slows down the decrease of the target delay, it grows immediately.
+	delayDecreaseGain = 64
+	// maxBaseTimestampDistance is the distance to the reference timestamp after which
+	// the reference is moved forward, long before the signed difference wraps.
+	maxBaseTimestampDistance = 1 << 30
+)
+
+// delayEstimator sizes the playout delay of a JitterBuffer from the interarrival jitter
+// of the pushed packets, see https://datatracker.ietf.org/doc/html/rfc3550#appendix-A.8.
+//
+// The playout time of a packet is the time it would have arrived at with the lowest
+// transit delay seen so far, plus the target delay.
+type delayEstimator struct {
+	clockRate     uint32
+	minDelay      time.Duration
+	maxDelay      time.Duration
+	jitter        time.Duration
+	targetDelay   time.Duration
+	started       bool
+	lastArrival   time.Time
+	lastTimestamp uint32
+	baseArrival   time.Time
+	baseTimestamp uint32
+}
+
+func newDelayEstimator(clockRate uint32, minDelay, maxDelay time.Duration) *delayEstimator {
+	return &delayEstimator{
+		clockRate:   clockRate,
+		minDelay:    minDelay,
+		maxDelay:    maxDelay,
+		targetDelay: minDelay,
+	}
+}
+
+// mediaDuration converts a difference of RTP timestamps to a duration.
+func (e *delayEstimator) mediaDuration(diff uint32) time.Duration {
+	return time.Duration(int32(diff)) * time.Second / time.Duration(e.clockRate) //nolint:gosec // G115
+}
+
+// expectedArrival returns the time a packet with the given timestamp arrives at with the
+// lowest transit delay seen so far.
+func (e *delayEstimator) expectedArrival(timestamp uint32) time.Time {
+	return e.baseArrival.Add(e.mediaDuration(timestamp - e.baseTimestamp))
+}
+
+// playoutTime returns the time a packet with the given timestamp is due for playout.
+func (e *delayEstimator) playoutTime(timestamp uint32) time.Time {
+	return e.expectedArrival(timestamp).Add(e.targetDelay)
+}
+
+func (e *delayEstimator) update(arrival time.Time, timestamp uint32) {
+	if !e.started {
+		e.started = true
+		e.lastArrival, e.lastTimestamp = arrival, timestamp
+		e.baseArrival, e.baseTimestamp = arrival, timestamp
+
+		return
+	}
+
+	transitDiff := arrival.Sub(e.lastArrival) - e.mediaDuration(timestamp-e.lastTimestamp)
+	if transitDiff < 0 {
+		transitDiff = -transitDiff
+	}
+	e.jitter += (transitDiff - e.jitter) / jitterGain
+	e.lastArrival, e.lastTimestamp = arrival, timestamp
+
+	expected := e.expectedArrival(timestamp)
+	switch {
+	case arrival.Before(expected):
+		e.baseArrival, e.baseTimestamp = arrival, timestamp
+	case int32(timestamp-e.baseTimestamp) > maxBaseTimestampDistance: //nolint:gosec // G115
+		e.baseArrival, e.baseTimestamp = expected, timestamp
+	}
+
+	desired := min(max(jitterDelayFactor*e.jitter, e.minDelay), e.maxDelay)
+	if desired > e.targetDelay {
+		e.targetDelay = desired
+	} else {
+		e.targetDelay += (desired - e.targetDelay) / delayDecreaseGain
+	}
+}
@@ -62,6 +62,41 @@ func (q *PriorityQueue) Find(sqNum uint16) (*rtp.Packet, error) {
 	return nil, ErrNotFound
 }
 
+// findNext returns the packet with the first sequence number at or after sqNum,
+// taking the wrap around of sequence numbers into account.
+func (q *PriorityQueue) findNext(sqNum uint16) (*rtp.Packet, error) {
+	var next *rtp.Packet
+	for pos := q.next; pos != nil; pos = pos.next {
+		if next == nil || pos.priority-sqNum < next.SequenceNumber-sqNum {
+			next = pos.val
+		}
+	}
+	if next == nil {
+		return nil, ErrInvalidOperation
+	}
+
+	return next, nil
+}
+
+// findNextTimestamp returns the packet with the first sequence number at or after sqNum
+// whose timestamp is after the given one.
+func (q *PriorityQueue) findNextTimestamp(sqNum uint16, timestamp uint32) (*rtp.Packet, error) {
+	var next *rtp.Packet
+	for pos := q.next; pos != nil; pos = pos.next {
+		if int32(pos.val.Timestamp-timestamp) <= 0 { //nolint:gosec // G115
+			continue
+		}
+		if next == nil || pos.priority-sqNum < next.SequenceNumber-sqNum {
+			next = pos.val
+		}
+	}
+	if next == nil {
+		return nil, ErrNotFound
+	}
+
+	return next, nil
+}
+
 // Push will insert a packet in to the queue in order of sequence number.
 func (q *PriorityQueue) Push(val *rtp.Packet, priority uint16) {
 	newPq := newNode(val, priority)
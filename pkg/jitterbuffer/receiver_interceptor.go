@@ -4,23 +4,53 @@
 package jitterbuffer
 
 import (
+	"errors"
 	"sync"
+	"time"
 
 	"github.com/pion/interceptor"
 	"github.com/pion/logging"
+	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 )
 
+// keyframeRequestsSize is the number of PLIs that can be pending.
+const keyframeRequestsSize = 16
+
+// keyframeRequestInterval is the minimum interval between the PLIs sent for a remote stream.
+const keyframeRequestInterval = 500 * time.Millisecond
+
+// StatsGetter returns the Stats of the jitter buffers of remote streams.
+type StatsGetter interface {
+	Stats(ssrc uint32) (Stats, bool)
+}
+
+// NewPeerConnectionCallback receives a new StatsGetter for a newly created
+// PeerConnection.
+type NewPeerConnectionCallback func(string, StatsGetter)
+
 // InterceptorFactory is a interceptor.Factory for a GeneratorInterceptor.
 type InterceptorFactory struct {
-	opts []ReceiverInterceptorOption
+	opts              []ReceiverInterceptorOption
+	addPeerConnection NewPeerConnectionCallback
+}
+
+// OnNewPeerConnection sets the callback that is called when a new
+// PeerConnection is created.
+func (g *InterceptorFactory) OnNewPeerConnection(cb NewPeerConnectionCallback) {
+	g.addPeerConnection = cb
 }
 
 // NewInterceptor constructs a new ReceiverInterceptor.
-func (g *InterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+func (g *InterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor, error) {
 	receiverInterceptor := &ReceiverInterceptor{
-		close:  make(chan struct{}),
-		buffer: New(),
+		close:            make(chan struct{}),
+		buffers:          map[uint32]*JitterBuffer{},
+		streams:          map[uint32]*timedStream{},
+		keyframeRequests: make(chan uint32, keyframeRequestsSize),
+		minDelay:         defaultMinPlayoutDelay,
+		maxDelay:         defaultMaxPlayoutDelay,
+		clock:            interceptor.SystemClock(),
 	}
 
 	for _, opt := range g.opts {
@@ -36,6 +66,10 @@ func (g *InterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor,
 		receiverInterceptor.log = receiverInterceptor.loggerFactory.NewLogger("jitterbuffer")
 	}
 
+	if g.addPeerConnection != nil {
+		g.addPeerConnection(id, receiverInterceptor)
+	}
+
 	return receiverInterceptor, nil
 }
 
@@ -45,7 +79,7 @@ func (g *InterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor,
 //	The Interceptor is designed to fit in a RemoteStream
 //	pipeline and buffer incoming packets for a short period (currently
 //	defaulting to 50 packets) before emitting packets to be consumed by the
-//	next step in the pipeline.
+//	next step in the pipeline. Each remote stream gets its own JitterBuffer.
 //
 //	The caller must ensure they are prepared to handle an
 //	ErrPopWhileBuffering in the case that insufficient packets have been
@@ -56,26 +90,59 @@ func (g *InterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor,
 //	returned in the case that the initial buffering was sufficient and
 //	playback began but the caller is consuming packets (or they are not
 //	arriving) quickly enough.
+//
+//	With WithAdaptivePlayoutDelay, remote streams with a ClockRate use a
+//	time based JitterBuffer instead. Their reader blocks until the next
+//	packet is due for playout, packets are read from the remote stream in
+//	the background. UnbindRemoteStream and Close stop reading, and wait for
+//	the Read in progress to return, which the transport does once it closes
+//	the remote stream.
+//
+//	With WithFrameAssembly, the packets are returned frame by frame once a
+//	frame is complete. When an incomplete frame has to be skipped, a PLI is
+//	sent for the remote stream as decoding can not continue until the next
+//	keyframe. PLIs are only sent for the streams which negotiated "nack pli"
+//	feedback, at most one every 500ms.
 type ReceiverInterceptor struct {
 	interceptor.NoOp
-	buffer        *JitterBuffer
-	m             sync.Mutex
-	wg            sync.WaitGroup
-	close         chan struct{}
-	log           logging.LeveledLogger
-	loggerFactory logging.LoggerFactory
+	buffers          map[uint32]*JitterBuffer
+	streams          map[uint32]*timedStream
+	timeBased        bool
+	frames           bool
+	keyframeRequests chan uint32
+	minDelay         time.Duration
+	maxDelay         time.Duration
+	clock            interceptor.Clock
+	m                sync.Mutex
+	wg               sync.WaitGroup
+	close            chan struct{}
+	log              logging.LeveledLogger
+	loggerFactory    logging.LoggerFactory
 }
 
 // NewInterceptor returns a new InterceptorFactory.
 func NewInterceptor(opts ...ReceiverInterceptorOption) (*InterceptorFactory, error) {
-	return &InterceptorFactory{opts}, nil
+	return &InterceptorFactory{opts: opts}, nil
 }
 
 // BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream.
 // The returned method will be called once per rtp packet.
 func (i *ReceiverInterceptor) BindRemoteStream(
-	_ *interceptor.StreamInfo, reader interceptor.RTPReader,
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
 ) interceptor.RTPReader {
+	if i.timeBased && info.ClockRate != 0 {
+		return i.bindTimedStream(info, reader)
+	}
+
+	buffer := i.newBuffer(info)
+	i.m.Lock()
+	i.buffers[info.SSRC] = buffer
+	i.m.Unlock()
+
+	if i.frames {
+		return i.bindFrameStream(buffer, reader)
+	}
+
 	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
 		buf := make([]byte, len(b))
 		n, attr, err := reader.Read(buf, a)
@@ -83,40 +150,240 @@ func (i *ReceiverInterceptor) BindRemoteStream(
 			return n, attr, err
 		}
 		packet := &rtp.Packet{}
-		if err := packet.Unmarshal(buf); err != nil {
+		if err := packet.Unmarshal(buf[:n]); err != nil {
+			return 0, nil, err
+		}
+		buffer.Push(packet)
+		newPkt, err := buffer.Pop()
+		if errors.Is(err, ErrPopWhileBuffering) {
+			return n, attr, err
+		}
+		if err != nil {
 			return 0, nil, err
 		}
-		i.m.Lock()
-		defer i.m.Unlock()
-		i.buffer.Push(packet)
-		if i.buffer.state == Emitting {
-			newPkt, err := i.buffer.Pop()
+		nlen, err := newPkt.MarshalTo(b)
+
+		return nlen, withStats(attr, buffer), err
+	})
+}
+
+// withStats sets the number of packets buffer dropped as late in attr, which is
+// created if nil.
+func withStats(attr interceptor.Attributes, buffer *JitterBuffer) interceptor.Attributes {
+	if attr == nil {
+		attr = make(interceptor.Attributes)
+	}
+	attr.Set(interceptor.DiscardedPacketsAttributesKey, uint64(buffer.Stats().LateCount))
+
+	return attr
+}
+
+func (i *ReceiverInterceptor) newBuffer(info *interceptor.StreamInfo, opts ...Option) *JitterBuffer {
+	buffer := New(append([]Option{WithBufferClock(i.clock)}, opts...)...)
+	if i.frames && streamSupportPli(info) {
+		buffer.Listen(FrameSkipped, func(Event, *JitterBuffer) {
+			select {
+			case i.keyframeRequests <- info.SSRC:
+			default:
+			}
+		})
+	}
+
+	return buffer
+}
+
+func streamSupportPli(info *interceptor.StreamInfo) bool {
+	for _, fb := range info.RTCPFeedback {
+		if fb.Type == "nack" && fb.Parameter == "pli" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// packetAttributes holds the Attributes of the packets pushed into a JitterBuffer.
+type packetAttributes map[uint16]interceptor.Attributes
+
+// pop returns the Attributes of the packet with the sequence number sqNum, and
+// drops the ones of the packets before it, which were skipped. Packets are
+// played out in sequence number order.
+func (p packetAttributes) pop(sqNum uint16) interceptor.Attributes {
+	attr := p[sqNum]
+	for seq := range p {
+		if seq == sqNum || isOlder(seq, sqNum) {
+			delete(p, seq)
+		}
+	}
+
+	return attr
+}
+
+// bindFrameStream returns the packets of complete frames, reading from reader until one is.
+func (i *ReceiverInterceptor) bindFrameStream(
+	buffer *JitterBuffer, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	var pending []*rtp.Packet
+	attributes := packetAttributes{}
+
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		for len(pending) == 0 {
+			buf := make([]byte, len(b))
+			n, attr, err := reader.Read(buf, a)
 			if err != nil {
+				return n, attr, err
+			}
+			packet := &rtp.Packet{}
+			if err := packet.Unmarshal(buf[:n]); err != nil {
 				return 0, nil, err
 			}
-			nlen, err := newPkt.MarshalTo(b)
+			if buffer.push(packet, i.clock.Now()) {
+				attributes[packet.SequenceNumber] = attr
+			}
 
-			return nlen, attr, err
+			frame, err := buffer.PopFrame()
+			switch {
+			case err == nil:
+				pending = frame.Packets
+			case errors.Is(err, ErrPopWhileBuffering), errors.Is(err, ErrFrameIncomplete):
+			default:
+				return 0, nil, err
+			}
+		}
+
+		packet := pending[0]
+		pending = pending[1:]
+		n, err := packet.MarshalTo(b)
+		if err != nil {
+			return 0, nil, err
 		}
 
-		return n, attr, ErrPopWhileBuffering
+		return n, withStats(attributes.pop(packet.SequenceNumber), buffer), nil
 	})
 }
 
+// BindRTCPWriter lets you modify any outgoing RTCP packets. It is called once per PeerConnection. The returned method
+// will be called once per packet batch.
+func (i *ReceiverInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.RTCPWriter {
+	if !i.frames {
+		return writer
+	}
+
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if i.isClosed() {
+		return writer
+	}
+
+	i.wg.Add(1)
+
+	go i.loop(writer)
+
+	return writer
+}
+
+// loop sends a PLI for the remote streams which skipped an incomplete frame, at
+// most one per keyframeRequestInterval for each stream.
+func (i *ReceiverInterceptor) loop(rtcpWriter interceptor.RTCPWriter) {
+	defer i.wg.Done()
+
+	lastRequests := map[uint32]time.Time{}
+	for {
+		select {
+		case ssrc := <-i.keyframeRequests:
+			now := i.clock.Now()
+			if last, ok := lastRequests[ssrc]; ok && now.Sub(last) < keyframeRequestInterval {
+				continue
+			}
+			lastRequests[ssrc] = now
+
+			pkts := []rtcp.Packet{&rtcp.PictureLossIndication{MediaSSRC: ssrc}}
+			if _, err := rtcpWriter.Write(pkts, interceptor.Attributes{}); err != nil {
+				i.log.Warnf("failed sending: %+v", err)
+			}
+		case <-i.close:
+			return
+		}
+	}
+}
+
+func (i *ReceiverInterceptor) isClosed() bool {
+	select {
+	case <-i.close:
+		return true
+	default:
+		return false
+	}
+}
+
+func (i *ReceiverInterceptor) bindTimedStream(
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	buffer := i.newBuffer(info, WithClockRate(info.ClockRate), WithPlayoutDelay(i.minDelay, i.maxDelay))
+	stream := newTimedStream(buffer, i.frames, i.clock, i.log)
+	i.m.Lock()
+	i.buffers[info.SSRC] = buffer
+	i.streams[info.SSRC] = stream
+	i.m.Unlock()
+
+	// Tracked by the stream, UnbindRemoteStream and Close wait for its readLoop.
+	go stream.readLoop(reader)
+
+	return stream
+}
+
+// Stats returns the Stats of the JitterBuffer of the remote stream with the given SSRC.
+func (i *ReceiverInterceptor) Stats(ssrc uint32) (Stats, bool) {
+	i.m.Lock()
+	buffer, ok := i.buffers[ssrc]
+	i.m.Unlock()
+
+	if !ok {
+		return Stats{}, false
+	}
+
+	return buffer.Stats(), true
+}
+
 // UnbindRemoteStream is called when the Stream is removed. It can be used to clean up any data related to that track.
-func (i *ReceiverInterceptor) UnbindRemoteStream(_ *interceptor.StreamInfo) {
+// For the time based streams, it waits for the pending Read of the remote stream to return.
+func (i *ReceiverInterceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
 	defer i.wg.Wait()
 	i.m.Lock()
-	defer i.m.Unlock()
-	i.buffer.Clear(true)
+	stream, hasStream := i.streams[info.SSRC]
+	buffer, hasBuffer := i.buffers[info.SSRC]
+	delete(i.streams, info.SSRC)
+	delete(i.buffers, info.SSRC)
+	i.m.Unlock()
+
+	if hasStream {
+		stream.close()
+	}
+	if hasBuffer {
+		buffer.Clear(true)
+	}
 }
 
-// Close closes the interceptor.
+// Close closes the interceptor. For the time based streams, it waits for the
+// pending Read of the remote streams to return.
 func (i *ReceiverInterceptor) Close() error {
 	defer i.wg.Wait()
 	i.m.Lock()
-	defer i.m.Unlock()
-	i.buffer.Clear(true)
+	streams, buffers := i.streams, i.buffers
+	i.streams = map[uint32]*timedStream{}
+	i.buffers = map[uint32]*JitterBuffer{}
+	if !i.isClosed() {
+		close(i.close)
+	}
+	i.m.Unlock()
+
+	for _, stream := range streams {
+		stream.close()
+	}
+	for _, buffer := range buffers {
+		buffer.Clear(true)
+	}
 
 	return nil
 }
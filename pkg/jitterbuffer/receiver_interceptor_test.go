@@ -5,6 +5,8 @@ package jitterbuffer
 
 import (
 	"bytes"
+	"io"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -96,3 +98,301 @@ func TestReceiverBuffersAndPlaysout(t *testing.T) {
 	err = testInterceptor.Close()
 	assert.NoError(t, err)
 }
+
+func TestReceiverBuffersPerStream(t *testing.T) {
+	factory, err := NewInterceptor()
+	assert.NoError(t, err)
+
+	var getter StatsGetter
+	factory.OnNewPeerConnection(func(_ string, g StatsGetter) {
+		getter = g
+	})
+
+	testInterceptor, err := factory.NewInterceptor("")
+	assert.NoError(t, err)
+	assert.NotNil(t, getter)
+
+	infoA := &interceptor.StreamInfo{SSRC: 1, ClockRate: 90000}
+	infoB := &interceptor.StreamInfo{SSRC: 2, ClockRate: 90000}
+	streamA := test.NewMockStream(infoA, testInterceptor)
+	streamB := test.NewMockStream(infoB, testInterceptor)
+
+	// Stream A is in order, every other packet of stream B is reordered.
+	streamB.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 2, SequenceNumber: 1000}})
+	for s := range uint16(30) {
+		streamA.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 1, SequenceNumber: 2 * s}})
+		streamA.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 1, SequenceNumber: 2*s + 1}})
+		streamB.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 2, SequenceNumber: 1002 + 2*s}})
+		streamB.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 2, SequenceNumber: 1001 + 2*s}})
+	}
+
+	for s := range uint16(10) {
+		read := <-streamA.ReadRTP()
+		assert.NoError(t, read.Err)
+		assert.Equal(t, s, read.Packet.SequenceNumber)
+		assert.Equal(t, uint32(1), read.Packet.SSRC)
+
+		read = <-streamB.ReadRTP()
+		assert.NoError(t, read.Err)
+		assert.Equal(t, 1000+s, read.Packet.SequenceNumber)
+		assert.Equal(t, uint32(2), read.Packet.SSRC)
+	}
+
+	statsA, ok := getter.Stats(1)
+	assert.True(t, ok)
+	assert.Zero(t, statsA.OutOfOrderCount)
+
+	statsB, ok := getter.Stats(2)
+	assert.True(t, ok)
+	assert.EqualValues(t, 60, statsB.OutOfOrderCount)
+
+	testInterceptor.UnbindRemoteStream(infoB)
+	_, ok = getter.Stats(2)
+	assert.False(t, ok)
+
+	assert.NoError(t, streamA.Close())
+	assert.NoError(t, streamB.Close())
+}
+
+func TestReceiverAdaptivePlayoutDelay(t *testing.T) {
+	factory, err := NewInterceptor(WithAdaptivePlayoutDelay(20*time.Millisecond, 100*time.Millisecond))
+	assert.NoError(t, err)
+
+	testInterceptor, err := factory.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, testInterceptor)
+
+	// A packet is held back for the minimum delay, even though it is read right away.
+	start := time.Now()
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456, SequenceNumber: 0}})
+	read := <-stream.ReadRTP()
+	assert.NoError(t, read.Err)
+	assert.Equal(t, uint16(0), read.Packet.SequenceNumber)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	// Reordered packets are released in order.
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456, SequenceNumber: 2, Timestamp: 3600}})
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456, SequenceNumber: 1, Timestamp: 1800}})
+	for _, seq := range []uint16{1, 2} {
+		read := <-stream.ReadRTP()
+		assert.NoError(t, read.Err)
+		assert.Equal(t, seq, read.Packet.SequenceNumber)
+	}
+
+	select {
+	case read := <-stream.ReadRTP():
+		assert.Fail(t, "unexpected packet", read)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.NoError(t, stream.Close())
+	read = <-stream.ReadRTP()
+	assert.ErrorIs(t, read.Err, io.EOF)
+}
+
+func TestReceiverAdaptivePlayoutDelayBounds(t *testing.T) {
+	for _, bounds := range []struct {
+		minDelay, maxDelay time.Duration
+		err                error
+	}{
+		{-time.Millisecond, 100 * time.Millisecond, errNegativePlayoutDelay},
+		{20 * time.Millisecond, -time.Millisecond, errNegativePlayoutDelay},
+		{100 * time.Millisecond, 20 * time.Millisecond, errInvalidPlayoutDelay},
+	} {
+		factory, err := NewInterceptor(WithAdaptivePlayoutDelay(bounds.minDelay, bounds.maxDelay))
+		assert.NoError(t, err)
+
+		_, err = factory.NewInterceptor("")
+		assert.ErrorIs(t, err, bounds.err)
+	}
+}
+
+func TestReceiverStopsReadingOnClose(t *testing.T) {
+	factory, err := NewInterceptor(WithAdaptivePlayoutDelay(20*time.Millisecond, 100*time.Millisecond))
+	assert.NoError(t, err)
+
+	testInterceptor, err := factory.NewInterceptor("")
+	assert.NoError(t, err)
+
+	var reads atomic.Int32
+	unblock := make(chan struct{})
+	reader := testInterceptor.BindRemoteStream(&interceptor.StreamInfo{SSRC: 123456, ClockRate: 90000},
+		interceptor.RTPReaderFunc(func(b []byte, _ interceptor.Attributes) (int, interceptor.Attributes, error) {
+			reads.Add(1)
+			<-unblock
+			n, err := (&rtp.Packet{Header: rtp.Header{SSRC: 123456}}).MarshalTo(b)
+
+			return n, nil, err
+		}))
+	assert.Eventually(t, func() bool {
+		return reads.Load() == 1
+	}, time.Second, time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		assert.NoError(t, testInterceptor.Close())
+		close(closed)
+	}()
+
+	// The pending Read of the remote stream is waited for.
+	select {
+	case <-closed:
+		assert.Fail(t, "closed with a pending read")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(unblock)
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		assert.FailNow(t, "close did not return")
+	}
+
+	// The remote stream is not read anymore.
+	assert.Equal(t, int32(1), reads.Load())
+	_, _, err = reader.Read(make([]byte, 1500), nil)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestReceiverFrameAssembly(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		info *interceptor.StreamInfo
+		plis int
+	}{
+		{
+			name: "sends one PLI for the skipped frames",
+			info: &interceptor.StreamInfo{
+				SSRC:         123456,
+				RTCPFeedback: []interceptor.RTCPFeedback{{Type: "nack", Parameter: "pli"}},
+			},
+			plis: 1,
+		},
+		{
+			name: "sends no PLI without pli feedback",
+			info: &interceptor.StreamInfo{SSRC: 123456},
+			plis: 0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			testFrameAssembly(t, tc.info, tc.plis)
+		})
+	}
+}
+
+func testFrameAssembly(t *testing.T, info *interceptor.StreamInfo, plis int) {
+	t.Helper()
+
+	factory, err := NewInterceptor(WithFrameAssembly())
+	assert.NoError(t, err)
+
+	testInterceptor, err := factory.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(info, testInterceptor)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	// One frame of two packets, a frame missing its first packet, then single packet frames
+	// without the one of sequence number 20, which makes the frame after it incomplete too.
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456, SequenceNumber: 0, Timestamp: 0}})
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456, SequenceNumber: 1, Timestamp: 0, Marker: true}})
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456, SequenceNumber: 3, Timestamp: 3000, Marker: true}})
+	for seq := uint16(4); seq < 120; seq++ {
+		if seq == 20 {
+			continue
+		}
+		stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{
+			SSRC: 123456, SequenceNumber: seq, Timestamp: uint32(seq) * 3000, Marker: true,
+		}})
+	}
+
+	expected := []uint16{0, 1}
+	for seq := uint16(4); seq < 20; seq++ {
+		expected = append(expected, seq)
+	}
+	expected = append(expected, 22)
+	for _, seq := range expected {
+		read := <-stream.ReadRTP()
+		assert.NoError(t, read.Err)
+		assert.Equal(t, seq, read.Packet.SequenceNumber)
+	}
+
+	// The PLIs of both skips are sent within the rate limit.
+	for range plis {
+		select {
+		case pkts := <-stream.WrittenRTCP():
+			assert.Equal(t, []rtcp.Packet{&rtcp.PictureLossIndication{MediaSSRC: 123456}}, pkts)
+		case <-time.After(time.Second):
+			assert.Fail(t, "no PLI sent for the skipped frame")
+		}
+	}
+	select {
+	case pkts := <-stream.WrittenRTCP():
+		assert.Fail(t, "unexpected PLI", pkts)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestPacketAttributes(t *testing.T) {
+	attributes := packetAttributes{}
+	for _, seq := range []uint16{65534, 65535, 0, 1, 2} {
+		attributes[seq] = interceptor.Attributes{"seq": seq}
+	}
+
+	// The attributes of the skipped packets are dropped, across the wrap around.
+	assert.Equal(t, interceptor.Attributes{"seq": uint16(1)}, attributes.pop(1))
+	assert.Equal(t, packetAttributes{2: {"seq": uint16(2)}}, attributes)
+	assert.Nil(t, attributes.pop(3))
+	assert.Empty(t, attributes)
+}
+
+func TestReceiverSetsStatsAttribute(t *testing.T) {
+	factory, err := NewInterceptor(WithFrameAssembly())
+	assert.NoError(t, err)
+
+	testInterceptor, err := factory.NewInterceptor("")
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, testInterceptor.Close())
+	}()
+
+	// Single packet frames, with the first one repeated once it was played out.
+	var sequenceNumbers []uint16
+	for seq := range uint16(60) {
+		sequenceNumbers = append(sequenceNumbers, seq)
+	}
+	sequenceNumbers = append(sequenceNumbers, 0)
+	for seq := uint16(60); seq < 120; seq++ {
+		sequenceNumbers = append(sequenceNumbers, seq)
+	}
+	reader := testInterceptor.BindRemoteStream(&interceptor.StreamInfo{SSRC: 123456}, interceptor.RTPReaderFunc(
+		func(b []byte, _ interceptor.Attributes) (int, interceptor.Attributes, error) {
+			if len(sequenceNumbers) == 0 {
+				return 0, nil, io.EOF
+			}
+			seq := sequenceNumbers[0]
+			sequenceNumbers = sequenceNumbers[1:]
+			packet := &rtp.Packet{Header: rtp.Header{
+				SSRC: 123456, SequenceNumber: seq, Timestamp: uint32(seq) * 3000, Marker: true,
+			}}
+			n, err := packet.MarshalTo(b)
+
+			return n, nil, err
+		},
+	))
+
+	var discarded uint64
+	for range 60 {
+		_, attr, err := reader.Read(make([]byte, 1500), nil)
+		assert.NoError(t, err)
+		var ok bool
+		discarded, ok = attr.Get(interceptor.DiscardedPacketsAttributesKey).(uint64)
+		assert.True(t, ok)
+	}
+	assert.Equal(t, uint64(1), discarded)
+}
@@ -0,0 +1,216 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package jitterbuffer
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"github.com/pion/rtp"
+)
+
+// receiveMTU is the size of the buffer the packets of a timed stream are read into.
+const receiveMTU = 1500
+
+// timedStream reads the packets of a remote stream into a time based JitterBuffer
+// and returns them from Read when they are due for playout, frame by frame if frames is set.
+type timedStream struct {
+	buffer     *JitterBuffer
+	frames     bool
+	clock      interceptor.Clock
+	pending    []*rtp.Packet
+	log        logging.LeveledLogger
+	mu         sync.Mutex
+	attributes packetAttributes
+	err        error
+	pushed     chan struct{}
+	done       chan struct{}
+	stopped    chan struct{}
+}
+
+func newTimedStream(
+	buffer *JitterBuffer, frames bool, clock interceptor.Clock, log logging.LeveledLogger,
+) *timedStream {
+	return &timedStream{
+		buffer:     buffer,
+		frames:     frames,
+		clock:      clock,
+		log:        log,
+		attributes: packetAttributes{},
+		pushed:     make(chan struct{}, 1),
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+}
+
+// readLoop pushes the packets of reader into the JitterBuffer as they arrive, until
+// reader returns an error or the stream is closed. A Read in progress when the
+// stream is closed is waited for, its packet is dropped.
+func (s *timedStream) readLoop(reader interceptor.RTPReader) {
+	defer close(s.stopped)
+
+	for {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+
+		buf := make([]byte, receiveMTU)
+		n, attr, err := reader.Read(buf, nil)
+		if s.isClosed() {
+			return
+		}
+		if err != nil {
+			s.mu.Lock()
+			s.err = err
+			s.mu.Unlock()
+			s.notify()
+
+			return
+		}
+
+		packet := &rtp.Packet{}
+		if err := packet.Unmarshal(buf[:n]); err != nil {
+			s.log.Debugf("dropping invalid packet: %v", err)
+
+			continue
+		}
+
+		s.mu.Lock()
+		s.attributes[packet.SequenceNumber] = attr
+		s.mu.Unlock()
+
+		if !s.buffer.push(packet, s.clock.Now()) {
+			s.mu.Lock()
+			delete(s.attributes, packet.SequenceNumber)
+			s.mu.Unlock()
+		}
+		s.notify()
+	}
+}
+
+func (s *timedStream) notify() {
+	select {
+	case s.pushed <- struct{}{}:
+	default:
+	}
+}
+
+func (s *timedStream) readErr() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+// Read blocks until the next packet is due for playout. Once the remote stream
+// ended, the error of its reader is returned after the buffered packets.
+func (s *timedStream) Read(b []byte, _ interceptor.Attributes) (int, interceptor.Attributes, error) {
+	for len(s.pending) == 0 {
+		wake, hasWake, err := s.popDue(s.clock.Now())
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(s.pending) == 0 && !s.wait(wake, hasWake) {
+			return 0, nil, io.EOF
+		}
+	}
+
+	packet := s.pending[0]
+	s.pending = s.pending[1:]
+
+	return s.marshal(packet, b)
+}
+
+// popDue moves the packets due for playout to pending. Otherwise, it returns the time
+// to check again at, if any.
+func (s *timedStream) popDue(now time.Time) (time.Time, bool, error) {
+	next, err := s.buffer.NextPlayoutTime()
+	if err != nil {
+		return time.Time{}, false, s.readErr()
+	}
+	if now.Before(next) {
+		return next, true, nil
+	}
+
+	if !s.frames {
+		if packet, err := s.buffer.PopDue(now); err == nil {
+			s.pending = append(s.pending, packet)
+		}
+
+		return now, true, nil
+	}
+
+	frame, err := s.buffer.PopFrameDue(now)
+	if err == nil {
+		s.pending = append(s.pending, frame.Packets...)
+
+		return now, true, nil
+	}
+	if skip, ok := s.buffer.nextFramePlayoutTime(); ok {
+		return skip, true, nil
+	}
+
+	// The incomplete frame is dropped once the remote stream ended.
+	return time.Time{}, false, s.readErr()
+}
+
+// wait waits for the given time, if any, or for a new packet. It returns
+// false if the stream was closed.
+func (s *timedStream) wait(wake time.Time, hasWake bool) bool {
+	var due <-chan time.Time
+	if hasWake {
+		delay := wake.Sub(s.clock.Now())
+		if delay <= 0 {
+			return true
+		}
+		// The first tick of the ticker is the wake up time.
+		ticker := s.clock.NewTicker(delay)
+		defer ticker.Stop()
+		due = ticker.Ch()
+	}
+
+	select {
+	case <-s.pushed:
+	case <-due:
+	case <-s.done:
+		return false
+	}
+
+	return true
+}
+
+func (s *timedStream) marshal(packet *rtp.Packet, b []byte) (int, interceptor.Attributes, error) {
+	n, err := packet.MarshalTo(b)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	s.mu.Lock()
+	attr := s.attributes.pop(packet.SequenceNumber)
+	s.mu.Unlock()
+
+	return n, withStats(attr, s.buffer), nil
+}
+
+func (s *timedStream) isClosed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// close stops the stream, and waits for its readLoop to return.
+func (s *timedStream) close() {
+	if !s.isClosed() {
+		close(s.done)
+	}
+	<-s.stopped
+}
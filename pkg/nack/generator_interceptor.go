@@ -27,6 +27,7 @@ func (g *GeneratorInterceptorFactory) NewInterceptor(_ string) (interceptor.Inte
 		skipLastN:         0,
 		maxNacksPerPacket: 0,
 		interval:          time.Millisecond * 100,
+		clock:             interceptor.SystemClock(),
 		receiveLogs:       map[uint32]*receiveLog{},
 		nackCountLogs:     map[uint32]map[uint16]uint16{},
 		close:             make(chan struct{}),
@@ -60,6 +61,7 @@ type GeneratorInterceptor struct {
 	skipLastN         uint16
 	maxNacksPerPacket uint16
 	interval          time.Duration
+	clock             interceptor.Clock
 	m                 sync.Mutex
 	wg                sync.WaitGroup
 	close             chan struct{}
@@ -121,7 +123,11 @@ func (n *GeneratorInterceptor) BindRemoteStream(
 		if err != nil {
 			return 0, nil, err
 		}
-		receiveLog.add(header.SequenceNumber)
+		if isRepaired(attr) {
+			receiveLog.addRepaired(header.SequenceNumber)
+		} else {
+			receiveLog.add(header.SequenceNumber)
+		}
 
 		return i, attr, nil
 	})
@@ -158,11 +164,11 @@ func (n *GeneratorInterceptor) loop(rtcpWriter interceptor.RTCPWriter) {
 	missingPacketSeqNums := make([]uint16, n.size)
 	filteredMissingPacket := make([]uint16, n.size)
 
-	ticker := time.NewTicker(n.interval)
+	ticker := n.clock.NewTicker(n.interval)
 	defer ticker.Stop()
 	for {
 		select {
-		case <-ticker.C:
+		case <-ticker.Ch():
 			// save NACKs to send without holding the mutex during Write
 			var toSend []rtcp.Packet
 
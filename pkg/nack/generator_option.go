@@ -71,6 +71,15 @@ func GeneratorInterval(interval time.Duration) GeneratorOption {
 	}
 }
 
+// GeneratorClock sets the clock the nacks are sent by.
+func GeneratorClock(clock interceptor.Clock) GeneratorOption {
+	return func(r *GeneratorInterceptor) error {
+		r.clock = clock
+
+		return nil
+	}
+}
+
 // GeneratorStreamsFilter sets filter for generator streams.
 func GeneratorStreamsFilter(filter func(info *interceptor.StreamInfo) bool) GeneratorOption {
 	return func(r *GeneratorInterceptor) error {
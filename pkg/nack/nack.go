@@ -15,3 +15,11 @@ func streamSupportNack(info *interceptor.StreamInfo) bool {
 
 	return false
 }
+
+// isRepaired returns true for packets recovered by the rtx or flexfec interceptors.
+func isRepaired(attr interceptor.Attributes) bool {
+	retransmitted, _ := attr.Get(interceptor.RetransmittedAttributesKey).(bool)
+	recovered, _ := attr.Get(interceptor.RecoveredAttributesKey).(bool)
+
+	return retransmitted || recovered
+}
@@ -81,6 +81,21 @@ func (s *receiveLog) add(seq uint16) {
 	s.setReceived(seq)
 }
 
+// addRepaired marks a packet recovered by retransmission or FEC as received. Unlike add,
+// it never moves the end of the log forward, so that a repaired packet cannot make the
+// packets in between look lost.
+func (s *receiveLog) addRepaired(seq uint16) {
+	s.m.RLock()
+	newer := !s.started || (seq-s.end < rtpbuffer.Uint16SizeHalf && seq != s.end)
+	s.m.RUnlock()
+
+	if newer {
+		return
+	}
+
+	s.add(seq)
+}
+
 func (s *receiveLog) get(seq uint16) bool {
 	s.m.RLock()
 	defer s.m.RUnlock()
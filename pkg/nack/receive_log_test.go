@@ -151,3 +151,23 @@ func TestReceivedBuffer(t *testing.T) {
 		})
 	}
 }
+
+func TestReceiveLogAddRepaired(t *testing.T) {
+	rl, err := newReceiveLog(128)
+	assert.NoError(t, err)
+
+	// Nothing to repair before the first packet.
+	rl.addRepaired(5)
+	assert.False(t, rl.get(5))
+
+	rl.add(10)
+	rl.add(13)
+	rl.addRepaired(11)
+	assert.True(t, rl.get(11))
+	assert.Equal(t, []uint16{12}, rl.missingSeqNumbers(0, make([]uint16, 128)))
+
+	// Repaired packets never move the end of the log forward.
+	rl.addRepaired(20)
+	assert.False(t, rl.get(20))
+	assert.Equal(t, []uint16{12}, rl.missingSeqNumbers(0, make([]uint16, 128)))
+}
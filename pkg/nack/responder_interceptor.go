@@ -189,7 +189,8 @@ func (n *ResponderInterceptor) resendPackets(nack *rtcp.TransportLayerNack) {
 
 			if p != nil {
 				// send without holding rtpBufferMutex
-				if _, err := stream.rtpWriter.Write(p.Header(), p.Payload(), interceptor.Attributes{}); err != nil {
+				attributes := interceptor.Attributes{interceptor.RetransmittedAttributesKey: true}
+				if _, err := stream.rtpWriter.Write(p.Header(), p.Payload(), attributes); err != nil {
 					n.log.Warnf("failed resending nacked packet: %+v", err)
 				}
 				p.Release()
@@ -0,0 +1,272 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package nada implements Network-Assisted Dynamic Adaptation (NADA) congestion
+// control as defined by RFC 8698.
+package nada
+
+import (
+	"math"
+	"time"
+
+	"github.com/pion/interceptor/internal/cc"
+	"github.com/pion/rtcp"
+)
+
+// Default parameters of RFC 8698, Section 6.3.
+const (
+	// defaultMinBitrate is RMIN, the minimum rate of the sender.
+	defaultMinBitrate = 150_000
+	// defaultMaxBitrate is RMAX, the maximum rate of the sender.
+	defaultMaxBitrate = 1_500_000
+	// defaultPriority is PRIO, the weight of the flow.
+	defaultPriority = 1.0
+
+	// refDelay is XREF, the reference congestion level.
+	refDelay = 10 * time.Millisecond
+	// kappa is KAPPA, the scaling parameter of the gradual rate update.
+	kappa = 0.5
+	// eta is ETA, the scaling parameter of the gradual rate update.
+	eta = 2.0
+	// tau is TAU, the upper bound of the RTT in the gradual rate update.
+	tau = 500 * time.Millisecond
+	// feedbackInterval is DELTA, the target interval of the feedback.
+	feedbackInterval = 100 * time.Millisecond
+	// logWindow is LOGWIN, the observation window of the loss, marking and receive rates.
+	logWindow = 500 * time.Millisecond
+	// queueEpsilon is QEPS, the queuing delay under which the sender ramps up quickly.
+	queueEpsilon = 10 * time.Millisecond
+	// filterDelay is DFILT, the bound on the delay of the queuing delay filter.
+	filterDelay = 120 * time.Millisecond
+	// gammaMax is GAMMA_MAX, the upper bound of the rate increase ratio of the
+	// accelerated ramp-up.
+	gammaMax = 0.5
+	// queueBound is QBOUND, the upper bound of the queuing delay caused by the
+	// accelerated ramp-up.
+	queueBound = 50 * time.Millisecond
+	// queueThreshold is QTH, the queuing delay over which the delay is warped
+	// during loss episodes.
+	queueThreshold = 50 * time.Millisecond
+	// lambda is LAMBDA, the exponent of the delay warping.
+	lambda = 0.5
+	// refLossRatio is PLRREF, the reference packet loss ratio.
+	refLossRatio = 0.01
+	// refMarkingRatio is PMRREF, the reference packet marking ratio.
+	refMarkingRatio = 0.01
+	// lossPenalty is DLOSS, the delay penalty of the reference loss ratio.
+	lossPenalty = 10 * time.Millisecond
+	// markingPenalty is DMARK, the delay penalty of the reference marking ratio.
+	markingPenalty = 2 * time.Millisecond
+)
+
+// RampUpMode is the mode the reference rate is updated in.
+type RampUpMode int
+
+const (
+	// RampUpAccelerated is the mode used while no queue builds up and no packet
+	// is lost or marked. The rate grows with the receive rate.
+	RampUpAccelerated RampUpMode = iota
+	// RampUpGradual is the mode used once the network is congested. The rate
+	// follows the aggregated congestion signal.
+	RampUpGradual
+)
+
+func (m RampUpMode) String() string {
+	switch m {
+	case RampUpAccelerated:
+		return "accelerated"
+	case RampUpGradual:
+		return "gradual"
+	default:
+		return "unknown"
+	}
+}
+
+// Stats contains internal statistics of the NADA controller.
+type Stats struct {
+	// TargetBitrate is the reference rate of the sender.
+	TargetBitrate int
+	// ReceiveBitrate is the rate the packets were received at over the observation window.
+	ReceiveBitrate int
+	// QueueDelay is the queuing delay, the one way delay over the base delay.
+	QueueDelay time.Duration
+	// CongestionSignal is the aggregated congestion signal, the queuing delay
+	// with the loss and marking penalties.
+	CongestionSignal time.Duration
+	// LossRatio is the share of the packets lost over the observation window.
+	LossRatio float64
+	// MarkingRatio is the share of the received packets marked ECN-CE over the
+	// observation window.
+	MarkingRatio float64
+	// Mode is the mode of the last rate update.
+	Mode RampUpMode
+}
+
+type sample struct {
+	departure time.Time
+	size      int
+	lost      bool
+	marked    bool
+}
+
+// controller implements the sender side of NADA. As the feedback carries the
+// arrival time of every packet, the calculations of the receiver are done by
+// the sender.
+type controller struct {
+	minBitrate int
+	maxBitrate int
+	priority   float64
+
+	referenceRate  float64
+	baseDelay      time.Duration
+	baseDelaySet   bool
+	samples        []sample
+	prevSignal     time.Duration
+	lastCongestion time.Time
+	lastUpdate     time.Time
+	stats          Stats
+}
+
+func newController(initialBitrate, minBitrate, maxBitrate int, priority float64) *controller {
+	return &controller{
+		minBitrate:    minBitrate,
+		maxBitrate:    maxBitrate,
+		priority:      priority,
+		referenceRate: float64(max(min(initialBitrate, maxBitrate), minBitrate)),
+		stats:         Stats{TargetBitrate: max(min(initialBitrate, maxBitrate), minBitrate)},
+	}
+}
+
+// onAcks updates the reference rate with the acknowledgments of a feedback
+// received at now, and returns the new rate.
+func (c *controller) onAcks(now time.Time, acks []cc.Acknowledgment, rtt time.Duration) int {
+	queueDelay, ok := c.addSamples(now, acks)
+	if !ok {
+		return c.stats.TargetBitrate
+	}
+	lossRatio, markingRatio, receiveRate := c.windowStats()
+
+	warpedDelay := queueDelay
+	if lossRatio > 0 && queueDelay > queueThreshold {
+		excess := float64(queueDelay-queueThreshold) / float64(queueThreshold)
+		warpedDelay = time.Duration(float64(queueThreshold) * math.Exp(-lambda*excess))
+	}
+	signal := warpedDelay +
+		time.Duration(float64(markingPenalty)*math.Pow(markingRatio/refMarkingRatio, 2)) +
+		time.Duration(float64(lossPenalty)*math.Pow(lossRatio/refLossRatio, 2))
+
+	delta := feedbackInterval
+	if !c.lastUpdate.IsZero() {
+		delta = min(max(now.Sub(c.lastUpdate), 0), tau)
+	}
+	c.lastUpdate = now
+
+	mode := RampUpGradual
+	if now.Sub(c.lastCongestion) > logWindow && queueDelay < queueEpsilon {
+		mode = RampUpAccelerated
+	}
+
+	switch mode {
+	case RampUpAccelerated:
+		gamma := min(gammaMax, queueBound.Seconds()/(rtt+feedbackInterval+filterDelay).Seconds())
+		c.referenceRate = max(c.referenceRate, (1+gamma)*receiveRate)
+	case RampUpGradual:
+		offset := signal.Seconds() - c.priority*refDelay.Seconds()*float64(c.maxBitrate)/c.referenceRate
+		diff := (signal - c.prevSignal).Seconds()
+		c.referenceRate *= 1 - kappa*(delta.Seconds()/tau.Seconds())*(offset/tau.Seconds()) -
+			kappa*eta*(diff/tau.Seconds())
+	}
+	c.referenceRate = max(min(c.referenceRate, float64(c.maxBitrate)), float64(c.minBitrate))
+	c.prevSignal = signal
+
+	c.stats = Stats{
+		TargetBitrate:    int(c.referenceRate),
+		ReceiveBitrate:   int(receiveRate),
+		QueueDelay:       queueDelay,
+		CongestionSignal: signal,
+		LossRatio:        lossRatio,
+		MarkingRatio:     markingRatio,
+		Mode:             mode,
+	}
+
+	return c.stats.TargetBitrate
+}
+
+// addSamples adds the acknowledgments to the observation window and returns the
+// queuing delay of the feedback, the minimum one way delay of its packets over
+// the base delay. The queuing delay of the previous feedback is kept if no packet
+// of the feedback was received. It returns false if the feedback acknowledges no
+// sent packet.
+func (c *controller) addSamples(now time.Time, acks []cc.Acknowledgment) (time.Duration, bool) {
+	minDelay := time.Duration(math.MaxInt64)
+	var lastDeparture time.Time
+	for _, ack := range acks {
+		if ack.Departure.IsZero() {
+			continue
+		}
+		s := sample{departure: ack.Departure, size: ack.Size}
+		if ack.Departure.After(lastDeparture) {
+			lastDeparture = ack.Departure
+		}
+		if ack.Arrival.IsZero() {
+			s.lost = true
+			c.lastCongestion = now
+		} else {
+			s.marked = ack.ECN == rtcp.ECNCE
+			if s.marked {
+				c.lastCongestion = now
+			}
+			minDelay = min(minDelay, ack.Arrival.Sub(ack.Departure))
+		}
+		c.samples = append(c.samples, s)
+	}
+
+	expired := 0
+	for _, s := range c.samples {
+		if lastDeparture.Sub(s.departure) < logWindow {
+			break
+		}
+		expired++
+	}
+	c.samples = c.samples[expired:]
+
+	if lastDeparture.IsZero() {
+		return 0, false
+	}
+	if minDelay == time.Duration(math.MaxInt64) {
+		return c.stats.QueueDelay, true
+	}
+	if !c.baseDelaySet || minDelay < c.baseDelay {
+		c.baseDelay = minDelay
+		c.baseDelaySet = true
+	}
+
+	return minDelay - c.baseDelay, true
+}
+
+// windowStats returns the loss ratio, the marking ratio and the receive rate in
+// bits per second over the observation window.
+func (c *controller) windowStats() (lossRatio, markingRatio, receiveRate float64) {
+	lost, marked, received, receivedBytes := 0, 0, 0, 0
+	for _, s := range c.samples {
+		if s.lost {
+			lost++
+
+			continue
+		}
+		received++
+		receivedBytes += s.size
+		if s.marked {
+			marked++
+		}
+	}
+	if total := lost + received; total > 0 {
+		lossRatio = float64(lost) / float64(total)
+	}
+	if received > 0 {
+		markingRatio = float64(marked) / float64(received)
+	}
+	receiveRate = float64(receivedBytes) * 8 / logWindow.Seconds()
+
+	return lossRatio, markingRatio, receiveRate
+}
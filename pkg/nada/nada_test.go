@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package nada
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor/internal/cc"
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+)
+
+// feedback returns the acknowledgments of n packets of 1000 bytes sent every
+// 10ms from start, with ack customizing each of them.
+func feedback(start time.Time, n int, ack func(i int, a *cc.Acknowledgment)) []cc.Acknowledgment {
+	acks := make([]cc.Acknowledgment, 0, n)
+	for i := 0; i < n; i++ {
+		departure := start.Add(time.Duration(i) * 10 * time.Millisecond)
+		a := cc.Acknowledgment{
+			SequenceNumber: uint16(i), //nolint:gosec // G115
+			Size:           1000,
+			Departure:      departure,
+			Arrival:        departure.Add(20 * time.Millisecond),
+		}
+		if ack != nil {
+			ack(i, &a)
+		}
+		acks = append(acks, a)
+	}
+
+	return acks
+}
+
+func TestController(t *testing.T) {
+	start := time.Now()
+	now := start.Add(500 * time.Millisecond)
+
+	t.Run("accelerated ramp-up", func(t *testing.T) {
+		c := newController(300_000, defaultMinBitrate, defaultMaxBitrate, defaultPriority)
+		bitrate := c.onAcks(now, feedback(start, 50, nil), 0)
+
+		assert.Equal(t, RampUpAccelerated, c.stats.Mode)
+		assert.Equal(t, 800_000, c.stats.ReceiveBitrate)
+		// The rate grows by QBOUND/(RTT+DELTA+DFILT) over the receive rate.
+		assert.InDelta(t, 800_000*(1+50.0/220), bitrate, 1)
+	})
+
+	t.Run("queuing delay", func(t *testing.T) {
+		c := newController(1_000_000, defaultMinBitrate, defaultMaxBitrate, defaultPriority)
+		c.onAcks(now, feedback(start, 50, nil), 0)
+		acks := feedback(start.Add(500*time.Millisecond), 10, func(_ int, a *cc.Acknowledgment) {
+			a.Arrival = a.Arrival.Add(100 * time.Millisecond)
+		})
+		bitrate := c.onAcks(now.Add(100*time.Millisecond), acks, 0)
+
+		assert.Equal(t, RampUpGradual, c.stats.Mode)
+		assert.Equal(t, 100*time.Millisecond, c.stats.QueueDelay)
+		assert.Less(t, bitrate, 1_000_000)
+	})
+
+	t.Run("ECN marking", func(t *testing.T) {
+		c := newController(1_000_000, defaultMinBitrate, defaultMaxBitrate, defaultPriority)
+		bitrate := c.onAcks(now, feedback(start, 50, func(i int, a *cc.Acknowledgment) {
+			if i%10 == 0 {
+				a.ECN = rtcp.ECNCE
+			}
+		}), 0)
+
+		assert.Equal(t, RampUpGradual, c.stats.Mode)
+		assert.Equal(t, time.Duration(0), c.stats.QueueDelay)
+		assert.InDelta(t, 0.1, c.stats.MarkingRatio, 0.001)
+		assert.Less(t, bitrate, 1_000_000)
+	})
+
+	t.Run("loss", func(t *testing.T) {
+		c := newController(1_000_000, defaultMinBitrate, defaultMaxBitrate, defaultPriority)
+		bitrate := c.onAcks(now, feedback(start, 50, func(i int, a *cc.Acknowledgment) {
+			if i%10 == 0 {
+				a.Arrival = time.Time{}
+			}
+		}), 0)
+
+		assert.Equal(t, RampUpGradual, c.stats.Mode)
+		assert.InDelta(t, 0.1, c.stats.LossRatio, 0.001)
+		assert.Less(t, bitrate, 1_000_000)
+	})
+
+	t.Run("bounds", func(t *testing.T) {
+		c := newController(1_000_000, defaultMinBitrate, defaultMaxBitrate, defaultPriority)
+		bitrate := c.onAcks(now, feedback(start, 50, func(_ int, a *cc.Acknowledgment) {
+			a.Arrival = time.Time{}
+		}), 0)
+		assert.Equal(t, defaultMinBitrate, bitrate)
+
+		c = newController(1_400_000, defaultMinBitrate, defaultMaxBitrate, defaultPriority)
+		bitrate = c.onAcks(now, feedback(start, 50, func(_ int, a *cc.Acknowledgment) {
+			a.Size = 5000
+		}), 0)
+		assert.Equal(t, defaultMaxBitrate, bitrate)
+	})
+}
@@ -0,0 +1,296 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package nada
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/cc"
+	"github.com/pion/interceptor/pkg/gcc"
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+)
+
+// ErrSendSideBWEClosed is raised when SendSideBWE.WriteRTCP is called after SendSideBWE.Close.
+var ErrSendSideBWEClosed = errors.New("nada: SendSideBWE closed")
+
+// SendSideBWE implements NADA as a cc.BandwidthEstimator, so that it can be used
+// with the congestion control interceptor instead of GCC:
+//
+//	cc.NewInterceptor(func() (cc.BandwidthEstimator, error) {
+//		return nada.NewSendSideBWE()
+//	})
+//
+// The congestion signal is derived from TWCC or RFC 8888 feedback. It
+// aggregates the queuing delay, the loss ratio and the ratio of packets marked
+// ECN-CE. RFC 8888 feedback carries the ECN marks of the packets, which makes
+// the controller react to L4S marking before a queue builds up.
+//
+// The packets are paced at the target bitrate. They are numbered with transport
+// wide sequence numbers by a twcc.HeaderExtensionInterceptor registered after
+// the congestion control interceptor, and the SendSideBWE records their
+// departure from the pacer in the interceptor.SentPacketRegistry set in their
+// attributes. Without such an interceptor, SendSideBWENumberPackets makes the
+// SendSideBWE number the packets itself.
+type SendSideBWE struct {
+	pacer      gcc.Pacer
+	sendSide   *cc.SendSide
+	controller *controller
+
+	onTargetBitrateChange func(bitrate int)
+
+	lock           sync.Mutex
+	latestBitrate  int
+	initialBitrate int
+	minBitrate     int
+	maxBitrate     int
+	priority       float64
+	clock          interceptor.Clock
+
+	close     chan struct{}
+	closeLock sync.RWMutex
+
+	log           logging.LeveledLogger
+	loggerFactory logging.LoggerFactory
+}
+
+// Option configures a bandwidth estimator.
+type Option func(*SendSideBWE) error
+
+// SendSideBWEInitialBitrate sets the initial bitrate of the bandwidth estimator.
+// It defaults to the minimum bitrate.
+func SendSideBWEInitialBitrate(rate int) Option {
+	return func(e *SendSideBWE) error {
+		e.initialBitrate = rate
+
+		return nil
+	}
+}
+
+// SendSideBWEMinBitrate sets the minimum bitrate of the bandwidth estimator.
+func SendSideBWEMinBitrate(rate int) Option {
+	return func(e *SendSideBWE) error {
+		e.minBitrate = rate
+
+		return nil
+	}
+}
+
+// SendSideBWEMaxBitrate sets the maximum bitrate of the bandwidth estimator. The
+// queuing delay the controller settles at grows with the maximum bitrate.
+func SendSideBWEMaxBitrate(rate int) Option {
+	return func(e *SendSideBWE) error {
+		e.maxBitrate = rate
+
+		return nil
+	}
+}
+
+// SendSideBWEPriority sets the weight of the flow, flows with a higher priority
+// settle at a higher share of the bottleneck.
+func SendSideBWEPriority(priority float64) Option {
+	return func(e *SendSideBWE) error {
+		e.priority = priority
+
+		return nil
+	}
+}
+
+// SendSideBWEMarkECT1 asks the transport to send the packets with the ECT(1) ECN
+// codepoint, signaling that the sender responds to CE marks as defined by L4S.
+func SendSideBWEMarkECT1() Option {
+	return func(e *SendSideBWE) error {
+		e.sendSide.MarkECT1 = true
+
+		return nil
+	}
+}
+
+// SendSideBWEPacer sets the pacing algorithm to use.
+func SendSideBWEPacer(p gcc.Pacer) Option {
+	return func(e *SendSideBWE) error {
+		e.pacer = p
+
+		return nil
+	}
+}
+
+// SendSideBWENumberPackets makes the bandwidth estimator number the transport
+// wide sequence numbers of all packets as they leave the pacer, replacing the
+// numbers set by earlier interceptors. It is used when no
+// twcc.HeaderExtensionInterceptor is registered.
+func SendSideBWENumberPackets() Option {
+	return func(e *SendSideBWE) error {
+		e.sendSide.NumberPackets = true
+
+		return nil
+	}
+}
+
+// SendSideBWEClock sets the clock of the bandwidth estimator and of its default pacer.
+func SendSideBWEClock(clock interceptor.Clock) Option {
+	return func(e *SendSideBWE) error {
+		e.clock = clock
+
+		return nil
+	}
+}
+
+// WithLoggerFactory sets the logger factory for the bandwidth estimator.
+func WithLoggerFactory(factory logging.LoggerFactory) Option {
+	return func(e *SendSideBWE) error {
+		e.loggerFactory = factory
+
+		return nil
+	}
+}
+
+// NewSendSideBWE creates a new sender side bandwidth estimator.
+func NewSendSideBWE(opts ...Option) (*SendSideBWE, error) {
+	send := &SendSideBWE{
+		sendSide:   cc.NewSendSide(),
+		minBitrate: defaultMinBitrate,
+		maxBitrate: defaultMaxBitrate,
+		priority:   defaultPriority,
+		clock:      interceptor.SystemClock(),
+		close:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if err := opt(send); err != nil {
+			return nil, err
+		}
+	}
+	if send.loggerFactory == nil {
+		send.loggerFactory = logging.NewDefaultLoggerFactory()
+	}
+	send.log = send.loggerFactory.NewLogger("nada")
+	send.sendSide.Clock = send.clock
+
+	if send.initialBitrate == 0 {
+		send.initialBitrate = send.minBitrate
+	}
+	send.controller = newController(send.initialBitrate, send.minBitrate, send.maxBitrate, send.priority)
+	send.latestBitrate = send.controller.stats.TargetBitrate
+	if send.pacer == nil {
+		send.pacer = gcc.NewLeakyBucketPacer(send.latestBitrate, gcc.LeakyBucketPacerClock(send.clock))
+	}
+
+	return send, nil
+}
+
+// AddStream adds a new stream to the bandwidth estimator.
+func (e *SendSideBWE) AddStream(info *interceptor.StreamInfo, writer interceptor.RTPWriter) interceptor.RTPWriter {
+	e.sendSide.AddStream(e.pacer, info, writer, nil)
+
+	return e.pacer
+}
+
+// WriteRTCP adds some RTCP feedback to the bandwidth estimator.
+func (e *SendSideBWE) WriteRTCP(pkts []rtcp.Packet, _ interceptor.Attributes) error {
+	now := e.clock.Now()
+	e.closeLock.RLock()
+	defer e.closeLock.RUnlock()
+
+	if e.isClosed() {
+		return ErrSendSideBWEClosed
+	}
+
+	for _, pkt := range pkts {
+		feedback, ok, err := e.sendSide.OnFeedback(now, pkt)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		e.onAcks(now, feedback.Acks, feedback.RTT)
+	}
+
+	return nil
+}
+
+func (e *SendSideBWE) onAcks(now time.Time, acks []cc.Acknowledgment, rtt time.Duration) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	bitrate := e.controller.onAcks(now, acks, rtt)
+	if bitrate == e.latestBitrate {
+		return
+	}
+	stats := e.controller.stats
+	e.log.Tracef("target bitrate %v, mode %v, congestion signal %v", bitrate, stats.Mode, stats.CongestionSignal)
+	e.latestBitrate = bitrate
+	e.pacer.SetTargetBitrate(bitrate)
+
+	if e.onTargetBitrateChange != nil {
+		go e.onTargetBitrateChange(bitrate)
+	}
+}
+
+// GetTargetBitrate returns the current target bitrate in bits per second.
+func (e *SendSideBWE) GetTargetBitrate() int {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return e.latestBitrate
+}
+
+// GetStats returns some internal statistics of the bandwidth estimator.
+func (e *SendSideBWE) GetStats() map[string]any {
+	stats := e.Stats()
+
+	return map[string]any{
+		"targetBitrate":    stats.TargetBitrate,
+		"receiveBitrate":   stats.ReceiveBitrate,
+		"queueDelay":       float64(stats.QueueDelay.Microseconds()) / 1000.0,
+		"congestionSignal": float64(stats.CongestionSignal.Microseconds()) / 1000.0,
+		"lossRatio":        stats.LossRatio,
+		"markingRatio":     stats.MarkingRatio,
+		"mode":             stats.Mode.String(),
+	}
+}
+
+// Stats returns the internal statistics of the bandwidth estimator.
+func (e *SendSideBWE) Stats() Stats {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return e.controller.stats
+}
+
+// OnTargetBitrateChange sets the callback that is called when the target
+// bitrate in bits per second changes.
+func (e *SendSideBWE) OnTargetBitrateChange(f func(bitrate int)) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.onTargetBitrateChange = f
+}
+
+// isClosed returns true if SendSideBWE is closed.
+func (e *SendSideBWE) isClosed() bool {
+	select {
+	case <-e.close:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops and closes the bandwidth estimator.
+func (e *SendSideBWE) Close() error {
+	e.closeLock.Lock()
+	defer e.closeLock.Unlock()
+
+	if e.isClosed() {
+		return nil
+	}
+	close(e.close)
+
+	return e.pacer.Close()
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package nada
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/ntp"
+	"github.com/pion/interceptor/pkg/cc"
+	"github.com/pion/interceptor/pkg/gcc"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendSideBWE(t *testing.T) {
+	bwe, err := NewSendSideBWE(SendSideBWEInitialBitrate(1_000_000), SendSideBWEPacer(gcc.NewNoOpPacer()))
+	require.NoError(t, err)
+	assert.Equal(t, 1_000_000, bwe.GetTargetBitrate())
+
+	changed := make(chan int, 1)
+	bwe.OnTargetBitrateChange(func(bitrate int) {
+		changed <- bitrate
+	})
+
+	writer := bwe.AddStream(&interceptor.StreamInfo{SSRC: 1}, interceptor.RTPWriterFunc(
+		func(*rtp.Header, []byte, interceptor.Attributes) (int, error) {
+			return 0, nil
+		},
+	))
+	for i := 0; i < 20; i++ {
+		_, err = writer.Write(&rtp.Header{SSRC: 1, SequenceNumber: uint16(i)}, make([]byte, 1000), nil) //nolint:gosec
+		require.NoError(t, err)
+	}
+
+	// All packets are received ECN-CE marked, without queuing delay.
+	metricBlocks := make([]rtcp.CCFeedbackMetricBlock, 20)
+	for i := range metricBlocks {
+		metricBlocks[i] = rtcp.CCFeedbackMetricBlock{Received: true, ECN: rtcp.ECNCE}
+	}
+	require.NoError(t, bwe.WriteRTCP([]rtcp.Packet{&rtcp.CCFeedbackReport{
+		ReportBlocks:    []rtcp.CCFeedbackReportBlock{{MediaSSRC: 1, MetricBlocks: metricBlocks}},
+		ReportTimestamp: uint32(ntp.ToNTP(time.Now()) >> 16), //nolint:gosec // G115
+	}}, nil))
+
+	stats := bwe.Stats()
+	assert.Equal(t, RampUpGradual, stats.Mode)
+	assert.Equal(t, 1.0, stats.MarkingRatio)
+	assert.Less(t, bwe.GetTargetBitrate(), 1_000_000)
+	assert.Equal(t, bwe.GetTargetBitrate(), <-changed)
+	assert.Equal(t, "gradual", bwe.GetStats()["mode"])
+
+	require.NoError(t, bwe.Close())
+	assert.ErrorIs(t, bwe.WriteRTCP(nil, nil), ErrSendSideBWEClosed)
+}
+
+func TestSendSideBWE_Interceptor(t *testing.T) {
+	factory, err := cc.NewInterceptor(func() (cc.BandwidthEstimator, error) {
+		return NewSendSideBWE()
+	})
+	require.NoError(t, err)
+
+	var estimator cc.BandwidthEstimator
+	factory.OnNewPeerConnection(func(_ string, e cc.BandwidthEstimator) {
+		estimator = e
+	})
+	i, err := factory.NewInterceptor("")
+	require.NoError(t, err)
+
+	assert.IsType(t, &SendSideBWE{}, estimator)
+	assert.Equal(t, defaultMinBitrate, estimator.GetTargetBitrate())
+	require.NoError(t, i.Close())
+}
@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package netem
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+)
+
+type event struct {
+	at  time.Time
+	seq uint64
+	f   func()
+}
+
+// eventQueue is a heap of events ordered by time, then by scheduling order.
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].at.Equal(q[j].at) {
+		return q[i].seq < q[j].seq
+	}
+
+	return q[i].at.Before(q[j].at)
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x any) {
+	if e, ok := x.(*event); ok {
+		*q = append(*q, e)
+	}
+}
+
+func (q *eventQueue) Pop() any {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+
+	return e
+}
+
+// VirtualClock is an interceptor.Clock which only advances when Advance is
+// called. Events scheduled on the clock run on the goroutine calling Advance, in
+// the order of their time, and in the order they were scheduled for the same time.
+type VirtualClock struct {
+	lock   sync.Mutex
+	now    time.Time
+	seq    uint64
+	events eventQueue
+}
+
+// NewVirtualClock returns a new VirtualClock set to start.
+func NewVirtualClock(start time.Time) *VirtualClock {
+	return &VirtualClock{now: start}
+}
+
+// Now returns the current time of the clock.
+func (c *VirtualClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+// AfterFunc schedules f to run once the clock advanced by d.
+func (c *VirtualClock) AfterFunc(d time.Duration, f func()) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.schedule(c.now.Add(d), f)
+}
+
+func (c *VirtualClock) at(at time.Time, f func()) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.schedule(at, f)
+}
+
+// schedule adds an event at time at. It must be called with the lock held.
+func (c *VirtualClock) schedule(at time.Time, f func()) {
+	c.seq++
+	heap.Push(&c.events, &event{at: at, seq: c.seq, f: f})
+}
+
+// Advance advances the clock by d, running the events scheduled until then.
+func (c *VirtualClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	end := c.now.Add(d)
+	c.lock.Unlock()
+
+	for {
+		c.lock.Lock()
+		if len(c.events) == 0 || c.events[0].at.After(end) {
+			c.now = end
+			c.lock.Unlock()
+
+			return
+		}
+		e, _ := heap.Pop(&c.events).(*event)
+		c.now = e.at
+		c.lock.Unlock()
+
+		e.f()
+	}
+}
+
+// NewTicker returns a ticker sending the time of the clock every d on its channel.
+// As time.Ticker, it drops ticks for slow receivers.
+func (c *VirtualClock) NewTicker(d time.Duration) interceptor.Ticker {
+	ticker := &VirtualTicker{clock: c, interval: d, c: make(chan time.Time, 1)}
+	c.AfterFunc(d, ticker.tick)
+
+	return ticker
+}
+
+// VirtualTicker is a ticker driven by a VirtualClock.
+type VirtualTicker struct {
+	clock    *VirtualClock
+	interval time.Duration
+	c        chan time.Time
+
+	lock    sync.Mutex
+	stopped bool
+}
+
+func (t *VirtualTicker) tick() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.stopped {
+		return
+	}
+	select {
+	case t.c <- t.clock.Now():
+	default:
+	}
+	t.clock.AfterFunc(t.interval, t.tick)
+}
+
+// Ch returns the channel the ticks are sent on.
+func (t *VirtualTicker) Ch() <-chan time.Time {
+	return t.c
+}
+
+// Stop stops the ticker.
+func (t *VirtualTicker) Stop() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.stopped = true
+}
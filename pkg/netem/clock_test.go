@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package netem
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ interceptor.Clock = &VirtualClock{}
+
+func TestVirtualClock(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := NewVirtualClock(start)
+
+	var order []int
+	var times []time.Time
+	record := func(i int) func() {
+		return func() {
+			order = append(order, i)
+			times = append(times, clock.Now())
+		}
+	}
+	clock.AfterFunc(20*time.Millisecond, record(1))
+	clock.AfterFunc(10*time.Millisecond, record(2))
+	clock.AfterFunc(20*time.Millisecond, record(3))
+	clock.AfterFunc(10*time.Millisecond, func() {
+		clock.AfterFunc(5*time.Millisecond, record(4))
+	})
+
+	clock.Advance(15 * time.Millisecond)
+	assert.Equal(t, []int{2, 4}, order)
+	assert.Equal(t, start.Add(15*time.Millisecond), clock.Now())
+
+	clock.Advance(15 * time.Millisecond)
+	assert.Equal(t, []int{2, 4, 1, 3}, order)
+	assert.Equal(t, []time.Time{
+		start.Add(10 * time.Millisecond),
+		start.Add(15 * time.Millisecond),
+		start.Add(20 * time.Millisecond),
+		start.Add(20 * time.Millisecond),
+	}, times)
+	assert.Equal(t, start.Add(30*time.Millisecond), clock.Now())
+}
+
+func TestVirtualTicker(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := NewVirtualClock(start)
+	ticker := clock.NewTicker(10 * time.Millisecond)
+
+	clock.Advance(10 * time.Millisecond)
+	assert.Equal(t, start.Add(10*time.Millisecond), <-ticker.Ch())
+
+	// Ticks are dropped while the channel is full.
+	clock.Advance(30 * time.Millisecond)
+	assert.Equal(t, start.Add(20*time.Millisecond), <-ticker.Ch())
+	assert.Empty(t, ticker.Ch())
+
+	ticker.Stop()
+	clock.Advance(30 * time.Millisecond)
+	assert.Empty(t, ticker.Ch())
+}
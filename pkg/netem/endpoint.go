@@ -0,0 +1,239 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package netem
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+)
+
+// receiveMTU is the size of the buffers packets are read into.
+const receiveMTU = 1500
+
+var errNoPendingPacket = errors.New("no pending packet")
+
+// Connection connects two interceptor chains with a Link in each direction.
+type Connection struct {
+	A *Endpoint
+	B *Endpoint
+}
+
+// NewConnection connects the interceptor chains a and b. The packets written by a
+// are sent on a link with the impairments of aToB, the packets written by b on a
+// link with the impairments of bToA.
+func NewConnection(
+	clock *VirtualClock, a, b interceptor.Interceptor, aToB, bToA LinkConfig,
+) *Connection {
+	endpointA := newEndpoint(a, NewLink(clock, aToB))
+	endpointB := newEndpoint(b, NewLink(clock, bToA))
+	endpointA.peer = endpointB
+	endpointB.peer = endpointA
+	endpointA.bind()
+	endpointB.bind()
+
+	return &Connection{A: endpointA, B: endpointB}
+}
+
+// Close closes both interceptor chains.
+func (c *Connection) Close() error {
+	return errors.Join(c.A.interceptor.Close(), c.B.interceptor.Close())
+}
+
+// Endpoint is a side of a Connection. The packets written to its local streams
+// and the RTCP packets written by its interceptor chain are sent to the peer.
+// The packets received from the peer are read through its interceptor chain
+// when they arrive, on the goroutine advancing the clock.
+type Endpoint struct {
+	interceptor interceptor.Interceptor
+	link        *Link
+	peer        *Endpoint
+
+	rtcpWriter interceptor.RTCPWriter
+	rtcpReader interceptor.RTCPReader
+
+	lock          sync.Mutex
+	remoteStreams map[uint32]*remoteStream
+	pendingRTCP   [][]byte
+	onRTCP        func([]rtcp.Packet)
+}
+
+type pendingPacket struct {
+	data []byte
+	ecn  rtcp.ECN
+}
+
+type remoteStream struct {
+	reader   interceptor.RTPReader
+	pending  []pendingPacket
+	onPacket func(*rtp.Packet, interceptor.Attributes)
+}
+
+func newEndpoint(i interceptor.Interceptor, link *Link) *Endpoint {
+	return &Endpoint{
+		interceptor:   i,
+		link:          link,
+		remoteStreams: map[uint32]*remoteStream{},
+	}
+}
+
+func (e *Endpoint) bind() {
+	e.rtcpWriter = e.interceptor.BindRTCPWriter(interceptor.RTCPWriterFunc(
+		func(pkts []rtcp.Packet, _ interceptor.Attributes) (int, error) {
+			data, err := rtcp.Marshal(pkts)
+			if err != nil {
+				return 0, err
+			}
+			e.link.Send(data, rtcp.ECNNonECT, e.peer.deliverRTCP)
+
+			return len(data), nil
+		},
+	))
+	e.rtcpReader = e.interceptor.BindRTCPReader(interceptor.RTCPReaderFunc(
+		func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+			e.lock.Lock()
+			defer e.lock.Unlock()
+
+			if len(e.pendingRTCP) == 0 {
+				return 0, nil, errNoPendingPacket
+			}
+			data := e.pendingRTCP[0]
+			e.pendingRTCP = e.pendingRTCP[1:]
+			if len(data) > len(b) {
+				return 0, nil, io.ErrShortBuffer
+			}
+
+			return copy(b, data), a, nil
+		},
+	))
+}
+
+// Link returns the link the packets of the endpoint are sent on.
+func (e *Endpoint) Link() *Link {
+	return e.link
+}
+
+// AddLocalStream binds a local stream to the interceptor chain, and returns the
+// writer the packets of the stream are written to.
+func (e *Endpoint) AddLocalStream(info *interceptor.StreamInfo) interceptor.RTPWriter {
+	return e.interceptor.BindLocalStream(info, interceptor.RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+			data, err := (&rtp.Packet{Header: *header, Payload: payload}).Marshal()
+			if err != nil {
+				return 0, err
+			}
+			ecn, _ := attributes.Get(interceptor.ECNAttributesKey).(rtcp.ECN)
+			e.link.Send(data, ecn, e.peer.deliverRTP)
+
+			return len(data), nil
+		},
+	))
+}
+
+// AddRemoteStream binds a remote stream to the interceptor chain. onPacket is
+// called with the packets of the stream read from the chain, along with their
+// attributes. Packets of streams which are not added are discarded on arrival.
+func (e *Endpoint) AddRemoteStream(
+	info *interceptor.StreamInfo, onPacket func(*rtp.Packet, interceptor.Attributes),
+) {
+	stream := &remoteStream{onPacket: onPacket}
+	stream.reader = e.interceptor.BindRemoteStream(info, interceptor.RTPReaderFunc(
+		func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+			e.lock.Lock()
+			defer e.lock.Unlock()
+
+			if len(stream.pending) == 0 {
+				return 0, nil, errNoPendingPacket
+			}
+			packet := stream.pending[0]
+			stream.pending = stream.pending[1:]
+			if len(packet.data) > len(b) {
+				return 0, nil, io.ErrShortBuffer
+			}
+			if a == nil {
+				a = make(interceptor.Attributes)
+			}
+			a.Set(interceptor.ECNAttributesKey, packet.ecn)
+
+			return copy(b, packet.data), a, nil
+		},
+	))
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.remoteStreams[info.SSRC] = stream
+}
+
+// OnRTCP sets the callback called with the RTCP packets read from the chain.
+func (e *Endpoint) OnRTCP(f func([]rtcp.Packet)) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.onRTCP = f
+}
+
+// WriteRTCP writes RTCP packets to the interceptor chain, to be sent to the peer.
+func (e *Endpoint) WriteRTCP(pkts []rtcp.Packet) error {
+	_, err := e.rtcpWriter.Write(pkts, interceptor.Attributes{})
+
+	return err
+}
+
+func (e *Endpoint) deliverRTP(data []byte, ecn rtcp.ECN) {
+	header := &rtp.Header{}
+	if _, err := header.Unmarshal(data); err != nil {
+		return
+	}
+
+	e.lock.Lock()
+	stream, ok := e.remoteStreams[header.SSRC]
+	if ok {
+		stream.pending = append(stream.pending, pendingPacket{data: data, ecn: ecn})
+	}
+	e.lock.Unlock()
+	if !ok {
+		return
+	}
+
+	buf := make([]byte, max(receiveMTU, len(data)))
+	n, attributes, err := stream.reader.Read(buf, nil)
+	if err != nil {
+		return
+	}
+	packet := &rtp.Packet{}
+	if err := packet.Unmarshal(buf[:n]); err != nil {
+		return
+	}
+	if stream.onPacket != nil {
+		stream.onPacket(packet, attributes)
+	}
+}
+
+func (e *Endpoint) deliverRTCP(data []byte, _ rtcp.ECN) {
+	e.lock.Lock()
+	e.pendingRTCP = append(e.pendingRTCP, data)
+	e.lock.Unlock()
+
+	buf := make([]byte, max(receiveMTU, len(data)))
+	n, _, err := e.rtcpReader.Read(buf, nil)
+	if err != nil {
+		return
+	}
+	pkts, err := rtcp.Unmarshal(buf[:n])
+	if err != nil {
+		return
+	}
+
+	e.lock.Lock()
+	onRTCP := e.onRTCP
+	e.lock.Unlock()
+	if onRTCP != nil {
+		onRTCP(pkts)
+	}
+}
@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package netem
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnection(t *testing.T) {
+	clock := NewVirtualClock(time.Unix(1000, 0))
+	conn := NewConnection(clock, &interceptor.NoOp{}, &interceptor.NoOp{},
+		LinkConfig{Delay: 20 * time.Millisecond}, LinkConfig{Delay: 30 * time.Millisecond})
+	defer func() {
+		assert.NoError(t, conn.Close())
+	}()
+
+	var received []*rtp.Packet
+	var ecns []any
+	conn.B.AddRemoteStream(&interceptor.StreamInfo{SSRC: 1}, func(p *rtp.Packet, a interceptor.Attributes) {
+		received = append(received, p)
+		ecns = append(ecns, a.Get(interceptor.ECNAttributesKey))
+	})
+	var feedback [][]rtcp.Packet
+	conn.A.OnRTCP(func(pkts []rtcp.Packet) {
+		feedback = append(feedback, pkts)
+	})
+
+	writer := conn.A.AddLocalStream(&interceptor.StreamInfo{SSRC: 1})
+	_, err := writer.Write(&rtp.Header{SSRC: 1, SequenceNumber: 7}, []byte{1, 2, 3},
+		interceptor.Attributes{interceptor.ECNAttributesKey: rtcp.ECNECT1})
+	require.NoError(t, err)
+	// Packets of streams not added to the remote are discarded.
+	_, err = conn.A.AddLocalStream(&interceptor.StreamInfo{SSRC: 2}).Write(&rtp.Header{SSRC: 2}, nil, nil)
+	require.NoError(t, err)
+
+	clock.Advance(19 * time.Millisecond)
+	assert.Empty(t, received)
+	clock.Advance(time.Millisecond)
+	require.Len(t, received, 1)
+	assert.Equal(t, uint16(7), received[0].SequenceNumber)
+	assert.Equal(t, []byte{1, 2, 3}, received[0].Payload)
+	assert.Equal(t, []any{rtcp.ECNECT1}, ecns)
+	assert.Equal(t, uint64(2), conn.A.Link().Stats().Delivered)
+
+	require.NoError(t, conn.B.WriteRTCP([]rtcp.Packet{&rtcp.PictureLossIndication{MediaSSRC: 1}}))
+	clock.Advance(29 * time.Millisecond)
+	assert.Empty(t, feedback)
+	clock.Advance(time.Millisecond)
+	assert.Equal(t, [][]rtcp.Packet{{&rtcp.PictureLossIndication{MediaSSRC: 1}}}, feedback)
+}
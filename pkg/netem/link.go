@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package netem
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/pion/rtcp"
+)
+
+// GilbertElliott is a two state loss model. The link moves between a good and a
+// bad state on each packet with the transition probabilities, and loses the
+// packet with the loss probability of its state. Bursty loss is modeled with a
+// low loss probability in the good state and a high one in the bad state, random
+// loss with LossGood only.
+type GilbertElliott struct {
+	// GoodToBad is the probability to move from the good to the bad state.
+	GoodToBad float64
+	// BadToGood is the probability to move from the bad to the good state.
+	BadToGood float64
+	// LossGood is the probability to lose a packet in the good state.
+	LossGood float64
+	// LossBad is the probability to lose a packet in the bad state.
+	LossBad float64
+}
+
+// LinkConfig configures the impairments of a Link.
+type LinkConfig struct {
+	// Bandwidth is the bitrate of the bottleneck in bits per second. The packets
+	// wait in the bottleneck queue until they can be sent at that bitrate. 0 means
+	// an unlimited bitrate.
+	Bandwidth int
+	// QueueSize is the size in bytes of the bottleneck queue, packets arriving at a
+	// full queue are dropped. 0 means an unlimited queue.
+	QueueSize int
+	// MarkingThreshold is the queuing delay over which ECN capable packets are
+	// marked congestion experienced (CE) instead of waiting longer, as an L4S
+	// queue does. 0 disables the marking.
+	MarkingThreshold time.Duration
+	// Delay is the propagation delay of the link.
+	Delay time.Duration
+	// Jitter is the maximum random delay added to the propagation delay. Packets
+	// do not overtake each other because of jitter.
+	Jitter time.Duration
+	// Loss is the loss model of the link.
+	Loss GilbertElliott
+	// ReorderProbability is the probability that a packet is delayed by ReorderDelay,
+	// so that the packets sent after it can overtake it.
+	ReorderProbability float64
+	// ReorderDelay is the delay added to reordered packets.
+	ReorderDelay time.Duration
+	// DuplicateProbability is the probability that a packet is delivered twice.
+	DuplicateProbability float64
+	// Seed seeds the random impairments, links with the same seed and traffic
+	// impair the same packets.
+	Seed uint64
+}
+
+// LinkStats contains the packet counters of a Link.
+type LinkStats struct {
+	// Sent is the number of packets sent on the link.
+	Sent uint64
+	// Delivered is the number of packets delivered, including duplicates.
+	Delivered uint64
+	// Dropped is the number of packets dropped by the full bottleneck queue.
+	Dropped uint64
+	// Lost is the number of packets lost by the loss model.
+	Lost uint64
+	// Marked is the number of packets marked CE by the bottleneck queue.
+	Marked uint64
+	// Reordered is the number of packets delayed to be reordered.
+	Reordered uint64
+	// Duplicated is the number of packets delivered twice.
+	Duplicated uint64
+}
+
+// DeliverFunc is called with the data of a packet and its ECN codepoint when it
+// arrives at the end of a Link.
+type DeliverFunc func(data []byte, ecn rtcp.ECN)
+
+// Link emulates a one way network path on a VirtualClock.
+type Link struct {
+	clock  *VirtualClock
+	config LinkConfig
+
+	lock        sync.Mutex
+	rand        *rand.Rand
+	bad         bool
+	busyUntil   time.Time
+	lastArrival time.Time
+	stats       LinkStats
+}
+
+// NewLink returns a new Link with the impairments of config.
+func NewLink(clock *VirtualClock, config LinkConfig) *Link {
+	return &Link{
+		clock:  clock,
+		config: config,
+		rand:   rand.New(rand.NewPCG(config.Seed, config.Seed)), //nolint:gosec // G404
+	}
+}
+
+// Send sends a packet on the link at the current time of the clock. Unless the
+// packet is dropped or lost, deliver is called once the clock reaches its arrival.
+func (l *Link) Send(data []byte, ecn rtcp.ECN, deliver DeliverFunc) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.clock.Now()
+	l.stats.Sent++
+
+	departure := now
+	if l.config.Bandwidth > 0 {
+		start := maxTime(now, l.busyUntil)
+		queueDelay := start.Sub(now)
+		queued := int(queueDelay.Seconds() * float64(l.config.Bandwidth) / 8)
+		if l.config.QueueSize > 0 && queued+len(data) > l.config.QueueSize {
+			l.stats.Dropped++
+
+			return
+		}
+		if l.config.MarkingThreshold > 0 && queueDelay > l.config.MarkingThreshold &&
+			(ecn == rtcp.ECNECT0 || ecn == rtcp.ECNECT1) {
+			ecn = rtcp.ECNCE
+			l.stats.Marked++
+		}
+		transmission := time.Duration(float64(len(data)*8) / float64(l.config.Bandwidth) * float64(time.Second))
+		l.busyUntil = start.Add(transmission)
+		departure = l.busyUntil
+	}
+
+	if l.lost() {
+		l.stats.Lost++
+
+		return
+	}
+
+	arrival := departure.Add(l.config.Delay)
+	if l.config.Jitter > 0 {
+		arrival = arrival.Add(time.Duration(l.rand.Int64N(int64(l.config.Jitter))))
+	}
+	if l.config.ReorderProbability > 0 && l.rand.Float64() < l.config.ReorderProbability {
+		arrival = arrival.Add(l.config.ReorderDelay)
+		l.stats.Reordered++
+	} else {
+		arrival = maxTime(arrival, l.lastArrival)
+		l.lastArrival = arrival
+	}
+
+	packet := make([]byte, len(data))
+	copy(packet, data)
+	copies := 1
+	if l.config.DuplicateProbability > 0 && l.rand.Float64() < l.config.DuplicateProbability {
+		l.stats.Duplicated++
+		copies++
+	}
+	for range copies {
+		l.clock.at(arrival, func() {
+			l.lock.Lock()
+			l.stats.Delivered++
+			l.lock.Unlock()
+
+			deliver(packet, ecn)
+		})
+	}
+}
+
+// lost returns true if the loss model loses the next packet. It must be called
+// with the lock held.
+func (l *Link) lost() bool {
+	loss := l.config.Loss
+	if l.bad {
+		l.bad = l.rand.Float64() >= loss.BadToGood
+	} else {
+		l.bad = loss.GoodToBad > 0 && l.rand.Float64() < loss.GoodToBad
+	}
+	probability := loss.LossGood
+	if l.bad {
+		probability = loss.LossBad
+	}
+
+	return probability > 0 && l.rand.Float64() < probability
+}
+
+// Stats returns the packet counters of the link.
+func (l *Link) Stats() LinkStats {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.stats
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return b
+	}
+
+	return a
+}
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package netem
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+)
+
+type arrival struct {
+	id  byte
+	at  time.Time
+	ecn rtcp.ECN
+}
+
+// sendPackets sends n packets of size bytes every interval on a link, the first
+// byte of a packet is its index.
+func sendPackets(clock *VirtualClock, link *Link, n, size int, interval time.Duration) *[]arrival {
+	arrivals := &[]arrival{}
+	for i := range n {
+		data := make([]byte, size)
+		data[0] = byte(i)
+		link.Send(data, rtcp.ECNECT1, func(data []byte, ecn rtcp.ECN) {
+			*arrivals = append(*arrivals, arrival{id: data[0], at: clock.Now(), ecn: ecn})
+		})
+		clock.Advance(interval)
+	}
+	clock.Advance(10 * time.Second)
+
+	return arrivals
+}
+
+func TestLink(t *testing.T) {
+	start := time.Unix(1000, 0)
+
+	t.Run("bandwidth and delay", func(t *testing.T) {
+		clock := NewVirtualClock(start)
+		link := NewLink(clock, LinkConfig{Bandwidth: 800_000, Delay: 50 * time.Millisecond})
+		arrivals := sendPackets(clock, link, 3, 1000, 0)
+
+		// Each packet takes 10ms to be sent at the bandwidth of the link.
+		assert.Equal(t, []arrival{
+			{id: 0, at: start.Add(60 * time.Millisecond), ecn: rtcp.ECNECT1},
+			{id: 1, at: start.Add(70 * time.Millisecond), ecn: rtcp.ECNECT1},
+			{id: 2, at: start.Add(80 * time.Millisecond), ecn: rtcp.ECNECT1},
+		}, *arrivals)
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		clock := NewVirtualClock(start)
+		link := NewLink(clock, LinkConfig{
+			Bandwidth: 800_000, QueueSize: 3000, MarkingThreshold: 15 * time.Millisecond,
+		})
+		arrivals := sendPackets(clock, link, 5, 1000, 0)
+
+		// The queue holds three packets, the packets waiting over 15ms are marked.
+		assert.Len(t, *arrivals, 3)
+		assert.Equal(t, rtcp.ECNCE, (*arrivals)[2].ecn)
+		assert.Equal(t, LinkStats{Sent: 5, Delivered: 3, Dropped: 2, Marked: 1}, link.Stats())
+	})
+
+	t.Run("jitter", func(t *testing.T) {
+		clock := NewVirtualClock(start)
+		link := NewLink(clock, LinkConfig{Delay: 50 * time.Millisecond, Jitter: 30 * time.Millisecond, Seed: 1})
+		arrivals := sendPackets(clock, link, 100, 100, time.Millisecond)
+
+		assert.Len(t, *arrivals, 100)
+		for i, a := range *arrivals {
+			assert.Equal(t, byte(i), a.id)
+			if i > 0 {
+				assert.False(t, a.at.Before((*arrivals)[i-1].at))
+			}
+		}
+	})
+
+	t.Run("loss", func(t *testing.T) {
+		clock := NewVirtualClock(start)
+		link := NewLink(clock, LinkConfig{
+			Loss: GilbertElliott{GoodToBad: 0.05, BadToGood: 0.5, LossBad: 1},
+			Seed: 1,
+		})
+		arrivals := sendPackets(clock, link, 1000, 100, time.Millisecond)
+
+		// The link is in the bad state a tenth of the time.
+		stats := link.Stats()
+		assert.InDelta(t, 100, stats.Lost, 30)
+		assert.Len(t, *arrivals, int(stats.Sent-stats.Lost))
+
+		// The same seed loses the same packets.
+		other := NewLink(clock, link.config)
+		assert.Equal(t, len(*arrivals), len(*sendPackets(clock, other, 1000, 100, time.Millisecond)))
+	})
+
+	t.Run("reordering and duplication", func(t *testing.T) {
+		clock := NewVirtualClock(start)
+		link := NewLink(clock, LinkConfig{
+			ReorderProbability:   0.1,
+			ReorderDelay:         5 * time.Millisecond,
+			DuplicateProbability: 0.1,
+			Seed:                 1,
+		})
+		arrivals := sendPackets(clock, link, 100, 100, time.Millisecond)
+
+		stats := link.Stats()
+		assert.Positive(t, stats.Reordered)
+		assert.Positive(t, stats.Duplicated)
+		assert.Len(t, *arrivals, 100+int(stats.Duplicated))
+
+		reordered := 0
+		for i := 1; i < len(*arrivals); i++ {
+			if (*arrivals)[i].id < (*arrivals)[i-1].id {
+				reordered++
+			}
+		}
+		assert.Positive(t, reordered)
+	})
+}
@@ -0,0 +1,20 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package netem emulates network links between interceptor chains on a virtual
+// clock, so that the behavior of interceptors under bandwidth limits, delay,
+// jitter, loss, reordering and duplication can be tested reproducibly without
+// sockets.
+//
+// A Link emulates a one way path: the packets wait in a bottleneck queue to be
+// sent at the bandwidth of the link, then arrive after the propagation delay
+// and jitter, unless they are dropped by the full queue or lost by the
+// Gilbert-Elliott loss model. A Connection connects two interceptor chains
+// with a Link in each direction.
+//
+// The links only advance with their VirtualClock, and their impairments are
+// drawn from seeded random sources, so that a sequence of writes and clock
+// advances always impairs the same packets. The VirtualClock is an
+// interceptor.Clock, it should be given to the interceptors of the chains with
+// their options so that they run on the same time as the links.
+package netem
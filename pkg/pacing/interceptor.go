@@ -52,6 +52,16 @@ func Interval(interval time.Duration) Option {
 	}
 }
 
+// Clock configures the clock the packets are paced by for interceptors created
+// by the interceptor factory.
+func Clock(clock interceptor.Clock) Option {
+	return func(i *Interceptor) error {
+		i.clock = clock
+
+		return nil
+	}
+}
+
 // WithLoggerFactory sets a logger factory for the interceptor.
 func WithLoggerFactory(loggerFactory logging.LoggerFactory) Option {
 	return func(i *Interceptor) error {
@@ -61,6 +71,19 @@ func WithLoggerFactory(loggerFactory logging.LoggerFactory) Option {
 	}
 }
 
+// Bypass makes the packets of the given classes skip the queues. They are sent as
+// soon as they are written, regardless of the pacing budget, which they still
+// consume when available.
+func Bypass(classes ...Class) Option {
+	return func(i *Interceptor) error {
+		for _, class := range classes {
+			i.bypass[class] = true
+		}
+
+		return nil
+	}
+}
+
 func setPacerFactory(f pacerFactory) Option {
 	return func(i *Interceptor) error {
 		i.pacerFactory = f
@@ -98,6 +121,20 @@ func (f *InterceptorFactory) SetRate(id string, r int) {
 	i.setRate(r)
 }
 
+// QueueStats returns the queueing statistics per priority class of the pacing
+// interceptor with the given ID.
+func (f *InterceptorFactory) QueueStats(id string) (map[Class]QueueStats, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	i, ok := f.interceptors[id]
+	if !ok {
+		return nil, false
+	}
+
+	return i.queueStats(), true
+}
+
 func (f *InterceptorFactory) remove(id string) {
 	f.lock.Lock()
 	defer f.lock.Unlock()
@@ -114,11 +151,14 @@ func (f *InterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor,
 		initialRate: 1_000_000,
 		interval:    5 * time.Millisecond,
 		queueSize:   1_000_000,
+		clock:       interceptor.SystemClock(),
 		pacerFactory: func(initialRate, burst int) pacer {
 			return newRateLimitPacer(initialRate, burst)
 		},
+		bypass:  map[Class]bool{},
 		limit:   nil,
 		queue:   nil,
+		stats:   map[Class]QueueStats{},
 		closed:  make(chan struct{}),
 		wg:      sync.WaitGroup{},
 		id:      id,
@@ -152,6 +192,10 @@ func (f *InterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor,
 
 // Interceptor implements packet pacing using a token bucket filter and sends
 // packets at a fixed interval.
+//
+// Packets are queued per priority Class, and sent from the queue with the
+// highest priority first, so that audio and retransmissions preempt a burst of
+// video packets. Packets of the classes set with Bypass are not queued.
 type Interceptor struct {
 	interceptor.NoOp
 	log           logging.LeveledLogger
@@ -161,12 +205,17 @@ type Interceptor struct {
 	initialRate  int
 	interval     time.Duration
 	queueSize    int
+	clock        interceptor.Clock
 	pacerFactory pacerFactory
+	bypass       map[Class]bool
 
 	// limiter and queue
 	limit pacer
 	queue chan packet
 
+	statsLock sync.Mutex
+	stats     map[Class]QueueStats
+
 	// shutdown
 	closed  chan struct{}
 	wg      sync.WaitGroup
@@ -190,6 +239,13 @@ func (i *Interceptor) setRate(r int) {
 	i.limit.SetRate(r, burst(r, i.interval))
 }
 
+func (i *Interceptor) queueStats() map[Class]QueueStats {
+	i.statsLock.Lock()
+	defer i.statsLock.Unlock()
+
+	return maps.Clone(i.stats)
+}
+
 // BindLocalStream implements interceptor.Interceptor.
 func (i *Interceptor) BindLocalStream(
 	info *interceptor.StreamInfo,
@@ -210,6 +266,8 @@ func (i *Interceptor) BindLocalStream(
 			header:     &hdr,
 			payload:    pay,
 			attributes: attr,
+			class:      Classify(info, header, attributes),
+			enqueued:   i.clock.Now(),
 		}:
 		case <-i.closed:
 			return 0, errPacerClosed
@@ -231,33 +289,89 @@ func (i *Interceptor) Close() error {
 }
 
 func (i *Interceptor) loop() {
-	ticker := time.NewTicker(i.interval)
+	ticker := i.clock.NewTicker(i.interval)
 	defer ticker.Stop()
-	queue := make([]packet, 0)
+	queue := newPriorityQueue()
 	for {
 		select {
-		case now := <-ticker.C:
-			for len(queue) > 0 && i.limit.Budget(now) > 8*float64(queue[0].len()) {
-				i.limit.AllowN(now, 8*queue[0].len())
-				var next packet
-				next, queue = queue[0], queue[1:]
-				if _, err := next.writer.Write(next.header, next.payload, next.attributes); err != nil {
-					slog.Warn("error on writing RTP packet", "error", err)
+		case now := <-ticker.Ch():
+			// Packets written since the last tick are queued first, to be sent
+			// before the packets of the classes with a lower priority.
+			i.drain(queue)
+			for {
+				next, ok := queue.peek()
+				if !ok || i.limit.Budget(now) <= 8*float64(next.len()) {
+					break
 				}
+				i.limit.AllowN(now, 8*next.len())
+				queue.pop()
+				i.send(next, now)
 			}
 		case pkt := <-i.queue:
-			queue = append(queue, pkt)
+			i.enqueue(queue, pkt)
 		case <-i.closed:
 			return
 		}
 	}
 }
 
+func (i *Interceptor) drain(queue *priorityQueue) {
+	for {
+		select {
+		case pkt := <-i.queue:
+			i.enqueue(queue, pkt)
+		default:
+			return
+		}
+	}
+}
+
+func (i *Interceptor) enqueue(queue *priorityQueue, pkt packet) {
+	if i.bypass[pkt.class] {
+		now := i.clock.Now()
+		i.limit.AllowN(now, 8*pkt.len())
+		i.send(pkt, now)
+
+		return
+	}
+
+	queue.push(pkt)
+
+	i.statsLock.Lock()
+	stats := i.stats[pkt.class]
+	stats.Queued++
+	i.stats[pkt.class] = stats
+	i.statsLock.Unlock()
+}
+
+func (i *Interceptor) send(pkt packet, now time.Time) {
+	delay := max(now.Sub(pkt.enqueued), 0)
+	if pkt.attributes == nil {
+		pkt.attributes = make(interceptor.Attributes)
+	}
+	pkt.attributes.Set(interceptor.SendDelayAttributesKey, delay)
+	if _, err := pkt.writer.Write(pkt.header, pkt.payload, pkt.attributes); err != nil {
+		slog.Warn("error on writing RTP packet", "error", err)
+	}
+
+	i.statsLock.Lock()
+	defer i.statsLock.Unlock()
+
+	stats := i.stats[pkt.class]
+	if !i.bypass[pkt.class] {
+		stats.Queued--
+	}
+	stats.OnSent(pkt.len(), delay)
+	i.stats[pkt.class] = stats
+}
+
 type packet struct {
 	writer     interceptor.RTPWriter
 	header     *rtp.Header
 	payload    []byte
 	attributes interceptor.Attributes
+	class      Class
+	enqueued   time.Time
 }
 
 func (p *packet) len() int {
@@ -10,6 +10,7 @@ import (
 
 	"github.com/pion/interceptor"
 	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/interceptor/pkg/netem"
 	"github.com/pion/logging"
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/assert"
@@ -142,4 +143,206 @@ func TestInterceptor(t *testing.T) {
 		case <-time.After(10 * time.Millisecond):
 		}
 	})
+
+	t.Run("paces_on_clock", func(t *testing.T) {
+		mp := &mockPacer{allow: true, budget: 8 * 1500}
+		clock := netem.NewVirtualClock(time.Now())
+		i := NewInterceptor(
+			setPacerFactory(func(initialRate, burst int) pacer {
+				return mp
+			}),
+			Interval(time.Millisecond),
+			Clock(clock),
+		)
+
+		pacer, err := i.NewInterceptor("")
+		assert.NoError(t, err)
+
+		stream := test.NewMockStream(&interceptor.StreamInfo{}, pacer)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+
+		hdr := rtp.Header{}
+		err = stream.WriteRTP(&rtp.Packet{
+			Header:  hdr,
+			Payload: make([]byte, 1200-hdr.MarshalSize()),
+		})
+		assert.NoError(t, err)
+
+		select {
+		case <-stream.WrittenRTP():
+			assert.Fail(t, "RTP packet written before the clock advanced")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		clock.Advance(time.Millisecond)
+		select {
+		case <-stream.WrittenRTP():
+		case <-time.After(time.Second):
+			assert.Fail(t, "no RTP packet written")
+		}
+	})
+
+	t.Run("sets_send_delay", func(t *testing.T) {
+		mp := &mockPacer{allow: true, budget: 8 * 1500}
+		clock := netem.NewVirtualClock(time.Now())
+		i := NewInterceptor(
+			setPacerFactory(func(initialRate, burst int) pacer {
+				return mp
+			}),
+			Interval(time.Millisecond),
+			Clock(clock),
+		)
+
+		pacer, err := i.NewInterceptor("")
+		assert.NoError(t, err)
+		defer func() {
+			assert.NoError(t, pacer.Close())
+		}()
+
+		delays := make(chan any, 1)
+		writer := pacer.BindLocalStream(&interceptor.StreamInfo{}, interceptor.RTPWriterFunc(
+			func(_ *rtp.Header, _ []byte, attributes interceptor.Attributes) (int, error) {
+				delays <- attributes.Get(interceptor.SendDelayAttributesKey)
+
+				return 0, nil
+			},
+		))
+		_, err = writer.Write(&rtp.Header{}, make([]byte, 1000), nil)
+		assert.NoError(t, err)
+
+		// let the pacer queue the packet before the clock advances
+		time.Sleep(10 * time.Millisecond)
+		clock.Advance(time.Millisecond)
+		select {
+		case delay := <-delays:
+			assert.Equal(t, time.Millisecond, delay)
+		case <-time.After(time.Second):
+			assert.Fail(t, "no RTP packet written")
+		}
+	})
+}
+
+func TestInterceptor_Priority(t *testing.T) {
+	newPacer := func(t *testing.T, mp *mockPacer, opts ...Option) (*InterceptorFactory, interceptor.Interceptor) {
+		t.Helper()
+
+		opts = append(opts, setPacerFactory(func(int, int) pacer {
+			return mp
+		}), Interval(time.Millisecond))
+		factory := NewInterceptor(opts...)
+		i, err := factory.NewInterceptor("")
+		assert.NoError(t, err)
+
+		return factory, i
+	}
+	videoInfo := &interceptor.StreamInfo{
+		SSRC:                       1,
+		SSRCRetransmission:         2,
+		SSRCForwardErrorCorrection: 3,
+		MimeType:                   "video/VP8",
+	}
+	audioInfo := &interceptor.StreamInfo{SSRC: 4, MimeType: "audio/opus"}
+
+	t.Run("sends_by_priority", func(t *testing.T) {
+		mp := &mockPacer{}
+		factory, i := newPacer(t, mp)
+		defer func() {
+			assert.NoError(t, i.Close())
+		}()
+
+		written := make(chan uint16, 10)
+		record := interceptor.RTPWriterFunc(func(h *rtp.Header, _ []byte, _ interceptor.Attributes) (int, error) {
+			written <- h.SequenceNumber
+
+			return 0, nil
+		})
+		video := i.BindLocalStream(videoInfo, record)
+		audio := i.BindLocalStream(audioInfo, record)
+
+		for _, p := range []struct {
+			writer interceptor.RTPWriter
+			ssrc   uint32
+			seq    uint16
+			attr   interceptor.Attributes
+		}{
+			{video, 1, 1, nil},
+			{video, 1, 2, nil},
+			{video, 3, 3, nil},
+			{video, 1, 4, interceptor.Attributes{interceptor.RetransmittedAttributesKey: true}},
+			{video, 2, 5, nil},
+			{audio, 4, 6, nil},
+		} {
+			_, err := p.writer.Write(&rtp.Header{SSRC: p.ssrc, SequenceNumber: p.seq}, make([]byte, 100), p.attr)
+			assert.NoError(t, err)
+		}
+
+		mp.lock.Lock()
+		mp.budget = 8 * 1500 * 10
+		mp.lock.Unlock()
+
+		for _, seq := range []uint16{6, 4, 5, 1, 2, 3} {
+			select {
+			case got := <-written:
+				assert.Equal(t, seq, got)
+			case <-time.After(time.Second):
+				assert.FailNow(t, "no RTP packet written")
+			}
+		}
+
+		stats, ok := factory.QueueStats("")
+		assert.True(t, ok)
+		assert.Equal(t, uint64(1), stats[ClassAudio].Sent)
+		assert.Equal(t, uint64(2), stats[ClassRetransmission].Sent)
+		assert.Equal(t, uint64(2), stats[ClassVideo].Sent)
+		assert.Equal(t, uint64(1), stats[ClassFEC].Sent)
+		assert.Equal(t, uint64(112), stats[ClassFEC].SentBytes)
+		for _, class := range Classes() {
+			assert.Equal(t, 0, stats[class].Queued)
+		}
+
+		_, ok = factory.QueueStats("unknown")
+		assert.False(t, ok)
+	})
+
+	t.Run("bypasses_queue", func(t *testing.T) {
+		mp := &mockPacer{}
+		factory, i := newPacer(t, mp, Bypass(ClassAudio))
+		defer func() {
+			assert.NoError(t, i.Close())
+		}()
+
+		written := make(chan uint32, 10)
+		record := interceptor.RTPWriterFunc(func(h *rtp.Header, _ []byte, _ interceptor.Attributes) (int, error) {
+			written <- h.SSRC
+
+			return 0, nil
+		})
+		video := i.BindLocalStream(videoInfo, record)
+		audio := i.BindLocalStream(audioInfo, record)
+
+		_, err := video.Write(&rtp.Header{SSRC: 1}, make([]byte, 1000), nil)
+		assert.NoError(t, err)
+		_, err = audio.Write(&rtp.Header{SSRC: 4}, make([]byte, 100), nil)
+		assert.NoError(t, err)
+
+		select {
+		case ssrc := <-written:
+			assert.Equal(t, uint32(4), ssrc)
+		case <-time.After(time.Second):
+			assert.FailNow(t, "audio packet not written")
+		}
+		select {
+		case <-written:
+			assert.Fail(t, "video packet written without pacing budget")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		stats, ok := factory.QueueStats("")
+		assert.True(t, ok)
+		assert.Equal(t, uint64(1), stats[ClassAudio].Sent)
+		assert.Equal(t, 0, stats[ClassAudio].Queued)
+		assert.Equal(t, 1, stats[ClassVideo].Queued)
+	})
 }
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package pacing
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtp"
+)
+
+// Class is the priority class of a packet in a pacer. Queued packets of a class
+// are only sent once the queues of all classes with a higher priority are empty.
+type Class int
+
+const (
+	// ClassAudio is the class of the packets of audio streams. It has the highest priority.
+	ClassAudio Class = iota
+	// ClassRetransmission is the class of retransmitted packets, either on the RTX
+	// stream or on the media stream.
+	ClassRetransmission
+	// ClassVideo is the class of the packets of video and other non audio streams.
+	ClassVideo
+	// ClassFEC is the class of forward error correction packets. It has the lowest priority.
+	ClassFEC
+)
+
+// Classes returns all priority classes, from the highest to the lowest priority.
+func Classes() []Class {
+	return []Class{ClassAudio, ClassRetransmission, ClassVideo, ClassFEC}
+}
+
+func (c Class) String() string {
+	switch c {
+	case ClassAudio:
+		return "audio"
+	case ClassRetransmission:
+		return "retransmission"
+	case ClassVideo:
+		return "video"
+	case ClassFEC:
+		return "fec"
+	default:
+		return "unknown"
+	}
+}
+
+// Classify returns the priority class of a packet written to the local stream
+// described by info, which may be nil.
+//
+// A packet is a retransmission if it is sent on the RTX stream, or if
+// interceptor.RetransmittedAttributesKey is set in its attributes. It is a FEC packet
+// if it is sent on the FEC stream or with the FEC payload type. Otherwise, the
+// class is given by the media kind of the MimeType of the stream.
+func Classify(info *interceptor.StreamInfo, header *rtp.Header, attributes interceptor.Attributes) Class {
+	if retransmitted, _ := attributes.Get(interceptor.RetransmittedAttributesKey).(bool); retransmitted {
+		return ClassRetransmission
+	}
+	if info == nil {
+		return ClassVideo
+	}
+
+	switch {
+	case info.SSRCRetransmission != 0 && header.SSRC == info.SSRCRetransmission:
+		return ClassRetransmission
+	case info.SSRCForwardErrorCorrection != 0 && header.SSRC == info.SSRCForwardErrorCorrection,
+		info.PayloadTypeForwardErrorCorrection != 0 && header.PayloadType == info.PayloadTypeForwardErrorCorrection:
+		return ClassFEC
+	case strings.HasPrefix(strings.ToLower(info.MimeType), "audio/"):
+		return ClassAudio
+	default:
+		return ClassVideo
+	}
+}
+
+// QueueStats contains the queueing statistics of a priority class of a pacer.
+type QueueStats struct {
+	// Queued is the number of packets currently waiting in the queue.
+	Queued int
+	// Sent is the number of packets sent.
+	Sent uint64
+	// SentBytes is the number of bytes sent.
+	SentBytes uint64
+	// TotalQueueTime is the sum of the times the sent packets waited in the queue.
+	TotalQueueTime time.Duration
+	// MaxQueueTime is the longest time a sent packet waited in the queue.
+	MaxQueueTime time.Duration
+}
+
+// OnSent records a packet of size bytes that was sent after waiting queueTime in
+// the queue.
+func (s *QueueStats) OnSent(size int, queueTime time.Duration) {
+	s.Sent++
+	s.SentBytes += uint64(size) //nolint:gosec // G115
+	s.TotalQueueTime += queueTime
+	s.MaxQueueTime = max(s.MaxQueueTime, queueTime)
+}
+
+// MeanQueueTime returns the mean time the sent packets waited in the queue.
+func (s QueueStats) MeanQueueTime() time.Duration {
+	if s.Sent == 0 {
+		return 0
+	}
+
+	return s.TotalQueueTime / time.Duration(s.Sent) //nolint:gosec // G115
+}
+
+// priorityQueue holds a FIFO queue of packets per priority class.
+type priorityQueue struct {
+	queues map[Class][]packet
+}
+
+func newPriorityQueue() *priorityQueue {
+	queues := map[Class][]packet{}
+	for _, class := range Classes() {
+		queues[class] = nil
+	}
+
+	return &priorityQueue{queues: queues}
+}
+
+func (q *priorityQueue) push(p packet) {
+	q.queues[p.class] = append(q.queues[p.class], p)
+}
+
+// peek returns the oldest packet of the class with the highest priority.
+func (q *priorityQueue) peek() (packet, bool) {
+	for _, class := range Classes() {
+		if queue := q.queues[class]; len(queue) > 0 {
+			return queue[0], true
+		}
+	}
+
+	return packet{}, false
+}
+
+// pop removes the packet returned by peek.
+func (q *priorityQueue) pop() {
+	for _, class := range Classes() {
+		if queue := q.queues[class]; len(queue) > 0 {
+			q.queues[class] = queue[1:]
+
+			return
+		}
+	}
+}
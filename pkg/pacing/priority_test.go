@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package pacing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	video := &interceptor.StreamInfo{
+		SSRC:                              1,
+		SSRCRetransmission:                2,
+		SSRCForwardErrorCorrection:        3,
+		PayloadType:                       96,
+		PayloadTypeForwardErrorCorrection: 118,
+		MimeType:                          "video/VP8",
+	}
+	audio := &interceptor.StreamInfo{SSRC: 4, MimeType: "Audio/opus"}
+	retransmitted := interceptor.Attributes{interceptor.RetransmittedAttributesKey: true}
+
+	for _, tc := range []struct {
+		name   string
+		info   *interceptor.StreamInfo
+		header rtp.Header
+		attr   interceptor.Attributes
+		class  Class
+	}{
+		{"video", video, rtp.Header{SSRC: 1, PayloadType: 96}, nil, ClassVideo},
+		{"rtx", video, rtp.Header{SSRC: 2}, nil, ClassRetransmission},
+		{"retransmitted", video, rtp.Header{SSRC: 1, PayloadType: 96}, retransmitted, ClassRetransmission},
+		{"fec_ssrc", video, rtp.Header{SSRC: 3}, nil, ClassFEC},
+		{"fec_payload_type", video, rtp.Header{SSRC: 1, PayloadType: 118}, nil, ClassFEC},
+		{"audio", audio, rtp.Header{SSRC: 4}, nil, ClassAudio},
+		{"unknown_stream", nil, rtp.Header{SSRC: 5}, nil, ClassVideo},
+		{"unknown_stream_retransmitted", nil, rtp.Header{SSRC: 5}, retransmitted, ClassRetransmission},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.class, Classify(tc.info, &tc.header, tc.attr))
+		})
+	}
+}
+
+func TestQueueStats(t *testing.T) {
+	stats := QueueStats{}
+	assert.Equal(t, time.Duration(0), stats.MeanQueueTime())
+
+	stats.OnSent(100, 10*time.Millisecond)
+	stats.OnSent(200, 30*time.Millisecond)
+	assert.Equal(t, QueueStats{
+		Sent:           2,
+		SentBytes:      300,
+		TotalQueueTime: 40 * time.Millisecond,
+		MaxQueueTime:   30 * time.Millisecond,
+	}, stats)
+	assert.Equal(t, 20*time.Millisecond, stats.MeanQueueTime())
+}
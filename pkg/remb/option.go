@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package remb
+
+import (
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+)
+
+// ReceiverOption can be used to configure ReceiverInterceptor.
+type ReceiverOption func(r *ReceiverInterceptor) error
+
+// WithInterval sets the interval at which REMB packets are sent.
+func WithInterval(interval time.Duration) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		r.interval = interval
+
+		return nil
+	}
+}
+
+// WithSenderSSRC sets the sender SSRC of the REMB packets.
+func WithSenderSSRC(ssrc uint32) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		r.senderSSRC = ssrc
+
+		return nil
+	}
+}
+
+// WithClock sets the clock the packet arrivals are taken from and the REMB
+// packets are sent by.
+func WithClock(clock interceptor.Clock) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		r.clock = clock
+
+		return nil
+	}
+}
+
+// WithLoggerFactory sets a logger factory for the interceptor.
+func WithLoggerFactory(loggerFactory logging.LoggerFactory) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		r.loggerFactory = loggerFactory
+
+		return nil
+	}
+}
@@ -0,0 +1,424 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package remb implements an interceptor estimating the available bandwidth on the
+// receiving side and signaling it to the sender with REMB.
+package remb
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/cc"
+	"github.com/pion/interceptor/pkg/gcc"
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+)
+
+const (
+	absSendTimeURI = "http://www.webrtc.org/experiments/rtp-hdrext/abs-send-time"
+	// defaultInterval is the interval at which REMB packets are sent by default.
+	defaultInterval = time.Second
+	// decreaseThreshold is the share of the last sent estimate under which a REMB
+	// packet is sent right away.
+	decreaseThreshold = 0.97
+)
+
+// BandwidthEstimator estimates the available bandwidth from the packets received on
+// the remote streams.
+type BandwidthEstimator interface {
+	OnPacket(departure, arrival time.Time, size int) error
+	GetTargetBitrate() int
+	OnTargetBitrateChange(f func(bitrate int))
+	GetStats() map[string]any
+	Close() error
+}
+
+// BandwidthEstimatorFactory creates new BandwidthEstimators.
+type BandwidthEstimatorFactory func() (BandwidthEstimator, error)
+
+// NewPeerConnectionCallback returns the BandwidthEstimator for the
+// PeerConnection with id.
+type NewPeerConnectionCallback func(id string, estimator BandwidthEstimator)
+
+// ReceiverInterceptorFactory is a interceptor.Factory for a ReceiverInterceptor.
+type ReceiverInterceptorFactory struct {
+	opts              []ReceiverOption
+	bweFactory        BandwidthEstimatorFactory
+	addPeerConnection NewPeerConnectionCallback
+}
+
+// NewReceiverInterceptor returns a new ReceiverInterceptorFactory. Without factory,
+// the delay based estimator of GCC is used.
+func NewReceiverInterceptor(
+	factory BandwidthEstimatorFactory, opts ...ReceiverOption,
+) (*ReceiverInterceptorFactory, error) {
+	if factory == nil {
+		factory = func() (BandwidthEstimator, error) {
+			return gcc.NewReceiveSideBWE()
+		}
+	}
+
+	return &ReceiverInterceptorFactory{opts: opts, bweFactory: factory}, nil
+}
+
+// OnNewPeerConnection sets a callback that is called when a new REMB interceptor
+// is created.
+func (f *ReceiverInterceptorFactory) OnNewPeerConnection(cb NewPeerConnectionCallback) {
+	f.addPeerConnection = cb
+}
+
+// NewInterceptor constructs a new ReceiverInterceptor.
+func (f *ReceiverInterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor, error) {
+	bwe, err := f.bweFactory()
+	if err != nil {
+		return nil, err
+	}
+
+	receiverInterceptor := &ReceiverInterceptor{
+		estimator: bwe,
+		interval:  defaultInterval,
+		clock:     interceptor.SystemClock(),
+		ssrcs:     map[uint32]struct{}{},
+		decreased: make(chan struct{}, 1),
+		close:     make(chan struct{}),
+	}
+
+	for _, opt := range f.opts {
+		if err := opt(receiverInterceptor); err != nil {
+			return nil, err
+		}
+	}
+
+	if receiverInterceptor.loggerFactory == nil {
+		receiverInterceptor.loggerFactory = logging.NewDefaultLoggerFactory()
+	}
+	receiverInterceptor.log = receiverInterceptor.loggerFactory.NewLogger("remb_receiver")
+
+	if f.addPeerConnection != nil {
+		f.addPeerConnection(id, bwe)
+	}
+
+	return receiverInterceptor, nil
+}
+
+// ReceiverInterceptor estimates the available bandwidth from the packets of the
+// remote streams and sends it to the sender in REMB packets.
+//
+// The departure time of a packet is read from the abs-send-time header
+// extension when it is negotiated. Otherwise, it is derived from the RTP
+// timestamp of the packet, which is only accurate for senders sending packets
+// as they are captured. As the RTP timestamps of the streams are on unrelated
+// clocks, they are only used while a single remote stream is bound, unless the
+// streams negotiate the transport wide sequence numbers of TWCC. These number
+// the packets of all streams in their send order, the departures derived from
+// the RTP timestamps are kept in that order, so that the packets of every
+// stream are used. The packets of the streams without either extension are
+// ignored while several remote streams are bound.
+//
+// REMB packets are sent at a fixed interval, and right away when the estimate
+// decreases.
+type ReceiverInterceptor struct {
+	interceptor.NoOp
+	estimator     BandwidthEstimator
+	interval      time.Duration
+	clock         interceptor.Clock
+	senderSSRC    uint32
+	m             sync.Mutex
+	ssrcs         map[uint32]struct{}
+	lastSent      int
+	transport     transportOrder
+	decreased     chan struct{}
+	wg            sync.WaitGroup
+	close         chan struct{}
+	log           logging.LeveledLogger
+	loggerFactory logging.LoggerFactory
+}
+
+// transportOrder keeps the departures derived from the RTP timestamps of
+// several streams in the send order given by the transport wide sequence numbers.
+type transportOrder struct {
+	started       bool
+	lastSequence  uint16
+	lastDeparture time.Time
+}
+
+// order returns the departure of the packet with the transport wide sequence
+// number sequence: not before the departure of the packets numbered before it,
+// and not after the ones numbered after it.
+func (o *transportOrder) order(sequence uint16, departure time.Time) time.Time {
+	if !o.started {
+		o.started = true
+		o.lastSequence = sequence
+		o.lastDeparture = departure
+
+		return departure
+	}
+
+	if int16(sequence-o.lastSequence) <= 0 { //nolint:gosec // G115
+		if departure.After(o.lastDeparture) {
+			return o.lastDeparture
+		}
+
+		return departure
+	}
+
+	if departure.Before(o.lastDeparture) {
+		departure = o.lastDeparture
+	}
+	o.lastSequence = sequence
+	o.lastDeparture = departure
+
+	return departure
+}
+
+// streamState derives the departure times of the packets of a remote stream.
+type streamState struct {
+	absSendTimeID uint8
+	transportCCID uint8
+	clockRate     uint32
+	started       bool
+	lastTimestamp uint32
+	lastDeparture time.Time
+}
+
+// departure returns the departure time of a packet. Without abs-send-time, it is
+// derived from the RTP timestamp if fromTimestamp is set, and starts over from
+// the arrival time once it is set again.
+func (s *streamState) departure(header *rtp.Header, arrival time.Time, fromTimestamp bool) (time.Time, bool) {
+	if departure, ok := s.absSendTime(header, arrival); ok {
+		return departure, true
+	}
+	if s.clockRate == 0 || !fromTimestamp {
+		s.started = false
+
+		return time.Time{}, false
+	}
+
+	if !s.started {
+		s.started = true
+		s.lastTimestamp = header.Timestamp
+		s.lastDeparture = arrival
+
+		return arrival, true
+	}
+
+	diff := int32(header.Timestamp - s.lastTimestamp) //nolint:gosec // G115
+	s.lastTimestamp = header.Timestamp
+	s.lastDeparture = s.lastDeparture.Add(time.Duration(diff) * time.Second / time.Duration(s.clockRate))
+
+	return s.lastDeparture, true
+}
+
+// absSendTime returns the departure time of a packet read from its abs-send-time.
+func (s *streamState) absSendTime(header *rtp.Header, arrival time.Time) (time.Time, bool) {
+	if s.absSendTimeID == 0 {
+		return time.Time{}, false
+	}
+	payload := header.GetExtension(s.absSendTimeID)
+	if payload == nil {
+		return time.Time{}, false
+	}
+	ext := &rtp.AbsSendTimeExtension{}
+	if err := ext.Unmarshal(payload); err != nil {
+		return time.Time{}, false
+	}
+
+	return ext.Estimate(arrival), true
+}
+
+// transportSequenceNumber returns the transport wide sequence number of a packet.
+func (s *streamState) transportSequenceNumber(header *rtp.Header) (uint16, bool) {
+	if s.transportCCID == 0 {
+		return 0, false
+	}
+	payload := header.GetExtension(s.transportCCID)
+	if payload == nil {
+		return 0, false
+	}
+	ext := &rtp.TransportCCExtension{}
+	if err := ext.Unmarshal(payload); err != nil {
+		return 0, false
+	}
+
+	return ext.TransportSequence, true
+}
+
+// BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream.
+// The returned method will be called once per rtp packet.
+func (r *ReceiverInterceptor) BindRemoteStream(
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	stream := &streamState{clockRate: info.ClockRate}
+	for _, e := range info.RTPHeaderExtensions {
+		switch e.URI {
+		case absSendTimeURI:
+			stream.absSendTimeID = uint8(e.ID) //nolint:gosec // G115
+		case cc.TransportCCURI:
+			stream.transportCCID = uint8(e.ID) //nolint:gosec // G115
+		}
+	}
+
+	r.m.Lock()
+	r.ssrcs[info.SSRC] = struct{}{}
+	r.m.Unlock()
+
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		n, attr, err := reader.Read(b, a)
+		if err != nil {
+			return n, attr, err
+		}
+		arrival := r.clock.Now()
+
+		if attr == nil {
+			attr = make(interceptor.Attributes)
+		}
+		header, err := attr.GetRTPHeader(b[:n])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if departure, ok := r.departure(stream, header, arrival); ok {
+			if err := r.estimator.OnPacket(departure, arrival, n); err != nil {
+				r.log.Debugf("failed to update estimate: %v", err)
+			}
+			r.checkDecrease()
+		}
+
+		return n, attr, nil
+	})
+}
+
+// departure returns the departure time of a packet of stream, see ReceiverInterceptor.
+func (r *ReceiverInterceptor) departure(stream *streamState, header *rtp.Header, arrival time.Time) (time.Time, bool) {
+	if departure, ok := stream.absSendTime(header, arrival); ok {
+		return departure, true
+	}
+	sequence, transportWide := stream.transportSequenceNumber(header)
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	departure, ok := stream.departure(header, arrival, transportWide || len(r.ssrcs) == 1)
+	if !ok || !transportWide {
+		return departure, ok
+	}
+
+	return r.transport.order(sequence, departure), true
+}
+
+// checkDecrease wakes up the loop if the estimate decreased since the last REMB packet.
+func (r *ReceiverInterceptor) checkDecrease() {
+	r.m.Lock()
+	lastSent := r.lastSent
+	r.m.Unlock()
+
+	if lastSent == 0 || float64(r.estimator.GetTargetBitrate()) >= decreaseThreshold*float64(lastSent) {
+		return
+	}
+
+	select {
+	case r.decreased <- struct{}{}:
+	default:
+	}
+}
+
+// UnbindRemoteStream is called when the Stream is removed. It can be used to clean up any data related to that track.
+func (r *ReceiverInterceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	delete(r.ssrcs, info.SSRC)
+}
+
+// BindRTCPWriter lets you modify any outgoing RTCP packets. It is called once per PeerConnection. The returned method
+// will be called once per packet batch.
+func (r *ReceiverInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.RTCPWriter {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if r.isClosed() {
+		return writer
+	}
+
+	r.wg.Add(1)
+
+	go r.loop(writer)
+
+	return writer
+}
+
+func (r *ReceiverInterceptor) loop(rtcpWriter interceptor.RTCPWriter) {
+	defer r.wg.Done()
+
+	ticker := r.clock.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.Ch():
+		case <-r.decreased:
+		case <-r.close:
+			return
+		}
+
+		r.writeREMB(rtcpWriter)
+	}
+}
+
+func (r *ReceiverInterceptor) writeREMB(rtcpWriter interceptor.RTCPWriter) {
+	bitrate := r.estimator.GetTargetBitrate()
+
+	r.m.Lock()
+	ssrcs := make([]uint32, 0, len(r.ssrcs))
+	for ssrc := range r.ssrcs {
+		ssrcs = append(ssrcs, ssrc)
+	}
+	r.m.Unlock()
+
+	if len(ssrcs) == 0 {
+		return
+	}
+	slices.Sort(ssrcs)
+
+	pkts := []rtcp.Packet{&rtcp.ReceiverEstimatedMaximumBitrate{
+		SenderSSRC: r.senderSSRC,
+		Bitrate:    float32(bitrate),
+		SSRCs:      ssrcs,
+	}}
+	if _, err := rtcpWriter.Write(pkts, interceptor.Attributes{}); err != nil {
+		r.log.Warnf("failed sending: %+v", err)
+
+		return
+	}
+
+	r.m.Lock()
+	r.lastSent = bitrate
+	r.m.Unlock()
+}
+
+func (r *ReceiverInterceptor) isClosed() bool {
+	select {
+	case <-r.close:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close closes the interceptor and the associated bandwidth estimator.
+func (r *ReceiverInterceptor) Close() error {
+	defer r.wg.Wait()
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if r.isClosed() {
+		return nil
+	}
+	close(r.close)
+
+	return r.estimator.Close()
+}
@@ -0,0 +1,276 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package remb
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/cc"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type packetArrival struct {
+	departure time.Time
+	arrival   time.Time
+	size      int
+}
+
+type mockEstimator struct {
+	mu       sync.Mutex
+	bitrate  int
+	arrivals []packetArrival
+}
+
+func (m *mockEstimator) OnPacket(departure, arrival time.Time, size int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.arrivals = append(m.arrivals, packetArrival{departure: departure, arrival: arrival, size: size})
+
+	return nil
+}
+
+func (m *mockEstimator) GetTargetBitrate() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.bitrate
+}
+
+func (m *mockEstimator) setTargetBitrate(bitrate int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.bitrate = bitrate
+}
+
+func (m *mockEstimator) packetArrivals() []packetArrival {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]packetArrival{}, m.arrivals...)
+}
+
+func (m *mockEstimator) OnTargetBitrateChange(func(int)) {}
+
+func (m *mockEstimator) GetStats() map[string]any {
+	return map[string]any{}
+}
+
+func (m *mockEstimator) Close() error {
+	return nil
+}
+
+func newTestInterceptor(t *testing.T, estimator *mockEstimator, opts ...ReceiverOption) *ReceiverInterceptor {
+	t.Helper()
+
+	factory, err := NewReceiverInterceptor(func() (BandwidthEstimator, error) {
+		return estimator, nil
+	}, opts...)
+	require.NoError(t, err)
+
+	var created BandwidthEstimator
+	factory.OnNewPeerConnection(func(_ string, e BandwidthEstimator) {
+		created = e
+	})
+
+	i, err := factory.NewInterceptor("")
+	require.NoError(t, err)
+	assert.Equal(t, BandwidthEstimator(estimator), created)
+
+	return i.(*ReceiverInterceptor) //nolint:forcetypeassert
+}
+
+func TestReceiverInterceptor_SendsREMB(t *testing.T) {
+	estimator := &mockEstimator{bitrate: 500_000}
+	i := newTestInterceptor(t, estimator, WithInterval(10*time.Millisecond), WithSenderSSRC(1))
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:                1234,
+		RTPHeaderExtensions: []interceptor.RTPHeaderExtension{{URI: absSendTimeURI, ID: 3}},
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	sendTime := time.Now().Add(-50 * time.Millisecond)
+	ext, err := rtp.NewAbsSendTimeExtension(sendTime).Marshal()
+	require.NoError(t, err)
+	packet := &rtp.Packet{Header: rtp.Header{Version: 2, SSRC: 1234}, Payload: make([]byte, 100)}
+	require.NoError(t, packet.SetExtension(3, ext))
+	stream.ReceiveRTP(packet)
+
+	read := <-stream.ReadRTP()
+	require.NoError(t, read.Err)
+	arrivals := estimator.packetArrivals()
+	require.Len(t, arrivals, 1)
+	assert.WithinDuration(t, sendTime, arrivals[0].departure, time.Millisecond)
+	assert.Equal(t, packet.MarshalSize(), arrivals[0].size)
+
+	select {
+	case pkts := <-stream.WrittenRTCP():
+		assert.Equal(t, []rtcp.Packet{&rtcp.ReceiverEstimatedMaximumBitrate{
+			SenderSSRC: 1,
+			Bitrate:    500_000,
+			SSRCs:      []uint32{1234},
+		}}, pkts)
+	case <-time.After(time.Second):
+		assert.Fail(t, "no REMB sent")
+	}
+}
+
+func TestReceiverInterceptor_SendsDecreaseRightAway(t *testing.T) {
+	estimator := &mockEstimator{bitrate: 500_000}
+	i := newTestInterceptor(t, estimator, WithInterval(time.Hour))
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 1234, ClockRate: 90000}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	i.m.Lock()
+	i.lastSent = 500_000
+	i.m.Unlock()
+
+	// A small decrease waits for the next interval.
+	estimator.setTargetBitrate(490_000)
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{Version: 2, SSRC: 1234, Timestamp: 0}})
+	<-stream.ReadRTP()
+
+	select {
+	case pkts := <-stream.WrittenRTCP():
+		assert.Fail(t, "unexpected REMB", pkts)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	estimator.setTargetBitrate(400_000)
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{Version: 2, SSRC: 1234, Timestamp: 9000}})
+	<-stream.ReadRTP()
+
+	select {
+	case pkts := <-stream.WrittenRTCP():
+		remb, ok := pkts[0].(*rtcp.ReceiverEstimatedMaximumBitrate)
+		require.True(t, ok)
+		assert.Equal(t, float32(400_000), remb.Bitrate)
+	case <-time.After(time.Second):
+		assert.Fail(t, "no REMB sent")
+	}
+
+	// Without abs-send-time, the departure is derived from the RTP timestamp.
+	arrivals := estimator.packetArrivals()
+	require.Len(t, arrivals, 2)
+	assert.Equal(t, 100*time.Millisecond, arrivals[1].departure.Sub(arrivals[0].departure))
+}
+
+func TestStreamState_Departure(t *testing.T) {
+	stream := &streamState{clockRate: 90000}
+	start := time.Now()
+	timestamp := uint32(4294967000)
+
+	departure, ok := stream.departure(&rtp.Header{Timestamp: timestamp}, start, true)
+	assert.True(t, ok)
+	assert.Equal(t, start, departure)
+
+	// Timestamps wrap around and may go backwards with B-frames.
+	departure, _ = stream.departure(&rtp.Header{Timestamp: timestamp + 1800}, start, true)
+	assert.Equal(t, start.Add(20*time.Millisecond), departure)
+	departure, _ = stream.departure(&rtp.Header{Timestamp: timestamp + 900}, start, true)
+	assert.Equal(t, start.Add(10*time.Millisecond), departure)
+
+	_, ok = (&streamState{}).departure(&rtp.Header{}, start, true)
+	assert.False(t, ok)
+
+	// The derived departure starts over once it can be used again.
+	_, ok = stream.departure(&rtp.Header{Timestamp: timestamp + 2700}, start, false)
+	assert.False(t, ok)
+	later := start.Add(time.Second)
+	departure, ok = stream.departure(&rtp.Header{Timestamp: timestamp + 3600}, later, true)
+	assert.True(t, ok)
+	assert.Equal(t, later, departure)
+}
+
+func TestReceiverInterceptor_TimestampsOfSingleStream(t *testing.T) {
+	estimator := &mockEstimator{}
+	i := newTestInterceptor(t, estimator, WithInterval(time.Hour))
+
+	streamA := test.NewMockStream(&interceptor.StreamInfo{SSRC: 1, ClockRate: 90000}, i)
+	streamB := test.NewMockStream(&interceptor.StreamInfo{SSRC: 2, ClockRate: 48000}, i)
+	defer func() {
+		assert.NoError(t, streamA.Close())
+		assert.NoError(t, streamB.Close())
+	}()
+
+	// The RTP timestamps of two streams are on unrelated clocks.
+	streamA.ReceiveRTP(&rtp.Packet{Header: rtp.Header{Version: 2, SSRC: 1, Timestamp: 0}})
+	<-streamA.ReadRTP()
+	streamB.ReceiveRTP(&rtp.Packet{Header: rtp.Header{Version: 2, SSRC: 2, Timestamp: 0}})
+	<-streamB.ReadRTP()
+	assert.Empty(t, estimator.packetArrivals())
+
+	i.UnbindRemoteStream(&interceptor.StreamInfo{SSRC: 2})
+	streamA.ReceiveRTP(&rtp.Packet{Header: rtp.Header{Version: 2, SSRC: 1, Timestamp: 9000}})
+	<-streamA.ReadRTP()
+	assert.Len(t, estimator.packetArrivals(), 1)
+}
+
+func TestTransportOrder(t *testing.T) {
+	var order transportOrder
+	start := time.Now()
+
+	assert.Equal(t, start, order.order(65535, start))
+	// Departures follow the transport wide sequence numbers across wrap arounds.
+	assert.Equal(t, start, order.order(0, start.Add(-5*time.Millisecond)))
+	assert.Equal(t, start.Add(10*time.Millisecond), order.order(1, start.Add(10*time.Millisecond)))
+	// A reordered packet departed before the packets numbered after it.
+	assert.Equal(t, start.Add(10*time.Millisecond), order.order(0, start.Add(20*time.Millisecond)))
+	assert.Equal(t, start.Add(5*time.Millisecond), order.order(0, start.Add(5*time.Millisecond)))
+}
+
+func TestReceiverInterceptor_TransportWideStreams(t *testing.T) {
+	estimator := &mockEstimator{}
+	i := newTestInterceptor(t, estimator, WithInterval(time.Hour))
+
+	extensions := []interceptor.RTPHeaderExtension{{URI: cc.TransportCCURI, ID: 1}}
+	streamA := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC: 1, ClockRate: 90000, RTPHeaderExtensions: extensions,
+	}, i)
+	streamB := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC: 2, ClockRate: 48000, RTPHeaderExtensions: extensions,
+	}, i)
+	defer func() {
+		assert.NoError(t, streamA.Close())
+		assert.NoError(t, streamB.Close())
+	}()
+
+	receive := func(stream *test.MockStream, ssrc uint32, timestamp uint32, sequence uint16) {
+		t.Helper()
+
+		header := rtp.Header{Version: 2, SSRC: ssrc, Timestamp: timestamp}
+		ext, err := (&rtp.TransportCCExtension{TransportSequence: sequence}).Marshal()
+		require.NoError(t, err)
+		require.NoError(t, header.SetExtension(1, ext))
+		stream.ReceiveRTP(&rtp.Packet{Header: header})
+		<-stream.ReadRTP()
+	}
+
+	// The packets of both streams are used while they are bound together, in the
+	// send order of their transport wide sequence numbers.
+	receive(streamA, 1, 0, 10)
+	receive(streamB, 2, 0, 11)
+	receive(streamA, 1, 9000, 12)
+	receive(streamB, 2, 480, 13)
+
+	arrivals := estimator.packetArrivals()
+	require.Len(t, arrivals, 4)
+	for n := 1; n < len(arrivals); n++ {
+		assert.False(t, arrivals[n].departure.Before(arrivals[n-1].departure))
+	}
+}
@@ -22,7 +22,10 @@ func (r *ReceiverInterceptorFactory) NewInterceptor(_ string) (interceptor.Inter
 	receiverInterceptor := &ReceiverInterceptor{
 		interval: 1 * time.Second,
 		now:      time.Now,
-		close:    make(chan struct{}),
+		newTicker: func(d time.Duration) Ticker {
+			return &timeTicker{time.NewTicker(d)}
+		},
+		close: make(chan struct{}),
 	}
 
 	for _, opt := range r.opts {
@@ -51,6 +54,7 @@ type ReceiverInterceptor struct {
 	interceptor.NoOp
 	interval      time.Duration
 	now           func() time.Time
+	newTicker     TickerFactory
 	streams       sync.Map
 	log           logging.LeveledLogger
 	loggerFactory logging.LoggerFactory
@@ -101,11 +105,11 @@ func (r *ReceiverInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) inte
 func (r *ReceiverInterceptor) loop(rtcpWriter interceptor.RTCPWriter) {
 	defer r.wg.Done()
 
-	ticker := time.NewTicker(r.interval)
+	ticker := r.newTicker(r.interval)
 	defer ticker.Stop()
 	for {
 		select {
-		case <-ticker.C:
+		case <-ticker.Ch():
 			now := r.now()
 			r.streams.Range(func(_, value any) bool {
 				if stream, ok := value.(*receiverStream); !ok {
@@ -6,6 +6,7 @@ package report
 import (
 	"time"
 
+	"github.com/pion/interceptor"
 	"github.com/pion/logging"
 )
 
@@ -47,3 +48,15 @@ func ReceiverNow(f func() time.Time) ReceiverOption {
 		return nil
 	}
 }
+
+// ReceiverClock sets the clock the interceptor gets the time and its ticker from.
+func ReceiverClock(clock interceptor.Clock) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		r.now = clock.Now
+		r.newTicker = func(d time.Duration) Ticker {
+			return clock.NewTicker(d)
+		}
+
+		return nil
+	}
+}
@@ -4,6 +4,7 @@
 package report
 
 import (
+	"math"
 	"math/rand"
 	"sync"
 	"time"
@@ -37,6 +38,26 @@ type receiverStream struct {
 	lastSenderReport     uint32
 	lastSenderReportTime time.Time
 	totalLost            uint32
+
+	// receive state since the last extended report, only kept for the streams of
+	// the XRReceiverInterceptor
+	extended     bool
+	lastXRSeqnum uint16
+	duplicates   uint32
+	jitterCount  int
+	jitterMin    float64
+	jitterMax    float64
+	jitterSum    float64
+	jitterSumSq  float64
+}
+
+// newExtendedReceiverStream returns a receiverStream which also keeps the
+// statistics of the extended reports.
+func newExtendedReceiverStream(ssrc uint32, clockRate uint32) *receiverStream {
+	stream := newReceiverStream(ssrc, clockRate)
+	stream.extended = true
+
+	return stream
 }
 
 func newReceiverStream(ssrc uint32, clockRate uint32) *receiverStream {
@@ -61,12 +82,18 @@ func (stream *receiverStream) processRTP(now time.Time, pktHeader *rtp.Header) {
 		stream.setReceived(pktHeader.SequenceNumber)
 		stream.lastSeqnum = pktHeader.SequenceNumber
 		stream.lastReportSeqnum = pktHeader.SequenceNumber - 1
+		stream.lastXRSeqnum = pktHeader.SequenceNumber - 1
 		stream.lastRTPTimeRTP = pktHeader.Timestamp
 		stream.lastRTPTimeTime = now
 	} else { // following frames
+		diff := pktHeader.SequenceNumber - stream.lastSeqnum
+		// only packets which are not newer than the last one can be duplicates,
+		// the history of newer ones still holds the packets of the previous cycle
+		if stream.extended && (diff == 0 || diff >= (1<<15)) && stream.getReceived(pktHeader.SequenceNumber) {
+			stream.duplicates++
+		}
 		stream.setReceived(pktHeader.SequenceNumber)
 
-		diff := pktHeader.SequenceNumber - stream.lastSeqnum
 		if diff > 0 && diff < (1<<15) {
 			// wrap around
 			if pktHeader.SequenceNumber < stream.lastSeqnum {
@@ -91,6 +118,19 @@ func (stream *receiverStream) processRTP(now time.Time, pktHeader *rtp.Header) {
 		stream.jitter += (D - stream.jitter) / 16
 		stream.lastRTPTimeRTP = pktHeader.Timestamp
 		stream.lastRTPTimeTime = now
+
+		if !stream.extended {
+			return
+		}
+		if stream.jitterCount == 0 || stream.jitter < stream.jitterMin {
+			stream.jitterMin = stream.jitter
+		}
+		if stream.jitterCount == 0 || stream.jitter > stream.jitterMax {
+			stream.jitterMax = stream.jitter
+		}
+		stream.jitterCount++
+		stream.jitterSum += stream.jitter
+		stream.jitterSumSq += stream.jitter * stream.jitter
 	}
 }
 
@@ -172,3 +212,98 @@ func (stream *receiverStream) generateReport(now time.Time) *rtcp.ReceiverReport
 
 	return receiverReport
 }
+
+// generateExtendedReportBlocks returns a Statistics Summary and a Loss RLE report
+// block (RFC 3611, sections 4.6 and 4.1) for the packets received since the last
+// call, if enabled. It returns no blocks if no packets were received since then.
+func (stream *receiverStream) generateExtendedReportBlocks(statisticsSummary, lossRLE bool) []rtcp.ReportBlock {
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	if stream.lastSeqnum == stream.lastXRSeqnum {
+		return nil
+	}
+	endSeq := stream.lastSeqnum + 1
+	beginSeq := stream.lastXRSeqnum + 1
+	if historySize := stream.size * packetsPerHistoryEntry; endSeq-beginSeq > historySize {
+		beginSeq = endSeq - historySize
+	}
+
+	var blocks []rtcp.ReportBlock
+	if statisticsSummary {
+		blocks = append(blocks, stream.statisticsSummary(beginSeq, endSeq))
+	}
+	if lossRLE {
+		blocks = append(blocks, stream.lossRLE(beginSeq, endSeq))
+	}
+
+	stream.lastXRSeqnum = stream.lastSeqnum
+	stream.duplicates = 0
+	stream.jitterCount = 0
+	stream.jitterSum = 0
+	stream.jitterSumSq = 0
+
+	return blocks
+}
+
+// statisticsSummary must be called with the lock held.
+func (stream *receiverStream) statisticsSummary(beginSeq, endSeq uint16) *rtcp.StatisticsSummaryReportBlock {
+	lost := uint32(0)
+	for i := beginSeq; i != endSeq; i++ {
+		if !stream.getReceived(i) {
+			lost++
+		}
+	}
+	block := &rtcp.StatisticsSummaryReportBlock{
+		LossReports:      true,
+		DuplicateReports: true,
+		JitterReports:    stream.jitterCount > 0,
+		TTLorHopLimit:    rtcp.ToHMissing,
+		SSRC:             stream.ssrc,
+		BeginSeq:         beginSeq,
+		EndSeq:           endSeq,
+		LostPackets:      lost,
+		DupPackets:       stream.duplicates,
+	}
+	if stream.jitterCount > 0 {
+		mean := stream.jitterSum / float64(stream.jitterCount)
+		variance := max(stream.jitterSumSq/float64(stream.jitterCount)-mean*mean, 0)
+		block.MinJitter = uint32(stream.jitterMin)
+		block.MaxJitter = uint32(stream.jitterMax)
+		block.MeanJitter = uint32(mean)
+		block.DevJitter = uint32(math.Sqrt(variance))
+	}
+
+	return block
+}
+
+// lossRLE must be called with the lock held. It encodes the packets in bit
+// vector chunks, in which a set bit marks a received packet.
+func (stream *receiverStream) lossRLE(beginSeq, endSeq uint16) *rtcp.LossRLEReportBlock {
+	var chunks []rtcp.Chunk
+	chunk, bits := rtcp.Chunk(0x8000), 0
+	for i := beginSeq; i != endSeq; i++ {
+		if stream.getReceived(i) {
+			chunk |= 1 << (14 - bits)
+		}
+		bits++
+		if bits == 15 {
+			chunks = append(chunks, chunk)
+			chunk, bits = 0x8000, 0
+		}
+	}
+	if bits > 0 {
+		chunks = append(chunks, chunk)
+	}
+	// pad the block to a multiple of 32 bits with a terminating null chunk
+	if len(chunks)%2 == 1 {
+		chunks = append(chunks, 0)
+	}
+
+	return &rtcp.LossRLEReportBlock{
+		SSRC:     stream.ssrc,
+		BeginSeq: beginSeq,
+		EndSeq:   endSeq,
+		Chunks:   chunks,
+	}
+}
@@ -5,7 +5,9 @@ package report
 
 import (
 	"testing"
+	"time"
 
+	"github.com/pion/rtp"
 	"github.com/stretchr/testify/require"
 )
 
@@ -28,4 +30,24 @@ func TestReceiverStream(t *testing.T) {
 			require.False(t, stream.getReceived(seq), "packet with SN %v should no longer be received", seq)
 		}
 	})
+
+	t.Run("keeps extended report statistics only if extended", func(t *testing.T) {
+		now := time.Unix(1000, 0)
+		for _, extended := range []bool{false, true} {
+			stream := newReceiverStream(12345, 90000)
+			if extended {
+				stream = newExtendedReceiverStream(12345, 90000)
+			}
+			for _, seq := range []uint16{1, 2, 2} {
+				stream.processRTP(now, &rtp.Header{SequenceNumber: seq})
+			}
+
+			expected := 0
+			if extended {
+				expected = 2
+			}
+			require.Equal(t, expected, stream.jitterCount)
+			require.Equal(t, uint32(expected/2), stream.duplicates)
+		}
+	})
 }
@@ -1,5 +1,6 @@
 // SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
 // SPDX-License-Identifier: MIT
 
-// Package report provides interceptors to implement sending sender and receiver reports.
+// Package report provides interceptors to implement sending sender and receiver reports,
+// and RTCP extended reports as defined in RFC 3611.
 package report
@@ -6,6 +6,7 @@ package report
 import (
 	"time"
 
+	"github.com/pion/interceptor"
 	"github.com/pion/logging"
 )
 
@@ -57,6 +58,18 @@ func SenderTicker(f TickerFactory) SenderOption {
 	}
 }
 
+// SenderClock sets the clock the interceptor gets the time and its ticker from.
+func SenderClock(clock interceptor.Clock) SenderOption {
+	return func(r *SenderInterceptor) error {
+		r.now = clock.Now
+		r.newTicker = func(d time.Duration) Ticker {
+			return clock.NewTicker(d)
+		}
+
+		return nil
+	}
+}
+
 // SenderUseLatestPacket sets the interceptor to always use the latest packet, even
 // if it appears to be out-of-order.
 func SenderUseLatestPacket() SenderOption {
@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/ntp"
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+)
+
+// XRReceiverInterceptorFactory is a interceptor.Factory for a XRReceiverInterceptor.
+type XRReceiverInterceptorFactory struct {
+	opts []XRReceiverOption
+}
+
+// NewInterceptor constructs a new XRReceiverInterceptor.
+func (r *XRReceiverInterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	xrReceiverInterceptor := &XRReceiverInterceptor{
+		interval: 1 * time.Second,
+		now:      time.Now,
+		newTicker: func(d time.Duration) Ticker {
+			return &timeTicker{time.NewTicker(d)}
+		},
+		senderSSRC: rand.Uint32(), // #nosec
+		close:      make(chan struct{}),
+	}
+
+	for _, opt := range r.opts {
+		if err := opt(xrReceiverInterceptor); err != nil {
+			return nil, err
+		}
+	}
+
+	if xrReceiverInterceptor.loggerFactory == nil {
+		xrReceiverInterceptor.loggerFactory = logging.NewDefaultLoggerFactory()
+	}
+	xrReceiverInterceptor.log = xrReceiverInterceptor.loggerFactory.NewLogger("xr_receiver_interceptor")
+
+	return xrReceiverInterceptor, nil
+}
+
+// NewXRReceiverInterceptor returns a new XRReceiverInterceptorFactory.
+func NewXRReceiverInterceptor(opts ...XRReceiverOption) (*XRReceiverInterceptorFactory, error) {
+	return &XRReceiverInterceptorFactory{opts}, nil
+}
+
+// XRReceiverInterceptor generates RTCP extended reports (RFC 3611) as a media
+// receiver. Each report carries a Receiver Reference Time report block, which
+// the XRSenderInterceptor of the remote peer answers with a DLRR report block,
+// so that receive-only endpoints can measure the round trip time as well.
+// Statistics Summary and Loss RLE report blocks for the remote streams can be
+// added with the XRReceiverStatisticsSummary and XRReceiverLossRLE options.
+type XRReceiverInterceptor struct {
+	interceptor.NoOp
+	interval          time.Duration
+	now               func() time.Time
+	newTicker         TickerFactory
+	senderSSRC        uint32
+	statisticsSummary bool
+	lossRLE           bool
+	streams           sync.Map
+	log               logging.LeveledLogger
+	loggerFactory     logging.LoggerFactory
+	m                 sync.Mutex
+	wg                sync.WaitGroup
+	close             chan struct{}
+}
+
+func (r *XRReceiverInterceptor) isClosed() bool {
+	select {
+	case <-r.close:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close closes the interceptor.
+func (r *XRReceiverInterceptor) Close() error {
+	defer r.wg.Wait()
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if !r.isClosed() {
+		close(r.close)
+	}
+
+	return nil
+}
+
+// BindRTCPWriter lets you modify any outgoing RTCP packets. It is called once per PeerConnection. The returned method
+// will be called once per packet batch.
+func (r *XRReceiverInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.RTCPWriter {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if r.isClosed() {
+		return writer
+	}
+
+	r.wg.Add(1)
+
+	go r.loop(writer)
+
+	return writer
+}
+
+func (r *XRReceiverInterceptor) loop(rtcpWriter interceptor.RTCPWriter) {
+	defer r.wg.Done()
+
+	ticker := r.newTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.Ch():
+			if _, err := rtcpWriter.Write(
+				[]rtcp.Packet{r.generateReport(r.now())}, interceptor.Attributes{},
+			); err != nil {
+				r.log.Warnf("failed sending: %+v", err)
+			}
+
+		case <-r.close:
+			return
+		}
+	}
+}
+
+func (r *XRReceiverInterceptor) generateReport(now time.Time) *rtcp.ExtendedReport {
+	report := &rtcp.ExtendedReport{
+		SenderSSRC: r.senderSSRC,
+		Reports: []rtcp.ReportBlock{
+			&rtcp.ReceiverReferenceTimeReportBlock{NTPTimestamp: ntp.ToNTP(now)},
+		},
+	}
+	if !r.statisticsSummary && !r.lossRLE {
+		return report
+	}
+	r.streams.Range(func(_, value any) bool {
+		if stream, ok := value.(*receiverStream); !ok {
+			r.log.Warnf("failed to cast XRReceiverInterceptor stream")
+		} else {
+			blocks := stream.generateExtendedReportBlocks(r.statisticsSummary, r.lossRLE)
+			report.Reports = append(report.Reports, blocks...)
+		}
+
+		return true
+	})
+
+	return report
+}
+
+// BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream.
+// The returned method will be called once per rtp packet.
+func (r *XRReceiverInterceptor) BindRemoteStream(
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	stream := newExtendedReceiverStream(info.SSRC, info.ClockRate)
+	r.streams.Store(info.SSRC, stream)
+
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		i, attr, err := reader.Read(b, a)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if attr == nil {
+			attr = make(interceptor.Attributes)
+		}
+		header, err := attr.GetRTPHeader(b[:i])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		stream.processRTP(r.now(), header)
+
+		return i, attr, nil
+	})
+}
+
+// UnbindRemoteStream is called when the Stream is removed. It can be used to clean up any data related to that track.
+func (r *XRReceiverInterceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
+	r.streams.Delete(info.SSRC)
+}
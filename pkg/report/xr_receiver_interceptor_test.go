@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/ntp"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockClock is an interceptor.Clock with the time of a test.MockTime.
+type mockClock struct {
+	*test.MockTime
+}
+
+func (c mockClock) NewTicker(d time.Duration) interceptor.Ticker {
+	return interceptor.SystemClock().NewTicker(d)
+}
+
+func TestXRReceiverInterceptor(t *testing.T) {
+	rtpTime := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+
+	t.Run("sends receiver reference time", func(t *testing.T) {
+		mt := &test.MockTime{}
+		mt.SetNow(rtpTime)
+		f, err := NewXRReceiverInterceptor(
+			XRReceiverInterval(time.Millisecond*50),
+			XRReceiverClock(mockClock{mt}),
+		)
+		assert.NoError(t, err)
+
+		i, err := f.NewInterceptor("")
+		assert.NoError(t, err)
+
+		stream := test.NewMockStream(&interceptor.StreamInfo{
+			SSRC:      123456,
+			ClockRate: 90000,
+		}, i)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+
+		pkts := <-stream.WrittenRTCP()
+		assert.Equal(t, 1, len(pkts))
+		xr, ok := pkts[0].(*rtcp.ExtendedReport)
+		assert.True(t, ok)
+		assert.Equal(t, []rtcp.ReportBlock{
+			&rtcp.ReceiverReferenceTimeReportBlock{NTPTimestamp: ntp.ToNTP(rtpTime)},
+		}, xr.Reports)
+	})
+
+	t.Run("statistics summary and loss RLE", func(t *testing.T) {
+		mt := &test.MockTime{}
+		f, err := NewXRReceiverInterceptor(
+			XRReceiverInterval(time.Hour),
+			XRReceiverClock(mockClock{mt}),
+			XRReceiverStatisticsSummary(),
+			XRReceiverLossRLE(),
+		)
+		assert.NoError(t, err)
+
+		i, err := f.NewInterceptor("")
+		assert.NoError(t, err)
+
+		stream := test.NewMockStream(&interceptor.StreamInfo{
+			SSRC:      123456,
+			ClockRate: 90000,
+		}, i)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+
+		// packets 3 and 4 are lost, packet 5 arrives twice
+		for _, seqNum := range []uint16{0, 1, 2, 5, 6, 7, 8, 9, 5} {
+			mt.SetNow(rtpTime.Add(time.Duration(seqNum) * 20 * time.Millisecond))
+			stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{
+				SequenceNumber: seqNum,
+				Timestamp:      uint32(seqNum) * 1800,
+				SSRC:           123456,
+			}})
+			<-stream.ReadRTP()
+		}
+
+		xrReceiver, ok := i.(*XRReceiverInterceptor)
+		assert.True(t, ok)
+		report := xrReceiver.generateReport(rtpTime.Add(time.Second))
+		assert.Equal(t, []rtcp.ReportBlock{
+			&rtcp.ReceiverReferenceTimeReportBlock{NTPTimestamp: ntp.ToNTP(rtpTime.Add(time.Second))},
+			&rtcp.StatisticsSummaryReportBlock{
+				LossReports:      true,
+				DuplicateReports: true,
+				JitterReports:    true,
+				TTLorHopLimit:    rtcp.ToHMissing,
+				SSRC:             123456,
+				BeginSeq:         0,
+				EndSeq:           10,
+				LostPackets:      2,
+				DupPackets:       1,
+			},
+			&rtcp.LossRLEReportBlock{
+				SSRC:     123456,
+				BeginSeq: 0,
+				EndSeq:   10,
+				Chunks:   []rtcp.Chunk{0b1111_0011_1110_0000, 0},
+			},
+		}, report.Reports)
+
+		_, err = report.Marshal()
+		assert.NoError(t, err)
+
+		// no packets since the last report
+		report = xrReceiver.generateReport(rtpTime.Add(2 * time.Second))
+		assert.Equal(t, 1, len(report.Reports))
+	})
+}
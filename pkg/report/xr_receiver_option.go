@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+)
+
+// XRReceiverOption can be used to configure XRReceiverInterceptor.
+type XRReceiverOption func(r *XRReceiverInterceptor) error
+
+// WithXRReceiverLoggerFactory sets a logger factory for the interceptor.
+func WithXRReceiverLoggerFactory(loggerFactory logging.LoggerFactory) XRReceiverOption {
+	return func(r *XRReceiverInterceptor) error {
+		r.loggerFactory = loggerFactory
+
+		return nil
+	}
+}
+
+// XRReceiverInterval sets send interval for the interceptor.
+func XRReceiverInterval(interval time.Duration) XRReceiverOption {
+	return func(r *XRReceiverInterceptor) error {
+		r.interval = interval
+
+		return nil
+	}
+}
+
+// XRReceiverClock sets the clock the interceptor gets the time and its ticker from.
+func XRReceiverClock(clock interceptor.Clock) XRReceiverOption {
+	return func(r *XRReceiverInterceptor) error {
+		r.now = clock.Now
+		r.newTicker = func(d time.Duration) Ticker {
+			return clock.NewTicker(d)
+		}
+
+		return nil
+	}
+}
+
+// XRReceiverStatisticsSummary adds a Statistics Summary report block for each
+// remote stream to the extended reports.
+func XRReceiverStatisticsSummary() XRReceiverOption {
+	return func(r *XRReceiverInterceptor) error {
+		r.statisticsSummary = true
+
+		return nil
+	}
+}
+
+// XRReceiverLossRLE adds a Loss RLE report block for each remote stream to the
+// extended reports.
+func XRReceiverLossRLE() XRReceiverOption {
+	return func(r *XRReceiverInterceptor) error {
+		r.lossRLE = true
+
+		return nil
+	}
+}
@@ -0,0 +1,240 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+)
+
+// XRSenderInterceptorFactory is a interceptor.Factory for a XRSenderInterceptor.
+type XRSenderInterceptorFactory struct {
+	opts []XRSenderOption
+}
+
+// NewInterceptor constructs a new XRSenderInterceptor.
+func (s *XRSenderInterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	xrSenderInterceptor := &XRSenderInterceptor{
+		interval: 1 * time.Second,
+		now:      time.Now,
+		newTicker: func(d time.Duration) Ticker {
+			return &timeTicker{time.NewTicker(d)}
+		},
+		senderSSRC:     rand.Uint32(), // #nosec
+		localSSRCs:     map[uint32]struct{}{},
+		referenceTimes: map[uint32]receiverReferenceTime{},
+		close:          make(chan struct{}),
+	}
+
+	for _, opt := range s.opts {
+		if err := opt(xrSenderInterceptor); err != nil {
+			return nil, err
+		}
+	}
+
+	if xrSenderInterceptor.loggerFactory == nil {
+		xrSenderInterceptor.loggerFactory = logging.NewDefaultLoggerFactory()
+	}
+	xrSenderInterceptor.log = xrSenderInterceptor.loggerFactory.NewLogger("xr_sender_interceptor")
+
+	return xrSenderInterceptor, nil
+}
+
+// NewXRSenderInterceptor returns a new XRSenderInterceptorFactory.
+func NewXRSenderInterceptor(opts ...XRSenderOption) (*XRSenderInterceptorFactory, error) {
+	return &XRSenderInterceptorFactory{opts}, nil
+}
+
+// XRSenderInterceptor answers the Receiver Reference Time report blocks of RTCP
+// extended reports (RFC 3611) with DLRR report blocks as a media sender. It
+// tracks the latest reference time received from each receiver, and sends it
+// back with the delay since its arrival in the next extended report, in a
+// sub-block identified by the SSRC of the receiver.
+type XRSenderInterceptor struct {
+	interceptor.NoOp
+	interval      time.Duration
+	now           func() time.Time
+	newTicker     TickerFactory
+	senderSSRC    uint32
+	log           logging.LeveledLogger
+	loggerFactory logging.LoggerFactory
+	m             sync.Mutex
+	wg            sync.WaitGroup
+	close         chan struct{}
+
+	// locked by m
+	localSSRCs     map[uint32]struct{}
+	referenceTimes map[uint32]receiverReferenceTime
+}
+
+// receiverReferenceTime is the latest unanswered Receiver Reference Time report
+// block of a receiver.
+type receiverReferenceTime struct {
+	lastRR  uint32
+	arrival time.Time
+}
+
+func (s *XRSenderInterceptor) isClosed() bool {
+	select {
+	case <-s.close:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close closes the interceptor.
+func (s *XRSenderInterceptor) Close() error {
+	defer s.wg.Wait()
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if !s.isClosed() {
+		close(s.close)
+	}
+
+	return nil
+}
+
+// BindRTCPWriter lets you modify any outgoing RTCP packets. It is called once per PeerConnection. The returned method
+// will be called once per packet batch.
+func (s *XRSenderInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.RTCPWriter {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.isClosed() {
+		return writer
+	}
+
+	s.wg.Add(1)
+
+	go s.loop(writer)
+
+	return writer
+}
+
+func (s *XRSenderInterceptor) loop(rtcpWriter interceptor.RTCPWriter) {
+	defer s.wg.Done()
+
+	ticker := s.newTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.Ch():
+			report := s.generateReport(s.now())
+			if report == nil {
+				continue
+			}
+			if _, err := rtcpWriter.Write([]rtcp.Packet{report}, interceptor.Attributes{}); err != nil {
+				s.log.Warnf("failed sending: %+v", err)
+			}
+
+		case <-s.close:
+			return
+		}
+	}
+}
+
+// generateReport returns an extended report answering the latest Receiver
+// Reference Time report block of each receiver, or nil if they were answered
+// already.
+func (s *XRSenderInterceptor) generateReport(now time.Time) *rtcp.ExtendedReport {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if len(s.referenceTimes) == 0 || len(s.localSSRCs) == 0 {
+		return nil
+	}
+
+	block := &rtcp.DLRRReportBlock{}
+	for ssrc, reference := range s.referenceTimes {
+		block.Reports = append(block.Reports, rtcp.DLRRReport{
+			SSRC:   ssrc,
+			LastRR: reference.lastRR,
+			DLRR:   uint32(now.Sub(reference.arrival).Seconds() * 65536), //nolint:gosec // G115
+		})
+	}
+	clear(s.referenceTimes)
+	slices.SortFunc(block.Reports, func(a, b rtcp.DLRRReport) int {
+		return cmp.Compare(a.SSRC, b.SSRC)
+	})
+
+	return &rtcp.ExtendedReport{
+		SenderSSRC: s.senderSSRC,
+		Reports:    []rtcp.ReportBlock{block},
+	}
+}
+
+// BindLocalStream lets you modify any outgoing RTP packets. It is called once for per LocalStream. The returned method
+// will be called once per rtp packet.
+func (s *XRSenderInterceptor) BindLocalStream(
+	info *interceptor.StreamInfo, writer interceptor.RTPWriter,
+) interceptor.RTPWriter {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.localSSRCs[info.SSRC] = struct{}{}
+
+	return writer
+}
+
+// UnbindLocalStream is called when the Stream is removed. It can be used to clean up any data related to that track.
+func (s *XRSenderInterceptor) UnbindLocalStream(info *interceptor.StreamInfo) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	delete(s.localSSRCs, info.SSRC)
+}
+
+// BindRTCPReader lets you modify any incoming RTCP packets. It is called once per sender/receiver, however this might
+// change in the future. The returned method will be called once per packet batch.
+func (s *XRSenderInterceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.RTCPReader {
+	return interceptor.RTCPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		i, attr, err := reader.Read(b, a)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if attr == nil {
+			attr = make(interceptor.Attributes)
+		}
+		pkts, err := attr.GetRTCPPackets(b[:i])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		for _, pkt := range pkts {
+			xr, ok := pkt.(*rtcp.ExtendedReport)
+			if !ok {
+				continue
+			}
+			for _, block := range xr.Reports {
+				if rrtr, ok := block.(*rtcp.ReceiverReferenceTimeReportBlock); ok {
+					s.processReceiverReferenceTime(s.now(), xr.SenderSSRC, rrtr)
+				}
+			}
+		}
+
+		return i, attr, nil
+	})
+}
+
+func (s *XRSenderInterceptor) processReceiverReferenceTime(
+	now time.Time, ssrc uint32, rrtr *rtcp.ReceiverReferenceTimeReportBlock,
+) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.referenceTimes[ssrc] = receiverReferenceTime{
+		lastRR:  uint32(rrtr.NTPTimestamp >> 16), //nolint:gosec // G115
+		arrival: now,
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/ntp"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXRSenderInterceptor(t *testing.T) {
+	rtpTime := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+
+	t.Run("answers receiver reference time with DLRR", func(t *testing.T) {
+		mt := &test.MockTime{}
+		mt.SetNow(rtpTime)
+		f, err := NewXRSenderInterceptor(
+			XRSenderInterval(time.Hour),
+			XRSenderClock(mockClock{mt}),
+		)
+		assert.NoError(t, err)
+
+		i, err := f.NewInterceptor("")
+		assert.NoError(t, err)
+		xrSender, ok := i.(*XRSenderInterceptor)
+		assert.True(t, ok)
+
+		stream := test.NewMockStream(&interceptor.StreamInfo{
+			SSRC:      2,
+			ClockRate: 90000,
+		}, i)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+		i.BindLocalStream(&interceptor.StreamInfo{SSRC: 1, ClockRate: 90000}, nil)
+
+		assert.Nil(t, xrSender.generateReport(rtpTime))
+
+		// the latest reference time of each receiver is answered
+		receive := func(ssrc uint32, reference time.Time) {
+			stream.ReceiveRTCP([]rtcp.Packet{&rtcp.ExtendedReport{
+				SenderSSRC: ssrc,
+				Reports: []rtcp.ReportBlock{
+					&rtcp.ReceiverReferenceTimeReportBlock{NTPTimestamp: ntp.ToNTP(reference)},
+				},
+			}})
+			<-stream.ReadRTCP()
+		}
+		receive(1234, rtpTime.Add(-2*time.Second))
+		reference := rtpTime.Add(-time.Second)
+		receive(1234, reference)
+		mt.SetNow(rtpTime.Add(250 * time.Millisecond))
+		receive(5678, rtpTime)
+
+		lastRR := func(reference time.Time) uint32 {
+			return uint32(ntp.ToNTP(reference) >> 16) //nolint:gosec // G115
+		}
+		report := xrSender.generateReport(rtpTime.Add(500 * time.Millisecond))
+		assert.NotNil(t, report)
+		assert.Equal(t, []rtcp.ReportBlock{
+			&rtcp.DLRRReportBlock{
+				Reports: []rtcp.DLRRReport{
+					{SSRC: 1234, LastRR: lastRR(reference), DLRR: 1 << 15},
+					{SSRC: 5678, LastRR: lastRR(rtpTime), DLRR: 1 << 14},
+				},
+			},
+		}, report.Reports)
+
+		// the reference time is answered once only
+		assert.Nil(t, xrSender.generateReport(rtpTime.Add(time.Second)))
+	})
+}
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+)
+
+// XRSenderOption can be used to configure XRSenderInterceptor.
+type XRSenderOption func(r *XRSenderInterceptor) error
+
+// WithXRSenderLoggerFactory sets a logger factory for the interceptor.
+func WithXRSenderLoggerFactory(loggerFactory logging.LoggerFactory) XRSenderOption {
+	return func(r *XRSenderInterceptor) error {
+		r.loggerFactory = loggerFactory
+
+		return nil
+	}
+}
+
+// XRSenderInterval sets send interval for the interceptor.
+func XRSenderInterval(interval time.Duration) XRSenderOption {
+	return func(r *XRSenderInterceptor) error {
+		r.interval = interval
+
+		return nil
+	}
+}
+
+// XRSenderClock sets the clock the interceptor gets the time and its ticker from.
+func XRSenderClock(clock interceptor.Clock) XRSenderOption {
+	return func(r *XRSenderInterceptor) error {
+		r.now = clock.Now
+		r.newTicker = func(d time.Duration) Ticker {
+			return clock.NewTicker(d)
+		}
+
+		return nil
+	}
+}
@@ -119,11 +119,12 @@ func (s *SenderInterceptor) BindRemoteStream(
 			return 0, nil, err
 		}
 
+		ecn, _ := attr.Get(interceptor.ECNAttributesKey).(rtcp.ECN)
 		p := packet{
 			arrival:        s.now(),
 			ssrc:           header.SSRC,
 			sequenceNumber: header.SequenceNumber,
-			ecn:            0, // ECN is not supported (yet).
+			ecn:            uint8(ecn),
 		}
 		s.packetChan <- p
 
@@ -9,10 +9,12 @@ import (
 
 	"github.com/pion/interceptor"
 	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/interceptor/pkg/netem"
 	"github.com/pion/logging"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 //nolint:maintidx,cyclop
@@ -282,3 +284,51 @@ func TestInterceptor(t *testing.T) {
 		}, ccfb.ReportBlocks[0].MetricBlocks)
 	})
 }
+
+func TestInterceptor_ECN(t *testing.T) {
+	clock := netem.NewVirtualClock(time.Unix(1000, 0))
+	f, err := NewSenderInterceptor(SenderClock(clock))
+	require.NoError(t, err)
+	i, err := f.NewInterceptor("")
+	require.NoError(t, err)
+
+	// The packets waiting over 15ms in the bottleneck queue are marked CE.
+	conn := netem.NewConnection(clock, &interceptor.NoOp{}, i,
+		netem.LinkConfig{Bandwidth: 800_000, MarkingThreshold: 15 * time.Millisecond}, netem.LinkConfig{})
+	defer func() {
+		assert.NoError(t, conn.Close())
+	}()
+	conn.B.AddRemoteStream(&interceptor.StreamInfo{SSRC: 1}, nil)
+	feedback := make(chan []rtcp.Packet, 1)
+	conn.A.OnRTCP(func(pkts []rtcp.Packet) {
+		feedback <- pkts
+	})
+
+	writer := conn.A.AddLocalStream(&interceptor.StreamInfo{SSRC: 1})
+	for i := range 4 {
+		_, err = writer.Write(&rtp.Header{SSRC: 1, SequenceNumber: uint16(i)}, make([]byte, 988), //nolint:gosec
+			interceptor.Attributes{interceptor.ECNAttributesKey: rtcp.ECNECT1})
+		require.NoError(t, err)
+	}
+	clock.Advance(50 * time.Millisecond)
+
+	// The ticker is created and the feedback is sent by the interceptor goroutine.
+	var pkts []rtcp.Packet
+	require.Eventually(t, func() bool {
+		clock.Advance(10 * time.Millisecond)
+		select {
+		case pkts = <-feedback:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond)
+	require.Len(t, pkts, 1)
+	ccfb, ok := pkts[0].(*rtcp.CCFeedbackReport)
+	require.True(t, ok)
+	var ecns []rtcp.ECN
+	for _, block := range ccfb.ReportBlocks[0].MetricBlocks {
+		ecns = append(ecns, block.ECN)
+	}
+	assert.Equal(t, []rtcp.ECN{rtcp.ECNECT1, rtcp.ECNECT1, rtcp.ECNCE, rtcp.ECNCE}, ecns)
+}
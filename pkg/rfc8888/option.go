@@ -6,6 +6,7 @@ package rfc8888
 import (
 	"time"
 
+	"github.com/pion/interceptor"
 	"github.com/pion/logging"
 )
 
@@ -30,6 +31,18 @@ func SenderNow(f func() time.Time) Option {
 	}
 }
 
+// SenderClock sets the clock the interceptor gets the time and its ticker from.
+func SenderClock(clock interceptor.Clock) Option {
+	return func(i *SenderInterceptor) error {
+		i.now = clock.Now
+		i.newTicker = func(d time.Duration) ticker {
+			return clock.NewTicker(d)
+		}
+
+		return nil
+	}
+}
+
 // SendInterval sets the feedback send interval for the interceptor.
 func SendInterval(interval time.Duration) Option {
 	return func(s *SenderInterceptor) error {
@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtpfb
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+const defaultAggregatorWindow = time.Second
+
+var errInvalidWindow = errors.New("aggregator window must be positive")
+
+// AggregatorOption can be used to configure an Aggregator.
+type AggregatorOption func(*Aggregator) error
+
+// AggregatorWindow sets the duration over which the Aggregator aggregates the
+// Reports. The default is one second.
+func AggregatorWindow(window time.Duration) AggregatorOption {
+	return func(a *Aggregator) error {
+		if window <= 0 {
+			return errInvalidWindow
+		}
+		a.window = window
+
+		return nil
+	}
+}
+
+type packetSample struct {
+	reported time.Time
+	arrived  bool
+	size     int
+	arrival  time.Time
+}
+
+type rttSample struct {
+	reported time.Time
+	rtt      time.Duration
+}
+
+// Aggregator aggregates the Reports of a PeerConnection over a sliding window
+// into the loss rate and delivered bitrate of each SSRC, and percentiles of the
+// round trip time. The window ends at the arrival of the latest Report, so the
+// statistics only change when Reports arrive. Its OnReport method is a
+// ReportHandler, an Aggregator is usually created for each PeerConnection and
+// subscribed to its Subscriber:
+//
+//	factory.OnNewPeerConnection(func(id string, s rtpfb.Subscriber) {
+//		aggregator, _ := rtpfb.NewAggregator()
+//		s.Subscribe(aggregator.OnReport)
+//		aggregators.Store(id, aggregator)
+//	})
+type Aggregator struct {
+	window time.Duration
+
+	lock    sync.Mutex
+	packets map[uint32][]packetSample
+	rtts    []rttSample
+}
+
+// NewAggregator returns a new Aggregator.
+func NewAggregator(opts ...AggregatorOption) (*Aggregator, error) {
+	aggregator := &Aggregator{
+		window:  defaultAggregatorWindow,
+		packets: map[uint32][]packetSample{},
+	}
+	for _, opt := range opts {
+		if err := opt(aggregator); err != nil {
+			return nil, err
+		}
+	}
+
+	return aggregator, nil
+}
+
+// OnReport adds a Report to the aggregation, and drops the packets and round
+// trip times which fell out of the window.
+func (a *Aggregator) OnReport(report Report) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for _, pr := range report.PacketReports {
+		a.packets[pr.SSRC] = append(a.packets[pr.SSRC], packetSample{
+			reported: report.Arrival,
+			arrived:  pr.Arrived,
+			size:     pr.Size,
+			arrival:  pr.Arrival,
+		})
+	}
+	if report.RTT > 0 {
+		a.rtts = append(a.rtts, rttSample{reported: report.Arrival, rtt: report.RTT})
+	}
+
+	start := report.Arrival.Add(-a.window)
+	for ssrc, samples := range a.packets {
+		i := 0
+		for i < len(samples) && samples[i].reported.Before(start) {
+			i++
+		}
+		if i == len(samples) {
+			delete(a.packets, ssrc)
+
+			continue
+		}
+		a.packets[ssrc] = samples[i:]
+	}
+	i := 0
+	for i < len(a.rtts) && a.rtts[i].reported.Before(start) {
+		i++
+	}
+	a.rtts = a.rtts[i:]
+}
+
+// SSRCs returns the SSRCs with packets in the window, in increasing order.
+func (a *Aggregator) SSRCs() []uint32 {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	ssrcs := make([]uint32, 0, len(a.packets))
+	for ssrc := range a.packets {
+		ssrcs = append(ssrcs, ssrc)
+	}
+	slices.Sort(ssrcs)
+
+	return ssrcs
+}
+
+// LossRate returns the fraction of the packets of the SSRC reported in the
+// window which did not arrive. It returns 0 if no packets were reported.
+func (a *Aggregator) LossRate(ssrc uint32) float64 {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	samples := a.packets[ssrc]
+	if len(samples) == 0 {
+		return 0
+	}
+	lost := 0
+	for _, sample := range samples {
+		if !sample.arrived {
+			lost++
+		}
+	}
+
+	return float64(lost) / float64(len(samples))
+}
+
+// DeliveredBitrate returns the bitrate in bits per second the packets of the
+// SSRC reported in the window arrived at the receiver with. It is measured on
+// the arrival times taken by the receiver, and is 0 until at least two packets
+// arrived at different times.
+func (a *Aggregator) DeliveredBitrate(ssrc uint32) int {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	var first, last time.Time
+	bytes, firstSize := 0, 0
+	for _, sample := range a.packets[ssrc] {
+		if !sample.arrived {
+			continue
+		}
+		bytes += sample.size
+		if first.IsZero() || sample.arrival.Before(first) {
+			first = sample.arrival
+			firstSize = sample.size
+		}
+		if sample.arrival.After(last) {
+			last = sample.arrival
+		}
+	}
+	span := last.Sub(first)
+	if span <= 0 {
+		return 0
+	}
+
+	// The first packet arrived at the start of the span, its bytes were sent
+	// before it.
+	return int(float64(bytes-firstSize) * 8 / span.Seconds())
+}
+
+// RTTPercentile returns the p-th percentile, 0 < p <= 100, of the round trip
+// times reported in the window, by the nearest rank method. It returns 0 if no
+// round trip time was reported.
+func (a *Aggregator) RTTPercentile(p float64) time.Duration {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if len(a.rtts) == 0 {
+		return 0
+	}
+	rtts := make([]time.Duration, 0, len(a.rtts))
+	for _, sample := range a.rtts {
+		rtts = append(rtts, sample.rtt)
+	}
+	slices.Sort(rtts)
+	rank := int(math.Ceil(p / 100 * float64(len(rtts))))
+	rank = min(max(rank, 1), len(rtts))
+
+	return rtts[rank-1]
+}
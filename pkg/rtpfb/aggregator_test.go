@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtpfb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregator(t *testing.T) {
+	t.Run("invalid_window", func(t *testing.T) {
+		_, err := NewAggregator(AggregatorWindow(0))
+		assert.ErrorIs(t, err, errInvalidWindow)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		aggregator, err := NewAggregator()
+		assert.NoError(t, err)
+
+		assert.Empty(t, aggregator.SSRCs())
+		assert.Equal(t, 0.0, aggregator.LossRate(1))
+		assert.Equal(t, 0, aggregator.DeliveredBitrate(1))
+		assert.Equal(t, time.Duration(0), aggregator.RTTPercentile(50))
+	})
+
+	t.Run("aggregates_reports", func(t *testing.T) {
+		aggregator, err := NewAggregator()
+		assert.NoError(t, err)
+
+		start := time.Unix(1000, 0)
+		for i := range 10 {
+			ts := start.Add(time.Duration(i) * 100 * time.Millisecond)
+			report := Report{Arrival: ts, RTT: time.Duration(i+1) * 10 * time.Millisecond}
+			for j := range 10 {
+				report.PacketReports = append(report.PacketReports, PacketReport{
+					SSRC:    1,
+					Size:    1250,
+					Arrived: j != 0,
+					Arrival: ts.Add(time.Duration(j) * 10 * time.Millisecond),
+				}, PacketReport{
+					SSRC:    2,
+					Size:    100,
+					Arrived: true,
+					Arrival: ts.Add(time.Duration(j) * 10 * time.Millisecond),
+				})
+			}
+			aggregator.OnReport(report)
+		}
+
+		assert.Equal(t, []uint32{1, 2}, aggregator.SSRCs())
+		assert.InDelta(t, 0.1, aggregator.LossRate(1), 1e-9)
+		assert.Equal(t, 0.0, aggregator.LossRate(2))
+		// 89 packets of 10000 bits after the first one arrived, over 980ms.
+		assert.InDelta(t, 890_000/0.98, aggregator.DeliveredBitrate(1), 1)
+		assert.Equal(t, 80_000, aggregator.DeliveredBitrate(2))
+		assert.Equal(t, 10*time.Millisecond, aggregator.RTTPercentile(1))
+		assert.Equal(t, 50*time.Millisecond, aggregator.RTTPercentile(50))
+		assert.Equal(t, 100*time.Millisecond, aggregator.RTTPercentile(95))
+	})
+
+	t.Run("drops_old_reports", func(t *testing.T) {
+		aggregator, err := NewAggregator(AggregatorWindow(time.Second))
+		assert.NoError(t, err)
+
+		start := time.Unix(1000, 0)
+		aggregator.OnReport(Report{
+			Arrival:       start,
+			RTT:           time.Second,
+			PacketReports: []PacketReport{{SSRC: 1, Arrived: false}},
+		})
+		aggregator.OnReport(Report{
+			Arrival:       start.Add(2 * time.Second),
+			RTT:           10 * time.Millisecond,
+			PacketReports: []PacketReport{{SSRC: 2, Arrived: true}},
+		})
+
+		assert.Equal(t, []uint32{2}, aggregator.SSRCs())
+		assert.Equal(t, 0.0, aggregator.LossRate(1))
+		assert.Equal(t, 10*time.Millisecond, aggregator.RTTPercentile(100))
+	})
+}
@@ -7,47 +7,71 @@ import (
 	"sync"
 	"time"
 
+	"github.com/pion/interceptor"
 	"github.com/pion/rtcp"
 )
 
-type ssrcSequenceNumber struct {
-	ssrc           uint32
-	sequenceNumber uint16
-}
-
-// history keeps a global sequence number for all outgoing packets, called
-// counter, to avoid confusion with transport wide sequence numbers from TWCC.
-// Packets will be mapped to the counter either by their TWCC sequence number or
-// by their combination of RTP sequence number and SSRC. When feedback arrives,
-// calls to onFeedback will update the status of each packet included in the
-// report. buildReport can be used to create a new report including all packets
-// from nextReport to highestAcked.
+// history maps feedback to the outgoing packets recorded in an
+// interceptor.SentPacketRegistry. The index of a packet in the registry is a
+// global sequence number for all outgoing packets, called counter, to avoid
+// confusion with transport wide sequence numbers from TWCC. Packets are found
+// either by their TWCC sequence number or by their combination of RTP sequence
+// number and SSRC. When feedback arrives, calls to onFeedback will update the
+// status of each packet included in the report. buildReport can be used to
+// create a new report including all packets from nextReport to highestAcked.
+//
+// The registry is picked once, by the first outgoing packet: the registry shared
+// by the interceptor numbering the packets if the packet carries one, or a
+// registry of the history otherwise. Packets are only added to a registry the
+// history owns.
 type history struct {
-	lock               sync.RWMutex
-	counter            uint64
-	twccToCounter      map[uint16]uint64
-	ssrcSeqNrToCounter map[ssrcSequenceNumber]uint64
+	lock     sync.RWMutex
+	registry *interceptor.SentPacketRegistry
+	owned    bool
 
-	packets      map[uint64]*PacketReport
+	acks         map[uint64]acknowledgement
 	highestAcked uint64
 	nextReport   uint64
-
-	cleanUntil uint64
 }
 
 func newHistory() *history {
 	return &history{
-		lock:               sync.RWMutex{},
-		counter:            0,
-		twccToCounter:      map[uint16]uint64{},
-		ssrcSeqNrToCounter: map[ssrcSequenceNumber]uint64{},
-		packets:            make(map[uint64]*PacketReport),
-		highestAcked:       0,
-		nextReport:         0,
-		cleanUntil:         0,
+		lock:         sync.RWMutex{},
+		acks:         map[uint64]acknowledgement{},
+		highestAcked: 0,
+		nextReport:   0,
+	}
+}
+
+// useRegistry picks the registry the packets are looked up in, unless it was
+// picked before: registry, or a registry of the history if registry is nil. It
+// returns whether the history owns the registry, in which case the outgoing
+// packets are recorded with addOutgoing.
+func (h *history) useRegistry(registry *interceptor.SentPacketRegistry) bool {
+	h.lock.RLock()
+	picked, owned := h.registry != nil, h.owned
+	h.lock.RUnlock()
+	if picked {
+		return owned
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.registry == nil {
+		h.owned = registry == nil
+		if h.owned {
+			registry = interceptor.NewSentPacketRegistry()
+		}
+		h.registry = registry
+		h.nextReport = registry.NextIndex()
 	}
+
+	return h.owned
 }
 
+// addOutgoing records an outgoing packet. It must only be called if the history
+// owns its registry.
 func (h *history) addOutgoing(
 	ssrc uint32,
 	rtpSequenceNumber uint16,
@@ -56,83 +80,70 @@ func (h *history) addOutgoing(
 	size int,
 	departure time.Time,
 ) {
-	h.lock.Lock()
-	defer h.lock.Unlock()
-
-	if isTWCC {
-		h.twccToCounter[twccSequenceNumber] = h.counter
-	} else {
-		h.ssrcSeqNrToCounter[ssrcSequenceNumber{
-			ssrc:           ssrc,
-			sequenceNumber: rtpSequenceNumber,
-		}] = h.counter
+	if !h.useRegistry(nil) {
+		return
 	}
-	h.packets[h.counter] = &PacketReport{
-		SSRC:               ssrc,
-		SequenceNumber:     h.counter,
-		RTPSequenceNumber:  rtpSequenceNumber,
-		TWCCSequenceNumber: twccSequenceNumber,
-		Size:               size,
-		Departure:          departure,
-		Arrived:            false,
-		Arrival:            time.Time{},
-		ECN:                rtcp.ECNNonECT,
-	}
-	h.counter++
+
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	h.registry.Add(interceptor.SentPacket{
+		SSRC:                    ssrc,
+		SequenceNumber:          rtpSequenceNumber,
+		TransportWide:           isTWCC,
+		TransportSequenceNumber: twccSequenceNumber,
+		Size:                    size,
+		Departure:               departure,
+	})
 }
 
-// onFeedback maps an incoming ack for counter to the PacketReport stored when
-// the packet was sent. If the packet cannot be found, the ack is ignored.
+// onFeedback stores an incoming ack for the packet recorded in the registry.
 //
-// onFeedback must be called while holding the lock for reading.
+// onFeedback must be called while holding the lock for writing.
 // onFeedback returns the time between ts and the time the packet was sent.
-func (h *history) onFeedback(ts time.Time, counter uint64, ack acknowledgement) (time.Duration, bool) {
-	p, ok := h.packets[counter]
-	if !ok {
-		// ignore ack for unknown packet
-		return 0, false
-	}
-	p.Arrived = ack.arrived
-	if p.Arrived && h.highestAcked < p.SequenceNumber {
-		h.highestAcked = p.SequenceNumber
+func (h *history) onFeedback(ts time.Time, packet interceptor.SentPacket, ack acknowledgement) time.Duration {
+	h.acks[packet.Index] = ack
+	if ack.arrived && h.highestAcked < packet.Index {
+		h.highestAcked = packet.Index
 	}
-	p.Arrival = ack.arrival
-	p.ECN = ack.ecn
 
-	return ts.Sub(p.Departure), true
+	return ts.Sub(packet.Departure)
 }
 
-// onTWCCFeedback maps an acknowledgement to the counter by TWCC sequence number
+// onTWCCFeedback maps an acknowledgement to the packet by TWCC sequence number
 // and then calls onFeedback.
 func (h *history) onTWCCFeedback(ts time.Time, ack acknowledgement) (time.Duration, bool) {
-	h.lock.RLock()
-	defer h.lock.RUnlock()
+	h.lock.Lock()
+	defer h.lock.Unlock()
 
-	counter, ok := h.twccToCounter[ack.sequenceNumber]
-	if !ok {
-		// ignore ack for unknown packet
+	if h.registry == nil {
+		return 0, false
+	}
+	packet, ok := h.registry.GetByTransportSequenceNumber(ack.sequenceNumber)
+	if !ok || packet.Index < h.nextReport {
+		// ignore ack for unknown or already reported packet
 		return 0, false
 	}
 
-	return h.onFeedback(ts, counter, ack)
+	return h.onFeedback(ts, packet, ack), true
 }
 
-// onCCFBFeedback maps an acknowledgement to the counter by ssrc and sequence
+// onCCFBFeedback maps an acknowledgement to the packet by ssrc and sequence
 // number and then calls onFeedback.
 func (h *history) onCCFBFeedback(ts time.Time, ssrc uint32, ack acknowledgement) (time.Duration, bool) {
-	h.lock.RLock()
-	defer h.lock.RUnlock()
+	h.lock.Lock()
+	defer h.lock.Unlock()
 
-	counter, ok := h.ssrcSeqNrToCounter[ssrcSequenceNumber{
-		ssrc:           ssrc,
-		sequenceNumber: ack.sequenceNumber,
-	}]
-	if !ok {
-		// ignore ack for unknown packet
+	if h.registry == nil {
+		return 0, false
+	}
+	packet, ok := h.registry.GetBySequenceNumber(ssrc, ack.sequenceNumber)
+	if !ok || packet.Index < h.nextReport {
+		// ignore ack for unknown or already reported packet
 		return 0, false
 	}
 
-	return h.onFeedback(ts, counter, ack)
+	return h.onFeedback(ts, packet, ack), true
 }
 
 // buildReport builds a report containing all packets up to the highest
@@ -147,47 +158,38 @@ func (h *history) buildReport() []PacketReport {
 	h.lock.Lock()
 	defer h.lock.Unlock()
 
-	if h.nextReport > h.highestAcked {
+	if len(h.acks) == 0 || h.nextReport > h.highestAcked {
 		return nil
 	}
 	res := make([]PacketReport, 0, h.highestAcked-h.nextReport+1)
 	for i := h.nextReport; i <= h.highestAcked; i++ {
-		packet, ok := h.packets[i]
+		ack, acked := h.acks[i]
+		delete(h.acks, i)
+		packet, ok := h.registry.Get(i)
 		if !ok {
 			// packet dropped from history?
 			continue
 		}
-		res = append(res, *packet)
-		h.delete(packet)
-		if packet.SequenceNumber >= h.nextReport {
-			h.nextReport = packet.SequenceNumber + 1
+		report := PacketReport{
+			SSRC:               packet.SSRC,
+			SequenceNumber:     packet.Index,
+			RTPSequenceNumber:  packet.SequenceNumber,
+			IsTWCC:             packet.TransportWide,
+			TWCCSequenceNumber: packet.TransportSequenceNumber,
+			Size:               packet.Size,
+			Departure:          packet.Departure,
+			Arrived:            false,
+			Arrival:            time.Time{},
+			ECN:                rtcp.ECNNonECT,
+		}
+		if acked {
+			report.Arrived = ack.arrived
+			report.Arrival = ack.arrival
+			report.ECN = ack.ecn
 		}
+		res = append(res, report)
 	}
-	h.cleanBefore(h.nextReport)
+	h.nextReport = h.highestAcked + 1
 
 	return res
 }
-
-// delete removes p from the history. It must be called while holding the lock
-// for writing.
-func (h *history) delete(p *PacketReport) {
-	if p.IsTWCC {
-		delete(h.twccToCounter, p.TWCCSequenceNumber)
-	}
-	delete(h.ssrcSeqNrToCounter, ssrcSequenceNumber{
-		ssrc:           p.SSRC,
-		sequenceNumber: p.RTPSequenceNumber,
-	})
-}
-
-// cleanBefore removes all entries in the interval [h.cleanBefore, counter).
-// cleanBefore must be called while holding the lock for writing, because it
-// calls out to delete.
-func (h *history) cleanBefore(counter uint64) {
-	for i := h.cleanUntil; i < counter; i++ {
-		if p, ok := h.packets[i]; ok {
-			h.delete(p)
-		}
-	}
-	h.cleanUntil = counter - 1
-}
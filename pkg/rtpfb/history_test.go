@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pion/interceptor"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -127,4 +128,49 @@ func TestHistory(t *testing.T) {
 			})
 		}
 	})
+	t.Run("shared_registry", func(t *testing.T) {
+		registry := interceptor.NewSentPacketRegistry()
+		registry.Add(interceptor.SentPacket{SSRC: 1, SequenceNumber: 1})
+		history := newHistory()
+		assert.False(t, history.useRegistry(registry))
+		for i := range uint16(3) {
+			registry.Add(interceptor.SentPacket{
+				SSRC:                    1,
+				SequenceNumber:          2 + i,
+				TransportWide:           true,
+				TransportSequenceNumber: i,
+				Size:                    1200,
+			})
+		}
+
+		history.onTWCCFeedback(time.Time{}, acknowledgement{sequenceNumber: 0, arrived: true})
+		history.onTWCCFeedback(time.Time{}, acknowledgement{sequenceNumber: 1, arrived: false})
+		history.onTWCCFeedback(time.Time{}, acknowledgement{sequenceNumber: 2, arrived: true})
+		assert.Equal(t, []PacketReport{
+			{
+				SSRC: 1, SequenceNumber: 1, RTPSequenceNumber: 2, IsTWCC: true, TWCCSequenceNumber: 0, Size: 1200,
+				Arrived: true,
+			},
+			{
+				SSRC: 1, SequenceNumber: 2, RTPSequenceNumber: 3, IsTWCC: true, TWCCSequenceNumber: 1, Size: 1200,
+				Arrived: false,
+			},
+			{
+				SSRC: 1, SequenceNumber: 3, RTPSequenceNumber: 4, IsTWCC: true, TWCCSequenceNumber: 2, Size: 1200,
+				Arrived: true,
+			},
+		}, history.buildReport())
+		assert.Nil(t, history.buildReport())
+
+		// The registry is picked once, and packets are not added to a shared one.
+		assert.False(t, history.useRegistry(nil))
+		assert.False(t, history.useRegistry(interceptor.NewSentPacketRegistry()))
+		history.addOutgoing(1, 5, false, 0, 1200, time.Time{})
+		assert.Equal(t, uint64(4), registry.NextIndex())
+	})
+	t.Run("owned_registry", func(t *testing.T) {
+		history := newHistory()
+		assert.True(t, history.useRegistry(nil))
+		assert.True(t, history.useRegistry(interceptor.NewSentPacketRegistry()))
+	})
 }
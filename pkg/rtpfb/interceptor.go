@@ -6,6 +6,8 @@ package rtpfb
 
 import (
 	"math"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/pion/interceptor"
@@ -23,6 +25,7 @@ type ccfbAttributesKeyType uint32
 const CCFBAttributesKey ccfbAttributesKeyType = iota
 
 type packetLog interface {
+	useRegistry(registry *interceptor.SentPacketRegistry) bool
 	addOutgoing(
 		ssrc uint32,
 		rtpSequenceNumber uint16,
@@ -47,6 +50,11 @@ func WithLoggerFactory(lf logging.LoggerFactory) Option {
 	}
 }
 
+// WithClock sets the clock the sent packets and the feedback reports are timestamped by.
+func WithClock(clock interceptor.Clock) Option {
+	return timeFactory(clock.Now)
+}
+
 func timeFactory(f func() time.Time) Option {
 	return func(i *Interceptor) error {
 		i.timestamp = f
@@ -63,20 +71,44 @@ func setHistory(pl packetLog) Option {
 	}
 }
 
+// ReportHandler is called with every Report built from an incoming feedback
+// packet. It is called on the goroutine reading RTCP, so it should not block.
+type ReportHandler func(Report)
+
+// Subscriber lets the application receive the Reports of a PeerConnection
+// without reading them from the attributes of the RTCPReader.
+type Subscriber interface {
+	// Subscribe registers handler to be called with every new Report. The
+	// returned function removes the handler again.
+	Subscribe(handler ReportHandler) (unsubscribe func())
+}
+
+// NewPeerConnectionCallback receives the Subscriber of a newly created
+// PeerConnection.
+type NewPeerConnectionCallback func(string, Subscriber)
+
 // InterceptorFactory is a factory for CCFB interceptors.
 type InterceptorFactory struct {
-	opts []Option
+	opts              []Option
+	addPeerConnection NewPeerConnectionCallback
 }
 
 // NewInterceptor returns a new CCFB InterceptorFactory.
 func NewInterceptor(opts ...Option) (*InterceptorFactory, error) {
 	return &InterceptorFactory{
-		opts: opts,
+		opts:              opts,
+		addPeerConnection: nil,
 	}, nil
 }
 
+// OnNewPeerConnection sets the callback that is called when a new
+// PeerConnection is created.
+func (f *InterceptorFactory) OnNewPeerConnection(cb NewPeerConnectionCallback) {
+	f.addPeerConnection = cb
+}
+
 // NewInterceptor returns a new ccfb.Interceptor.
-func (f *InterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+func (f *InterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor, error) {
 	in := &Interceptor{
 		NoOp:       interceptor.NoOp{},
 		logFactory: logging.NewDefaultLoggerFactory(),
@@ -91,6 +123,10 @@ func (f *InterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor,
 	}
 	in.log = in.logFactory.NewLogger("ccfb_interceptor")
 
+	if f.addPeerConnection != nil {
+		f.addPeerConnection(id, in)
+	}
+
 	return in, nil
 }
 
@@ -99,7 +135,15 @@ func (f *InterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor,
 // each incoming feedback report, it will add an entry to the interceptor
 // attributes, which can be read from the `RTCPReader`
 // (`webrtc.RTPSender.Read`). For each acknowledgement included in the feedback
-// report, a PacketReport will be added to the ccfb.Report.
+// report, a PacketReport will be added to the ccfb.Report. The Reports are
+// also passed to the handlers subscribed with Subscribe.
+//
+// If the first outgoing packet carries an interceptor.SentPacketRegistry in its
+// attributes, as set by the twcc.HeaderExtensionInterceptor or the
+// gcc.SendSideBWE, the Interceptor uses it to map the feedback to the packets
+// instead of recording them a second time. The Interceptor then does not record
+// any packet, so it must be registered before the interceptor numbering the
+// packets, so that all of them pass it.
 type Interceptor struct {
 	interceptor.NoOp
 	logFactory logging.LoggerFactory
@@ -107,6 +151,55 @@ type Interceptor struct {
 	timestamp  func() time.Time
 
 	history packetLog
+
+	handlersLock sync.Mutex
+	handlers     []subscription
+	nextHandler  uint64
+}
+
+type subscription struct {
+	id      uint64
+	handler ReportHandler
+}
+
+// Subscribe implements Subscriber.
+func (i *Interceptor) Subscribe(handler ReportHandler) func() {
+	i.handlersLock.Lock()
+	defer i.handlersLock.Unlock()
+
+	id := i.nextHandler
+	i.nextHandler++
+	i.handlers = append(i.handlers, subscription{id: id, handler: handler})
+
+	return func() {
+		i.handlersLock.Lock()
+		defer i.handlersLock.Unlock()
+
+		i.handlers = slices.DeleteFunc(slices.Clone(i.handlers), func(s subscription) bool {
+			return s.id == id
+		})
+	}
+}
+
+// publish calls the subscribed handlers in the order they subscribed in.
+func (i *Interceptor) publish(report Report) {
+	i.handlersLock.Lock()
+	handlers := i.handlers
+	i.handlersLock.Unlock()
+
+	for _, s := range handlers {
+		s.handler(report)
+	}
+}
+
+// recordsPackets returns true if the Interceptor records the outgoing packets
+// itself. It does not if the packets are recorded in the
+// interceptor.SentPacketRegistry set in the attributes of the first packet sent,
+// by an interceptor earlier in the chain.
+func (i *Interceptor) recordsPackets(attributes interceptor.Attributes) bool {
+	registry, _ := attributes.Get(interceptor.SentPacketRegistryAttributesKey).(*interceptor.SentPacketRegistry)
+
+	return i.history.useRegistry(registry)
 }
 
 func (i *Interceptor) bindTWCCStream(twccHdrExtID uint8, writer interceptor.RTPWriter) interceptor.RTPWriter {
@@ -115,6 +208,9 @@ func (i *Interceptor) bindTWCCStream(twccHdrExtID uint8, writer interceptor.RTPW
 		payload []byte,
 		attributes interceptor.Attributes,
 	) (int, error) {
+		if !i.recordsPackets(attributes) {
+			return writer.Write(header, payload, attributes)
+		}
 		ts := i.timestamp()
 
 		var twccHdrExt rtp.TransportCCExtension
@@ -148,6 +244,9 @@ func (i *Interceptor) bindCCFBStream(writer interceptor.RTPWriter) interceptor.R
 		payload []byte,
 		attributes interceptor.Attributes,
 	) (int, error) {
+		if !i.recordsPackets(attributes) {
+			return writer.Write(header, payload, attributes)
+		}
 		ts := i.timestamp()
 
 		i.history.addOutgoing(
@@ -211,6 +310,7 @@ func (i *Interceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.
 				PacketReports: prs,
 			}
 			attr.Set(CCFBAttributesKey, report)
+			i.publish(report)
 		}
 
 		return n, attr, err
@@ -245,5 +345,10 @@ func (i *Interceptor) processFeedback(ts time.Time, pkts []rtcp.Packet) (time.Du
 		}
 	}
 
+	if shortestRTT == time.Duration(math.MaxInt64) {
+		// none of the acknowledged packets was found in the history
+		return 0, i.history.buildReport()
+	}
+
 	return shortestRTT - ackDelay, i.history.buildReport()
 }
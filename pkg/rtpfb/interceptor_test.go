@@ -11,6 +11,7 @@ import (
 	"github.com/pion/interceptor"
 	"github.com/pion/interceptor/internal/ntp"
 	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/interceptor/pkg/twcc"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/assert"
@@ -23,8 +24,18 @@ type ackListEntry struct {
 }
 
 type mockHistory struct {
-	log  []PacketReport
-	acks []ackListEntry
+	log      []PacketReport
+	acks     []ackListEntry
+	registry *interceptor.SentPacketRegistry
+}
+
+// useRegistry implements packetLog.
+func (m *mockHistory) useRegistry(registry *interceptor.SentPacketRegistry) bool {
+	if m.registry == nil {
+		m.registry = registry
+	}
+
+	return m.registry == nil
 }
 
 // addOutgoing implements packetLog.
@@ -154,6 +165,83 @@ func TestInterceptor(t *testing.T) {
 		}
 	})
 
+	t.Run("uses_shared_registry", func(t *testing.T) {
+		mh := &mockHistory{
+			log: []PacketReport{},
+		}
+		f, err := NewInterceptor(setHistory(mh))
+		assert.NoError(t, err)
+		rtpfbInterceptor, err := f.NewInterceptor("")
+		assert.NoError(t, err)
+		headerExtension, err := twcc.NewHeaderExtensionInterceptor()
+		assert.NoError(t, err)
+		headerExtensionInterceptor, err := headerExtension.NewInterceptor("")
+		assert.NoError(t, err)
+
+		info := &interceptor.StreamInfo{
+			RTPHeaderExtensions: []interceptor.RTPHeaderExtension{{URI: transportCCURI, ID: 2}},
+		}
+		stream := test.NewMockStream(info, interceptor.NewChain([]interceptor.Interceptor{
+			rtpfbInterceptor, headerExtensionInterceptor,
+		}))
+		for i := range 3 {
+			assert.NoError(t, stream.WriteRTP(&rtp.Packet{Header: rtp.Header{SequenceNumber: uint16(i)}}))
+		}
+
+		assert.Empty(t, mh.log)
+		assert.NotNil(t, mh.registry)
+		packet, ok := mh.registry.GetByTransportSequenceNumber(2)
+		assert.True(t, ok)
+		assert.Equal(t, uint16(2), packet.SequenceNumber)
+	})
+
+	t.Run("publishes_reports", func(t *testing.T) {
+		f, err := NewInterceptor(timeFactory(func() time.Time {
+			return mockTimeStamp
+		}))
+		assert.NoError(t, err)
+		var subscriber Subscriber
+		f.OnNewPeerConnection(func(id string, s Subscriber) {
+			assert.Equal(t, "pc", id)
+			subscriber = s
+		})
+		i, err := f.NewInterceptor("pc")
+		assert.NoError(t, err)
+		assert.NotNil(t, subscriber)
+
+		reports := make(chan Report, 10)
+		unsubscribe := subscriber.Subscribe(func(r Report) {
+			reports <- r
+		})
+
+		stream := test.NewMockStream(&interceptor.StreamInfo{}, i)
+		for _, seqNr := range []uint16{1, 2} {
+			assert.NoError(t, stream.WriteRTP(&rtp.Packet{Header: rtp.Header{SSRC: 1, SequenceNumber: seqNr}}))
+		}
+		feedback := &rtcp.CCFeedbackReport{
+			ReportBlocks: []rtcp.CCFeedbackReportBlock{{
+				MediaSSRC:     1,
+				BeginSequence: 1,
+				MetricBlocks:  []rtcp.CCFeedbackMetricBlock{{Received: true}, {Received: true}},
+			}},
+		}
+		stream.ReceiveRTCP([]rtcp.Packet{feedback})
+		<-stream.ReadRTCP()
+
+		report := <-reports
+		assert.Equal(t, mockTimeStamp, report.Arrival)
+		assert.Len(t, report.PacketReports, 2)
+
+		unsubscribe()
+		assert.NoError(t, stream.WriteRTP(&rtp.Packet{Header: rtp.Header{SSRC: 1, SequenceNumber: 3}}))
+		feedback.ReportBlocks[0].BeginSequence = 3
+		feedback.ReportBlocks[0].MetricBlocks = feedback.ReportBlocks[0].MetricBlocks[:1]
+		stream.ReceiveRTCP([]rtcp.Packet{feedback})
+		<-stream.ReadRTCP()
+		assert.Empty(t, reports)
+		assert.NoError(t, stream.Close())
+	})
+
 	t.Run("calls_on_feedback", func(t *testing.T) {
 		cases := []struct {
 			feedback rtcp.Packet
@@ -54,7 +54,8 @@ type PacketReport struct {
 // A Report contains the Arrival time of a CCFB or TWCC packet, the estimated
 // RTT based on the feedback packet and a list of PacketReport for all
 // acknowledged packets that were still in the history and not yet included in
-// an earlier Report.
+// an earlier Report. RTT is 0 if none of the acknowledged packets could be
+// matched to a sent packet.
 type Report struct {
 	Arrival       time.Time
 	RTT           time.Duration
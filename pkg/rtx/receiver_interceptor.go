@@ -0,0 +1,268 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package rtx implements an interceptor unwrapping RFC 4588 retransmissions into their original stream.
+package rtx
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"github.com/pion/rtp"
+)
+
+// osnLength is the length of the original sequence number which prefixes the RTX payload.
+const osnLength = 2
+
+// maxRepairedPackets is the number of unwrapped retransmissions kept until the
+// media stream is read. Older ones are dropped when the media stream stalls.
+const maxRepairedPackets = 128
+
+var errNoOriginalSequenceNumber = errors.New("rtx packet without original sequence number")
+
+// streamState holds the unwrapped retransmissions of a single media stream.
+type streamState struct {
+	mu          sync.Mutex
+	mediaSSRC   uint32
+	payloadType uint8
+	rtxSSRC     uint32
+	repaired    []*rtp.Packet
+}
+
+// push queues a repaired packet, and returns whether the oldest one was dropped
+// to make room for it.
+func (s *streamState) push(packet *rtp.Packet) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dropped := len(s.repaired) >= maxRepairedPackets
+	if dropped {
+		s.repaired[0] = nil
+		s.repaired = s.repaired[1:]
+	}
+	s.repaired = append(s.repaired, packet)
+
+	return dropped
+}
+
+func (s *streamState) pop() *rtp.Packet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.repaired) == 0 {
+		return nil
+	}
+
+	packet := s.repaired[0]
+	s.repaired = s.repaired[1:]
+
+	return packet
+}
+
+// unwrap restores the original packet from an RTX packet as described in
+// https://datatracker.ietf.org/doc/html/rfc4588#section-4.
+func (s *streamState) unwrap(buf []byte) (*rtp.Packet, error) {
+	packet := &rtp.Packet{}
+	if err := packet.Unmarshal(buf); err != nil {
+		return nil, err
+	}
+
+	// Padding only packets are used for probing and carry no retransmission.
+	if len(packet.Payload) < osnLength {
+		return nil, errNoOriginalSequenceNumber
+	}
+
+	packet.SequenceNumber = binary.BigEndian.Uint16(packet.Payload)
+	packet.SSRC = s.mediaSSRC
+	packet.PayloadType = s.payloadType
+	packet.Payload = packet.Payload[osnLength:]
+
+	return packet.Clone(), nil
+}
+
+// ReceiverInterceptorFactory is a interceptor.Factory for a ReceiverInterceptor.
+type ReceiverInterceptorFactory struct {
+	opts []ReceiverOption
+}
+
+// NewReceiverInterceptor returns a new ReceiverInterceptorFactory.
+func NewReceiverInterceptor(opts ...ReceiverOption) (*ReceiverInterceptorFactory, error) {
+	return &ReceiverInterceptorFactory{opts: opts}, nil
+}
+
+// NewInterceptor constructs a new ReceiverInterceptor.
+func (r *ReceiverInterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	receiverInterceptor := &ReceiverInterceptor{
+		streams:    map[uint32]*streamState{},
+		rtxStreams: map[uint32]*streamState{},
+	}
+
+	for _, opt := range r.opts {
+		if err := opt(receiverInterceptor); err != nil {
+			return nil, err
+		}
+	}
+
+	if receiverInterceptor.loggerFactory == nil {
+		receiverInterceptor.loggerFactory = logging.NewDefaultLoggerFactory()
+	}
+	receiverInterceptor.log = receiverInterceptor.loggerFactory.NewLogger("rtx_receiver")
+
+	return receiverInterceptor, nil
+}
+
+// ReceiverInterceptor unwraps RTX packets into the media stream they retransmit.
+//
+// A media stream is repaired when its StreamInfo carries both
+// SSRCRetransmission and PayloadTypeRetransmission. The RTX stream is expected
+// to be bound after the media stream it repairs, which is the order pion/webrtc
+// binds them in.
+//
+// The original SSRC, payload type and sequence number are restored and the
+// packet is returned by the media stream reader on its following reads, with
+// interceptor.RetransmittedAttributesKey set in its Attributes. Up to 128
+// packets are kept until the media stream is read, the oldest ones are dropped
+// first. The RTX stream reader still returns the RTX packets unchanged.
+type ReceiverInterceptor struct {
+	interceptor.NoOp
+	mu            sync.Mutex
+	streams       map[uint32]*streamState
+	rtxStreams    map[uint32]*streamState
+	log           logging.LeveledLogger
+	loggerFactory logging.LoggerFactory
+}
+
+// BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream.
+// The returned method will be called once per rtp packet.
+func (r *ReceiverInterceptor) BindRemoteStream(
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stream, ok := r.rtxStreams[info.SSRC]; ok {
+		return r.bindRTXStream(stream, reader)
+	}
+
+	if info.SSRCRetransmission == 0 || info.PayloadTypeRetransmission == 0 {
+		return reader
+	}
+
+	stream := &streamState{
+		mediaSSRC:   info.SSRC,
+		payloadType: info.PayloadType,
+		rtxSSRC:     info.SSRCRetransmission,
+	}
+	r.streams[info.SSRC] = stream
+	r.rtxStreams[info.SSRCRetransmission] = stream
+
+	return r.bindMediaStream(stream, reader)
+}
+
+func (r *ReceiverInterceptor) bindMediaStream(
+	stream *streamState, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		for {
+			packet := stream.pop()
+			if packet == nil {
+				n, attr, err := reader.Read(b, a)
+				if err != nil {
+					return n, attr, err
+				}
+
+				if attr == nil {
+					attr = make(interceptor.Attributes)
+				}
+				header, err := attr.GetRTPHeader(b[:n])
+				if err != nil {
+					return 0, nil, err
+				}
+
+				if header.SSRC != stream.rtxSSRC {
+					return n, attr, nil
+				}
+
+				// RTX packets multiplexed on the media stream are unwrapped in place.
+				if packet, err = stream.unwrap(b[:n]); err != nil {
+					r.log.Debugf("dropping rtx packet: %v", err)
+
+					continue
+				}
+			}
+
+			n, err := packet.MarshalTo(b)
+			if err != nil {
+				return 0, nil, err
+			}
+
+			// Use fresh attributes, the ones passed in may cache the header of another packet.
+			attr := make(interceptor.Attributes)
+			attr.Set(interceptor.RetransmittedAttributesKey, true)
+
+			return n, attr, nil
+		}
+	})
+}
+
+func (r *ReceiverInterceptor) bindRTXStream(
+	stream *streamState, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		n, attr, err := reader.Read(b, a)
+		if err != nil {
+			return n, attr, err
+		}
+
+		if attr == nil {
+			attr = make(interceptor.Attributes)
+		}
+		header, err := attr.GetRTPHeader(b[:n])
+		if err != nil {
+			return 0, nil, err
+		}
+		if header.SSRC != stream.rtxSSRC {
+			return n, attr, nil
+		}
+
+		packet, err := stream.unwrap(b[:n])
+		if err != nil {
+			r.log.Debugf("dropping rtx packet: %v", err)
+
+			return n, attr, nil
+		}
+		if stream.push(packet) {
+			r.log.Debugf("dropping oldest rtx packet of ssrc %d, the media stream is not read", stream.mediaSSRC)
+		}
+
+		return n, attr, nil
+	})
+}
+
+// UnbindRemoteStream is called when the Stream is removed. It can be used to clean up any data related to that track.
+func (r *ReceiverInterceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stream, ok := r.streams[info.SSRC]
+	if !ok {
+		return
+	}
+
+	delete(r.streams, info.SSRC)
+	delete(r.rtxStreams, stream.rtxSSRC)
+}
+
+// Close closes the interceptor.
+func (r *ReceiverInterceptor) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.streams = map[uint32]*streamState{}
+	r.rtxStreams = map[uint32]*streamState{}
+
+	return nil
+}
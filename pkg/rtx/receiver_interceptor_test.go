@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtx
+
+import (
+	"io"
+	"testing"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/rtpbuffer"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	mediaSSRC = uint32(1234)
+	rtxSSRC   = uint32(5678)
+	mediaPT   = uint8(96)
+	rtxPT     = uint8(97)
+)
+
+// packetQueueReader is an RTPReader returning the queued packets, then io.EOF.
+type packetQueueReader struct {
+	packets []*rtp.Packet
+}
+
+func (q *packetQueueReader) Read(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+	if len(q.packets) == 0 {
+		return 0, nil, io.EOF
+	}
+
+	packet := q.packets[0]
+	q.packets = q.packets[1:]
+	n, err := packet.MarshalTo(b)
+
+	return n, a, err
+}
+
+func mediaPacket(seq uint16) *rtp.Packet {
+	return &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    mediaPT,
+			SequenceNumber: seq,
+			Timestamp:      90000,
+			SSRC:           mediaSSRC,
+		},
+		Payload: []byte{0x01, 0x02, 0x03, byte(seq)},
+	}
+}
+
+func rtxPacket(t *testing.T, factory rtpbuffer.PacketFactory, seq uint16) *rtp.Packet {
+	t.Helper()
+
+	original := mediaPacket(seq)
+	packet, err := factory.NewPacket(&original.Header, original.Payload, rtxSSRC, rtxPT)
+	require.NoError(t, err)
+	defer packet.Release()
+
+	return (&rtp.Packet{Header: *packet.Header(), Payload: packet.Payload()}).Clone()
+}
+
+func readPacket(t *testing.T, reader interceptor.RTPReader) (*rtp.Packet, interceptor.Attributes) {
+	t.Helper()
+
+	buf := make([]byte, 1500)
+	n, attr, err := reader.Read(buf, nil)
+	require.NoError(t, err)
+
+	packet := &rtp.Packet{}
+	require.NoError(t, packet.Unmarshal(buf[:n]))
+
+	return packet, attr
+}
+
+func newTestInterceptor(t *testing.T) interceptor.Interceptor {
+	t.Helper()
+
+	factory, err := NewReceiverInterceptor()
+	require.NoError(t, err)
+
+	i, err := factory.NewInterceptor("")
+	require.NoError(t, err)
+
+	return i
+}
+
+var mediaInfo = &interceptor.StreamInfo{ //nolint:gochecknoglobals
+	SSRC:                      mediaSSRC,
+	PayloadType:               mediaPT,
+	SSRCRetransmission:        rtxSSRC,
+	PayloadTypeRetransmission: rtxPT,
+}
+
+func TestReceiverInterceptor_UnwrapsRTXStream(t *testing.T) {
+	i := newTestInterceptor(t)
+	factory := rtpbuffer.NewPacketFactoryCopy()
+
+	mediaReader := i.BindRemoteStream(mediaInfo, &packetQueueReader{
+		packets: []*rtp.Packet{mediaPacket(10), mediaPacket(12)},
+	})
+	rtxReader := i.BindRemoteStream(&interceptor.StreamInfo{SSRC: rtxSSRC}, &packetQueueReader{
+		packets: []*rtp.Packet{
+			rtxPacket(t, factory, 11),
+			// Padding only probe.
+			{Header: rtp.Header{Version: 2, SSRC: rtxSSRC, PayloadType: rtxPT, Padding: true, PaddingSize: 200}},
+		},
+	})
+
+	for _, seq := range []uint16{10, 12} {
+		packet, attr := readPacket(t, mediaReader)
+		assert.Equal(t, seq, packet.SequenceNumber)
+		assert.Nil(t, attr.Get(interceptor.RetransmittedAttributesKey))
+	}
+
+	// The RTX stream reader returns the RTX packets unchanged.
+	for range 2 {
+		packet, _ := readPacket(t, rtxReader)
+		assert.Equal(t, rtxSSRC, packet.SSRC)
+		assert.Equal(t, rtxPT, packet.PayloadType)
+	}
+
+	packet, attr := readPacket(t, mediaReader)
+	assert.Equal(t, mediaPacket(11), packet)
+	assert.Equal(t, true, attr.Get(interceptor.RetransmittedAttributesKey))
+
+	_, _, err := mediaReader.Read(make([]byte, 1500), nil)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.NoError(t, i.Close())
+}
+
+func TestReceiverInterceptor_UnwrapsRTXOnMediaStream(t *testing.T) {
+	i := newTestInterceptor(t)
+	factory := rtpbuffer.NewPacketFactoryCopy()
+
+	mediaReader := i.BindRemoteStream(mediaInfo, &packetQueueReader{
+		packets: []*rtp.Packet{mediaPacket(10), rtxPacket(t, factory, 9), mediaPacket(11)},
+	})
+
+	packet, _ := readPacket(t, mediaReader)
+	assert.Equal(t, uint16(10), packet.SequenceNumber)
+
+	packet, attr := readPacket(t, mediaReader)
+	assert.Equal(t, mediaPacket(9), packet)
+	assert.Equal(t, true, attr.Get(interceptor.RetransmittedAttributesKey))
+
+	packet, attr = readPacket(t, mediaReader)
+	assert.Equal(t, uint16(11), packet.SequenceNumber)
+	assert.Nil(t, attr.Get(interceptor.RetransmittedAttributesKey))
+}
+
+func TestReceiverInterceptor_DropsOldestRepairedPackets(t *testing.T) {
+	i := newTestInterceptor(t)
+	factory := rtpbuffer.NewPacketFactoryCopy()
+
+	// The media stream stalls while retransmissions keep arriving.
+	rtxPackets := make([]*rtp.Packet, 0, maxRepairedPackets+2)
+	for seq := range uint16(maxRepairedPackets + 2) {
+		rtxPackets = append(rtxPackets, rtxPacket(t, factory, seq))
+	}
+	mediaReader := i.BindRemoteStream(mediaInfo, &packetQueueReader{})
+	rtxReader := i.BindRemoteStream(&interceptor.StreamInfo{SSRC: rtxSSRC}, &packetQueueReader{packets: rtxPackets})
+	for range rtxPackets {
+		readPacket(t, rtxReader)
+	}
+
+	for seq := range uint16(maxRepairedPackets) {
+		packet, _ := readPacket(t, mediaReader)
+		assert.Equal(t, seq+2, packet.SequenceNumber)
+	}
+	_, _, err := mediaReader.Read(make([]byte, 1500), nil)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestReceiverInterceptor_StreamsWithoutRTX(t *testing.T) {
+	i := newTestInterceptor(t)
+
+	queue := &packetQueueReader{}
+	assert.Equal(t, interceptor.RTPReader(queue), i.BindRemoteStream(&interceptor.StreamInfo{SSRC: mediaSSRC}, queue))
+
+	// Once the media stream is unbound, its RTX stream is not unwrapped anymore.
+	i.BindRemoteStream(mediaInfo, queue)
+	i.UnbindRemoteStream(mediaInfo)
+	assert.Equal(t, interceptor.RTPReader(queue), i.BindRemoteStream(&interceptor.StreamInfo{SSRC: rtxSSRC}, queue))
+}
@@ -0,0 +1,226 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package stats
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultEventHistoryInterval = time.Second
+	defaultEventHistorySize     = 60
+)
+
+var errInvalidRule = errors.New(
+	"stats events need a handler, and rules with a name, a condition and a non negative duration",
+)
+
+// Event is passed to the EventHandler when a Rule started or stopped holding
+// for a stream.
+type Event struct {
+	// Rule is the name of the Rule.
+	Rule             string
+	PeerConnectionID string
+	SSRC             uint32
+	Direction        Direction
+	// Timestamp is the time of the Snapshot the Rule was evaluated on.
+	Timestamp time.Time
+	// Values are the values the Condition of the Rule returned.
+	Values map[string]float64
+	// Resolved is set once the Rule stopped holding after an Event was fired,
+	// or the History of the stream was dropped, in which case Values is nil.
+	Resolved bool
+}
+
+// EventHandler is called with the Events of the streams of a PeerConnection.
+// It is called from the goroutine taking the Snapshots of the History, or the
+// one unbinding a stream or closing the Interceptor, one Event at a time. It
+// should not block, nor unbind streams or close the Interceptor.
+type EventHandler func(Event)
+
+// Condition returns whether a condition holds on the History of a stream, and
+// the values it was evaluated on.
+type Condition func(history *History) (values map[string]float64, ok bool)
+
+// Rule fires an Event once its Condition held for the duration For, and
+// another one, Resolved, once it stopped holding or the stream is unbound.
+// Conditions are only evaluated when a Snapshot is taken, so an Event fires up
+// to one history interval, a second by default, after For elapsed.
+type Rule struct {
+	Name      string
+	Condition Condition
+	For       time.Duration
+}
+
+// ruleState is the state of a Rule for a stream.
+type ruleState struct {
+	since  time.Time
+	firing bool
+}
+
+// WithEventHandler evaluates the rules on the History of every bound stream
+// each time a Snapshot is taken, and passes their Events to handler. The
+// History is taken every second, and holds a minute, unless set with
+// WithHistory.
+func WithEventHandler(handler EventHandler, rules ...Rule) Option {
+	return func(i *Interceptor) error {
+		if handler == nil {
+			return errInvalidRule
+		}
+		for _, rule := range rules {
+			if rule.Name == "" || rule.Condition == nil || rule.For < 0 {
+				return errInvalidRule
+			}
+		}
+		i.eventHandler = handler
+		i.rules = rules
+
+		return nil
+	}
+}
+
+// evaluate evaluates the rules on the History of a stream, and returns the
+// Events they fired.
+func (b *historyBuffer) evaluate(rules []Rule, ssrc uint32, id string) []Event {
+	if len(b.rules) != len(rules) {
+		b.rules = make([]ruleState, len(rules))
+	}
+	history := b.history()
+	now := history.Snapshots[len(history.Snapshots)-1].Timestamp
+
+	var events []Event
+	for i, rule := range rules {
+		state := &b.rules[i]
+		values, ok := rule.Condition(history)
+		switch {
+		case ok && state.since.IsZero():
+			state.since = now
+		case !ok:
+			state.since = time.Time{}
+		}
+
+		if ok == state.firing || ok && now.Sub(state.since) < rule.For {
+			continue
+		}
+		state.firing = ok
+		events = append(events, Event{
+			Rule:             rule.Name,
+			PeerConnectionID: id,
+			SSRC:             ssrc,
+			Direction:        b.direction,
+			Timestamp:        now,
+			Values:           values,
+			Resolved:         !ok,
+		})
+	}
+
+	return events
+}
+
+// resolve returns the Resolved Events of the rules firing for a stream whose
+// History is dropped at now.
+func (b *historyBuffer) resolve(rules []Rule, ssrc uint32, id string, now time.Time) []Event {
+	var events []Event
+	for i, state := range b.rules {
+		if !state.firing || i >= len(rules) {
+			continue
+		}
+		events = append(events, Event{
+			Rule:             rules[i].Name,
+			PeerConnectionID: id,
+			SSRC:             ssrc,
+			Direction:        b.direction,
+			Timestamp:        now,
+			Resolved:         true,
+		})
+	}
+	b.rules = nil
+
+	return events
+}
+
+// last returns the Stats of the last Snapshot.
+func (h *History) last() *Stats {
+	if len(h.Snapshots) == 0 {
+		return &Stats{}
+	}
+
+	return &h.Snapshots[len(h.Snapshots)-1].Stats
+}
+
+// PacketLossAbove holds when more than fraction of the packets of a stream
+// were lost over the window, see History.LossFraction. Its value is
+// loss_fraction.
+func PacketLossAbove(fraction float64, window time.Duration) Condition {
+	return func(history *History) (map[string]float64, bool) {
+		loss := history.LossFraction(window)
+
+		return map[string]float64{"loss_fraction": loss}, loss > fraction
+	}
+}
+
+// StreamStalled holds when no RTP packet of a remote stream was received for
+// longer than timeout, after the first one. Its value is
+// seconds_since_last_packet. As it is evaluated on the Snapshots, a stall is
+// only detected at the granularity of the history interval, a second by
+// default.
+func StreamStalled(timeout time.Duration) Condition {
+	return func(history *History) (map[string]float64, bool) {
+		if history.Direction != DirectionInbound || len(history.Snapshots) == 0 {
+			return nil, false
+		}
+		last := history.last().LastPacketReceivedTimestamp
+		if last.IsZero() {
+			return nil, false
+		}
+		since := history.Snapshots[len(history.Snapshots)-1].Timestamp.Sub(last)
+
+		return map[string]float64{"seconds_since_last_packet": since.Seconds()}, since > timeout
+	}
+}
+
+// RTTAbove holds when the last round trip time measured is above threshold.
+// Its value is rtt_seconds.
+func RTTAbove(threshold time.Duration) Condition {
+	return func(history *History) (map[string]float64, bool) {
+		rtt := history.last().RemoteOutboundRTPStreamStats.RoundTripTime
+		if history.Direction == DirectionOutbound {
+			rtt = history.last().RemoteInboundRTPStreamStats.RoundTripTime
+		}
+
+		return map[string]float64{"rtt_seconds": rtt.Seconds()}, rtt > threshold
+	}
+}
+
+// JitterAbove holds when the last jitter of a stream is above threshold. The
+// jitter of the outbound streams is the one reported by the remote peer. Its
+// value is jitter_seconds.
+func JitterAbove(threshold time.Duration) Condition {
+	return func(history *History) (map[string]float64, bool) {
+		jitter := history.last().InboundRTPStreamStats.Jitter
+		if history.Direction == DirectionOutbound {
+			jitter = history.last().RemoteInboundRTPStreamStats.Jitter
+		}
+
+		return map[string]float64{"jitter_seconds": jitter}, jitter > threshold.Seconds()
+	}
+}
+
+// PLIRateAbove holds when more than perSecond PLI packets per second were
+// sent, for the remote streams, or received, for the local streams, over the
+// window. Its value is pli_per_second.
+func PLIRateAbove(perSecond float64, window time.Duration) Condition {
+	return func(history *History) (map[string]float64, bool) {
+		rate := history.rate(window, func(s *Stats) float64 {
+			if history.Direction == DirectionOutbound {
+				return float64(s.OutboundRTPStreamStats.PLICount)
+			}
+
+			return float64(s.InboundRTPStreamStats.PLICount)
+		})
+
+		return map[string]float64{"pli_per_second": rate}, rate > perSecond
+	}
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryBufferEvaluate(t *testing.T) {
+	rules := []Rule{{
+		Name:      "loss",
+		Condition: PacketLossAbove(0.05, time.Second),
+		For:       2 * time.Second,
+	}}
+	buffer := &historyBuffer{direction: DirectionInbound}
+	evaluate := func(seconds int, packets uint64, lost int64) []Event {
+		buffer.add(inboundSnapshot(seconds, packets, 0, lost, 0), 10)
+
+		return buffer.evaluate(rules, 1, "pc")
+	}
+
+	assert.Empty(t, evaluate(0, 0, 0))
+	// The loss has to hold for two seconds.
+	assert.Empty(t, evaluate(1, 90, 10))
+	assert.Empty(t, evaluate(2, 180, 20))
+	assert.Equal(t, []Event{{
+		Rule:             "loss",
+		PeerConnectionID: "pc",
+		SSRC:             1,
+		Direction:        DirectionInbound,
+		Timestamp:        time.Unix(3, 0),
+		Values:           map[string]float64{"loss_fraction": 0.1},
+		Resolved:         false,
+	}}, evaluate(3, 270, 30))
+	// The Event is fired once.
+	assert.Empty(t, evaluate(4, 360, 40))
+
+	events := evaluate(5, 460, 40)
+	assert.Len(t, events, 1)
+	assert.True(t, events[0].Resolved)
+	assert.Empty(t, evaluate(6, 560, 40))
+
+	// Dropping the History resolves the firing rules only.
+	assert.Empty(t, buffer.resolve(rules, 1, "pc", time.Unix(7, 0)))
+	for seconds := 7; seconds <= 10; seconds++ {
+		evaluate(seconds, 560+90*uint64(seconds-6), 40+10*int64(seconds-6)) //nolint:gosec // G115
+	}
+	assert.Equal(t, []Event{{
+		Rule:             "loss",
+		PeerConnectionID: "pc",
+		SSRC:             1,
+		Direction:        DirectionInbound,
+		Timestamp:        time.Unix(11, 0),
+		Resolved:         true,
+	}}, buffer.resolve(rules, 1, "pc", time.Unix(11, 0)))
+}
+
+func TestConditions(t *testing.T) {
+	t.Run("stream stalled", func(t *testing.T) {
+		history := &History{Direction: DirectionInbound, Snapshots: []Snapshot{inboundSnapshot(10, 0, 0, 0, 0)}}
+		_, ok := StreamStalled(time.Second)(history)
+		assert.False(t, ok, "no packet received yet")
+
+		history.Snapshots[0].Stats.LastPacketReceivedTimestamp = time.Unix(8, 0)
+		values, ok := StreamStalled(time.Second)(history)
+		assert.True(t, ok)
+		assert.Equal(t, map[string]float64{"seconds_since_last_packet": 2}, values)
+		_, ok = StreamStalled(3 * time.Second)(history)
+		assert.False(t, ok)
+	})
+
+	t.Run("rtt and jitter", func(t *testing.T) {
+		snapshot := Snapshot{Timestamp: time.Unix(0, 0)}
+		snapshot.Stats.RemoteInboundRTPStreamStats.RoundTripTime = 300 * time.Millisecond
+		snapshot.Stats.RemoteInboundRTPStreamStats.Jitter = 0.05
+		history := &History{Direction: DirectionOutbound, Snapshots: []Snapshot{snapshot}}
+
+		values, ok := RTTAbove(200 * time.Millisecond)(history)
+		assert.True(t, ok)
+		assert.Equal(t, map[string]float64{"rtt_seconds": 0.3}, values)
+		_, ok = RTTAbove(time.Second)(history)
+		assert.False(t, ok)
+
+		values, ok = JitterAbove(30 * time.Millisecond)(history)
+		assert.True(t, ok)
+		assert.Equal(t, map[string]float64{"jitter_seconds": 0.05}, values)
+
+		// The inbound streams take the values measured locally.
+		history.Direction = DirectionInbound
+		_, ok = RTTAbove(200 * time.Millisecond)(history)
+		assert.False(t, ok)
+		_, ok = JitterAbove(30 * time.Millisecond)(history)
+		assert.False(t, ok)
+	})
+
+	t.Run("pli storm", func(t *testing.T) {
+		history := &History{Direction: DirectionOutbound, Snapshots: make([]Snapshot, 3)}
+		for i := range history.Snapshots {
+			history.Snapshots[i].Timestamp = time.Unix(int64(i), 0)
+			history.Snapshots[i].Stats.OutboundRTPStreamStats.PLICount = uint32(i * 5) //nolint:gosec // G115
+		}
+
+		values, ok := PLIRateAbove(2, 2*time.Second)(history)
+		assert.True(t, ok)
+		assert.Equal(t, map[string]float64{"pli_per_second": 5}, values)
+		_, ok = PLIRateAbove(10, 2*time.Second)(history)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,375 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package exporter exports the statistics of the stats interceptor, and of the
+// bandwidth estimators of the cc interceptor, as metrics of a metrics library
+// like Prometheus or OpenTelemetry.
+//
+// The package does not depend on a metrics library, the application adapts
+// its library to the Registry interface. With Prometheus, a Registry keeps a
+// CounterVec or a GaugeVec per Desc, created with the Name, Help and
+// LabelNames of the Desc, and returns the metric of their With method:
+//
+//	func (r *registry) Counter(desc *exporter.Desc, labels exporter.Labels) exporter.Counter {
+//		return r.counterVec(desc).With(prometheus.Labels(labels))
+//	}
+//
+// The Exporter is added to the factories of the interceptors:
+//
+//	e, _ := exporter.NewExporter(registry)
+//	statsFactory.OnNewPeerConnection(e.AddPeerConnection)
+//	ccFactory.OnNewPeerConnection(func(id string, bwe cc.BandwidthEstimator) {
+//		e.AddBandwidthEstimator(id, bwe)
+//	})
+package exporter
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/stats"
+)
+
+const defaultInterval = time.Second
+
+// BandwidthEstimator is the part of the cc.BandwidthEstimator, implemented by
+// gcc.SendSideBWE and nada.SendSideBWE, the Exporter uses.
+type BandwidthEstimator interface {
+	GetTargetBitrate() int
+	GetStats() map[string]any
+}
+
+// exportedMetric is a metric registered with its label values.
+type exportedMetric struct {
+	desc    *Desc
+	labels  Labels
+	counter Counter
+	gauge   Gauge
+	last    float64
+}
+
+// set updates the metric to value. Counters are increased by the difference to
+// the last value, a decrease is taken as a reset of the statistic.
+func (m *exportedMetric) set(value float64) {
+	if m.gauge != nil {
+		m.gauge.Set(value)
+
+		return
+	}
+	if delta := value - m.last; delta > 0 {
+		m.counter.Add(delta)
+	}
+	m.last = value
+}
+
+type exportedStream struct {
+	stream  stats.Stream
+	metrics []*exportedMetric
+}
+
+type peerConnection struct {
+	getter     stats.Getter
+	bwe        BandwidthEstimator
+	streams    map[uint32]*exportedStream
+	bweMetrics map[string]*exportedMetric
+}
+
+// Exporter exports the statistics of PeerConnections to a Registry. The
+// metrics are updated on an interval, for every stream listed by the
+// stats.Getter of a PeerConnection, and for its bandwidth estimator. The
+// metrics of a stream are unregistered once it is unbound, all the metrics of
+// a PeerConnection when it is removed or the Exporter closed. A PeerConnection
+// whose stats.Getter implements stats.ClosedNotifier, as the one of the stats
+// interceptor does, is removed once its stats interceptor is closed.
+type Exporter struct {
+	registry Registry
+	interval time.Duration
+	clock    interceptor.Clock
+
+	lock            sync.Mutex
+	peerConnections map[string]*peerConnection
+	bweDescs        map[string]*Desc
+
+	close chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewExporter returns a new Exporter exporting to registry. It updates the
+// metrics until it is closed.
+func NewExporter(registry Registry, opts ...Option) (*Exporter, error) {
+	exporter := &Exporter{
+		registry:        registry,
+		interval:        defaultInterval,
+		clock:           interceptor.SystemClock(),
+		peerConnections: map[string]*peerConnection{},
+		bweDescs:        map[string]*Desc{},
+		close:           make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if err := opt(exporter); err != nil {
+			return nil, err
+		}
+	}
+
+	exporter.wg.Add(1)
+	go exporter.loop()
+
+	return exporter, nil
+}
+
+func (e *Exporter) loop() {
+	defer e.wg.Done()
+
+	ticker := e.clock.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.Ch():
+			e.Update()
+		case <-e.close:
+			return
+		}
+	}
+}
+
+// peerConnection returns the PeerConnection with id, added if new. It returns
+// nil once the Exporter is closed.
+func (e *Exporter) peerConnection(id string) *peerConnection {
+	if e.isClosed() {
+		return nil
+	}
+	pc, ok := e.peerConnections[id]
+	if !ok {
+		pc = &peerConnection{
+			streams:    map[uint32]*exportedStream{},
+			bweMetrics: map[string]*exportedMetric{},
+		}
+		e.peerConnections[id] = pc
+	}
+
+	return pc
+}
+
+// AddPeerConnection exports the stats of the streams of the PeerConnection
+// with id. It is a stats.NewPeerConnectionCallback. Only the streams of a
+// getter implementing stats.StreamLister, as the one of the stats interceptor
+// does, are exported.
+func (e *Exporter) AddPeerConnection(id string, getter stats.Getter) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	pc := e.peerConnection(id)
+	if pc == nil {
+		return
+	}
+	pc.getter = getter
+	if notifier, ok := getter.(stats.ClosedNotifier); ok {
+		e.wg.Add(1)
+		go e.removeOnClose(id, getter, notifier.Closed())
+	}
+}
+
+// removeOnClose removes the PeerConnection with id once closed is closed,
+// unless its getter was replaced in the meantime.
+func (e *Exporter) removeOnClose(id string, getter stats.Getter, closed <-chan struct{}) {
+	defer e.wg.Done()
+
+	select {
+	case <-closed:
+	case <-e.close:
+		return
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if pc, ok := e.peerConnections[id]; ok && pc.getter == getter {
+		e.unregisterPeerConnection(pc)
+		delete(e.peerConnections, id)
+	}
+}
+
+// AddBandwidthEstimator exports the target bitrate and the numeric GetStats
+// values of the bandwidth estimator of the PeerConnection with id.
+func (e *Exporter) AddBandwidthEstimator(id string, bwe BandwidthEstimator) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if pc := e.peerConnection(id); pc != nil {
+		pc.bwe = bwe
+	}
+}
+
+// RemovePeerConnection stops exporting the PeerConnection with id, and
+// unregisters its metrics.
+func (e *Exporter) RemovePeerConnection(id string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if pc, ok := e.peerConnections[id]; ok {
+		e.unregisterPeerConnection(pc)
+		delete(e.peerConnections, id)
+	}
+}
+
+// Update updates the metrics of all PeerConnections. It is called on every
+// interval, and may be called in between, before the metrics are collected.
+func (e *Exporter) Update() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for id, pc := range e.peerConnections {
+		e.updateStreams(id, pc)
+		e.updateBandwidthEstimator(id, pc)
+	}
+}
+
+func (e *Exporter) updateStreams(id string, pc *peerConnection) {
+	var streams []stats.Stream
+	if lister, ok := pc.getter.(stats.StreamLister); ok {
+		streams = lister.Streams()
+	}
+
+	bound := map[uint32]bool{}
+	for _, stream := range streams {
+		s := pc.getter.Get(stream.SSRC)
+		if s == nil {
+			continue
+		}
+		bound[stream.SSRC] = true
+
+		exported, ok := pc.streams[stream.SSRC]
+		if ok && exported.stream != stream {
+			// The SSRC was bound again, with another direction or codec.
+			e.unregister(exported.metrics)
+			ok = false
+		}
+		if !ok {
+			exported = e.registerStream(id, stream)
+			pc.streams[stream.SSRC] = exported
+		}
+
+		for i, metric := range streamMetrics(stream.Direction) {
+			exported.metrics[i].set(metric.value(s))
+		}
+	}
+
+	for ssrc, exported := range pc.streams {
+		if !bound[ssrc] {
+			e.unregister(exported.metrics)
+			delete(pc.streams, ssrc)
+		}
+	}
+}
+
+func (e *Exporter) registerStream(id string, stream stats.Stream) *exportedStream {
+	labels := Labels{
+		LabelPeerConnectionID: id,
+		LabelSSRC:             strconv.FormatUint(uint64(stream.SSRC), 10),
+		LabelDirection:        stream.Direction.String(),
+		LabelMimeType:         stream.MimeType,
+	}
+	metrics := streamMetrics(stream.Direction)
+	exported := &exportedStream{stream: stream, metrics: make([]*exportedMetric, 0, len(metrics))}
+	for _, metric := range metrics {
+		exported.metrics = append(exported.metrics, e.register(metric.desc, labels))
+	}
+
+	return exported
+}
+
+func (e *Exporter) updateBandwidthEstimator(id string, pc *peerConnection) {
+	if pc.bwe == nil {
+		return
+	}
+	labels := Labels{LabelPeerConnectionID: id}
+
+	e.bweMetric(pc, labels, bweTargetBitrate).set(float64(pc.bwe.GetTargetBitrate()))
+
+	values := pc.bwe.GetStats()
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		value, ok := bweValue(values[key])
+		if !ok {
+			continue
+		}
+		desc, ok := e.bweDescs[key]
+		if !ok {
+			desc = &Desc{
+				Name:       bweMetricName(key),
+				Help:       "Value of " + key + " in the statistics of the bandwidth estimator.",
+				LabelNames: bweLabelNames,
+			}
+			e.bweDescs[key] = desc
+		}
+		e.bweMetric(pc, labels, desc).set(value)
+	}
+}
+
+func (e *Exporter) bweMetric(pc *peerConnection, labels Labels, desc *Desc) *exportedMetric {
+	metric, ok := pc.bweMetrics[desc.Name]
+	if !ok {
+		metric = e.register(desc, labels)
+		pc.bweMetrics[desc.Name] = metric
+	}
+
+	return metric
+}
+
+func (e *Exporter) register(desc *Desc, labels Labels) *exportedMetric {
+	metric := &exportedMetric{desc: desc, labels: labels}
+	if desc.Counter {
+		metric.counter = e.registry.Counter(desc, labels)
+	} else {
+		metric.gauge = e.registry.Gauge(desc, labels)
+	}
+
+	return metric
+}
+
+func (e *Exporter) unregister(metrics []*exportedMetric) {
+	for _, metric := range metrics {
+		e.registry.Unregister(metric.desc, metric.labels)
+	}
+}
+
+func (e *Exporter) unregisterPeerConnection(pc *peerConnection) {
+	for _, exported := range pc.streams {
+		e.unregister(exported.metrics)
+	}
+	e.unregister(slices.Collect(maps.Values(pc.bweMetrics)))
+}
+
+func (e *Exporter) isClosed() bool {
+	select {
+	case <-e.close:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops updating the metrics, and unregisters the metrics of all
+// PeerConnections.
+func (e *Exporter) Close() error {
+	e.lock.Lock()
+	if e.isClosed() {
+		e.lock.Unlock()
+
+		return nil
+	}
+	close(e.close)
+	for id, pc := range e.peerConnections {
+		e.unregisterPeerConnection(pc)
+		delete(e.peerConnections, id)
+	}
+	e.lock.Unlock()
+
+	e.wg.Wait()
+
+	return nil
+}
@@ -0,0 +1,303 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor/pkg/netem"
+	"github.com/pion/interceptor/pkg/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockRegistry keeps the values of the metrics by name and label values.
+type mockRegistry struct {
+	lock   sync.Mutex
+	values map[string]float64
+}
+
+func newMockRegistry() *mockRegistry {
+	return &mockRegistry{values: map[string]float64{}}
+}
+
+func metricKey(desc *Desc, labels Labels) string {
+	key := desc.Name
+	for _, name := range desc.LabelNames {
+		key += fmt.Sprintf(",%s=%s", name, labels[name])
+	}
+
+	return key
+}
+
+type mockMetric struct {
+	registry *mockRegistry
+	key      string
+}
+
+func (m *mockMetric) Add(delta float64) {
+	m.registry.lock.Lock()
+	defer m.registry.lock.Unlock()
+
+	m.registry.values[m.key] += delta
+}
+
+func (m *mockMetric) Set(value float64) {
+	m.registry.lock.Lock()
+	defer m.registry.lock.Unlock()
+
+	m.registry.values[m.key] = value
+}
+
+func (r *mockRegistry) metric(desc *Desc, labels Labels) *mockMetric {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := metricKey(desc, labels)
+	r.values[key] = 0
+
+	return &mockMetric{registry: r, key: key}
+}
+
+func (r *mockRegistry) Counter(desc *Desc, labels Labels) Counter {
+	return r.metric(desc, labels)
+}
+
+func (r *mockRegistry) Gauge(desc *Desc, labels Labels) Gauge {
+	return r.metric(desc, labels)
+}
+
+func (r *mockRegistry) Unregister(desc *Desc, labels Labels) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.values, metricKey(desc, labels))
+}
+
+func (r *mockRegistry) get(key string) (float64, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	value, ok := r.values[key]
+
+	return value, ok
+}
+
+func (r *mockRegistry) len() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return len(r.values)
+}
+
+type mockGetter struct {
+	streams []stats.Stream
+	stats   map[uint32]*stats.Stats
+}
+
+func (g *mockGetter) Get(ssrc uint32) *stats.Stats {
+	return g.stats[ssrc]
+}
+
+func (g *mockGetter) Streams() []stats.Stream {
+	return g.streams
+}
+
+type mockClosedGetter struct {
+	mockGetter
+	closed chan struct{}
+}
+
+func (g *mockClosedGetter) Closed() <-chan struct{} {
+	return g.closed
+}
+
+type mockBandwidthEstimator struct{}
+
+func (mockBandwidthEstimator) GetTargetBitrate() int {
+	return 1_000_000
+}
+
+func (mockBandwidthEstimator) GetStats() map[string]any {
+	return map[string]any{
+		"lossTargetBitrate": 900_000,
+		"averageLoss":       0.25,
+		"inALR":             true,
+		"state":             "increase",
+	}
+}
+
+const (
+	outboundLabels = ",peer_connection_id=pc,ssrc=1,direction=outbound,mime_type=video/VP8"
+	inboundLabels  = ",peer_connection_id=pc,ssrc=2,direction=inbound,mime_type=audio/opus"
+)
+
+func TestExporter(t *testing.T) {
+	t.Run("invalid_interval", func(t *testing.T) {
+		_, err := NewExporter(newMockRegistry(), WithInterval(0))
+		assert.ErrorIs(t, err, errInvalidInterval)
+	})
+
+	t.Run("exports_streams", func(t *testing.T) {
+		registry := newMockRegistry()
+		exporter, err := NewExporter(registry)
+		assert.NoError(t, err)
+		defer func() {
+			assert.NoError(t, exporter.Close())
+		}()
+
+		getter := &mockGetter{
+			streams: []stats.Stream{
+				{SSRC: 1, MimeType: "video/VP8", Direction: stats.DirectionOutbound},
+				{SSRC: 2, MimeType: "audio/opus", Direction: stats.DirectionInbound},
+			},
+			stats: map[uint32]*stats.Stats{1: {}, 2: {}},
+		}
+		getter.stats[1].OutboundRTPStreamStats.PacketsSent = 10
+		getter.stats[1].RemoteInboundRTPStreamStats.RoundTripTime = 50 * time.Millisecond
+		getter.stats[2].InboundRTPStreamStats.PacketsReceived = 20
+		exporter.AddPeerConnection("pc", getter)
+		exporter.Update()
+
+		assert.Equal(t, len(outboundMetrics)+len(inboundMetrics), registry.len())
+		value, _ := registry.get("webrtc_outbound_rtp_packets_sent_total" + outboundLabels)
+		assert.Equal(t, 10.0, value)
+		value, _ = registry.get("webrtc_remote_inbound_rtp_round_trip_time_seconds" + outboundLabels)
+		assert.Equal(t, 0.05, value)
+		value, _ = registry.get("webrtc_inbound_rtp_packets_received_total" + inboundLabels)
+		assert.Equal(t, 20.0, value)
+
+		// Counters are increased by the difference, and not decreased.
+		getter.stats[1].OutboundRTPStreamStats.PacketsSent = 15
+		getter.stats[2].InboundRTPStreamStats.PacketsReceived = 5
+		exporter.Update()
+		value, _ = registry.get("webrtc_outbound_rtp_packets_sent_total" + outboundLabels)
+		assert.Equal(t, 15.0, value)
+		value, _ = registry.get("webrtc_inbound_rtp_packets_received_total" + inboundLabels)
+		assert.Equal(t, 20.0, value)
+
+		// The metrics of unbound streams are unregistered.
+		getter.streams = getter.streams[:1]
+		exporter.Update()
+		assert.Equal(t, len(outboundMetrics), registry.len())
+		_, ok := registry.get("webrtc_inbound_rtp_packets_received_total" + inboundLabels)
+		assert.False(t, ok)
+
+		exporter.RemovePeerConnection("pc")
+		assert.Equal(t, 0, registry.len())
+	})
+
+	t.Run("removes_closed_peer_connection", func(t *testing.T) {
+		registry := newMockRegistry()
+		exporter, err := NewExporter(registry)
+		assert.NoError(t, err)
+		defer func() {
+			assert.NoError(t, exporter.Close())
+		}()
+
+		newGetter := func() *mockClosedGetter {
+			return &mockClosedGetter{
+				mockGetter: mockGetter{
+					streams: []stats.Stream{{SSRC: 1, MimeType: "video/VP8", Direction: stats.DirectionOutbound}},
+					stats:   map[uint32]*stats.Stats{1: {}},
+				},
+				closed: make(chan struct{}),
+			}
+		}
+
+		// Closing a replaced getter keeps the PeerConnection.
+		replaced := newGetter()
+		exporter.AddPeerConnection("pc", replaced)
+		getter := newGetter()
+		exporter.AddPeerConnection("pc", getter)
+		exporter.Update()
+		assert.Equal(t, len(outboundMetrics), registry.len())
+		close(replaced.closed)
+		assert.Never(t, func() bool {
+			return registry.len() == 0
+		}, 50*time.Millisecond, time.Millisecond)
+
+		close(getter.closed)
+		assert.Eventually(t, func() bool {
+			return registry.len() == 0
+		}, time.Second, time.Millisecond)
+		exporter.Update()
+		assert.Equal(t, 0, registry.len())
+	})
+
+	t.Run("exports_bandwidth_estimator", func(t *testing.T) {
+		registry := newMockRegistry()
+		exporter, err := NewExporter(registry)
+		assert.NoError(t, err)
+
+		exporter.AddBandwidthEstimator("pc", mockBandwidthEstimator{})
+		exporter.Update()
+
+		values := map[string]float64{}
+		for _, name := range []string{
+			"webrtc_bwe_target_bitrate_bits_per_second",
+			"webrtc_bwe_loss_target_bitrate",
+			"webrtc_bwe_average_loss",
+			"webrtc_bwe_in_alr",
+		} {
+			value, ok := registry.get(name + ",peer_connection_id=pc")
+			assert.True(t, ok, name)
+			values[name] = value
+		}
+		assert.Equal(t, map[string]float64{
+			"webrtc_bwe_target_bitrate_bits_per_second": 1_000_000,
+			"webrtc_bwe_loss_target_bitrate":            900_000,
+			"webrtc_bwe_average_loss":                   0.25,
+			"webrtc_bwe_in_alr":                         1,
+		}, values)
+		// The state is not a number.
+		assert.Equal(t, 4, registry.len())
+
+		assert.NoError(t, exporter.Close())
+		assert.Equal(t, 0, registry.len())
+
+		// PeerConnections added after Close are not exported.
+		exporter.AddBandwidthEstimator("pc", mockBandwidthEstimator{})
+		exporter.Update()
+		assert.Equal(t, 0, registry.len())
+	})
+
+	t.Run("updates_on_interval", func(t *testing.T) {
+		registry := newMockRegistry()
+		clock := netem.NewVirtualClock(time.Unix(1000, 0))
+		exporter, err := NewExporter(registry, WithInterval(time.Second), WithClock(clock))
+		assert.NoError(t, err)
+		defer func() {
+			assert.NoError(t, exporter.Close())
+		}()
+
+		exporter.AddBandwidthEstimator("pc", mockBandwidthEstimator{})
+		// The ticker is created in the background, advance until it ticked.
+		assert.Eventually(t, func() bool {
+			clock.Advance(time.Second)
+
+			return registry.len() > 0
+		}, time.Second, time.Millisecond)
+	})
+}
+
+func TestBWEMetricName(t *testing.T) {
+	assert.Equal(t, "webrtc_bwe_remb_cap", bweMetricName("rembCap"))
+	assert.Equal(t, "webrtc_bwe_delay_target_bitrate", bweMetricName("delayTargetBitrate"))
+	assert.Equal(t, "webrtc_bwe_in_alr", bweMetricName("inALR"))
+	assert.Equal(t, "webrtc_bwe_alr_send_bitrate", bweMetricName("alrSendBitrate"))
+	assert.Equal(t, "webrtc_bwe_rtt_ms", bweMetricName("RTTMs"))
+}
+
+func TestStreamMetrics(t *testing.T) {
+	// Metric names must be unique.
+	var names []string
+	for _, metric := range slices.Concat(inboundMetrics, outboundMetrics) {
+		assert.NotContains(t, names, metric.desc.Name)
+		names = append(names, metric.desc.Name)
+	}
+}
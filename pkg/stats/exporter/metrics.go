@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pion/interceptor/pkg/stats"
+)
+
+var (
+	streamLabelNames = []string{LabelPeerConnectionID, LabelSSRC, LabelDirection, LabelMimeType}
+	bweLabelNames    = []string{LabelPeerConnectionID}
+)
+
+// streamMetric is a metric exported for every stream of a direction.
+type streamMetric struct {
+	desc  *Desc
+	value func(s *stats.Stats) float64
+}
+
+func counter(name, help string, value func(s *stats.Stats) float64) streamMetric {
+	return streamMetric{
+		desc:  &Desc{Name: name, Help: help, Counter: true, LabelNames: streamLabelNames},
+		value: value,
+	}
+}
+
+func gauge(name, help string, value func(s *stats.Stats) float64) streamMetric {
+	return streamMetric{
+		desc:  &Desc{Name: name, Help: help, LabelNames: streamLabelNames},
+		value: value,
+	}
+}
+
+func seconds(d time.Duration) float64 {
+	return d.Seconds()
+}
+
+func timestamp(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return float64(t.UnixNano()) / float64(time.Second)
+}
+
+// inboundMetrics are exported for the remote streams, from the
+// InboundRTPStreamStats and the RemoteOutboundRTPStreamStats.
+//
+//nolint:lll
+var inboundMetrics = []streamMetric{
+	counter("webrtc_inbound_rtp_packets_received_total", "Number of RTP packets received.", func(s *stats.Stats) float64 {
+		return float64(s.InboundRTPStreamStats.PacketsReceived)
+	}),
+	gauge("webrtc_inbound_rtp_packets_lost", "Number of RTP packets lost.", func(s *stats.Stats) float64 {
+		return float64(s.InboundRTPStreamStats.PacketsLost)
+	}),
+	gauge("webrtc_inbound_rtp_jitter_seconds", "Interarrival jitter of the RTP packets.", func(s *stats.Stats) float64 {
+		return s.InboundRTPStreamStats.Jitter
+	}),
+	gauge("webrtc_inbound_rtp_last_packet_received_timestamp_seconds", "Unix time the last RTP packet was received at.", func(s *stats.Stats) float64 {
+		return timestamp(s.LastPacketReceivedTimestamp)
+	}),
+	counter("webrtc_inbound_rtp_header_bytes_received_total", "Number of RTP header bytes received.", func(s *stats.Stats) float64 {
+		return float64(s.HeaderBytesReceived)
+	}),
+	counter("webrtc_inbound_rtp_bytes_received_total", "Number of RTP bytes received.", func(s *stats.Stats) float64 {
+		return float64(s.BytesReceived)
+	}),
+	counter("webrtc_inbound_rtp_retransmitted_packets_received_total", "Number of retransmitted RTP packets received.", func(s *stats.Stats) float64 {
+		return float64(s.RetransmittedPacketsReceived)
+	}),
+	counter("webrtc_inbound_rtp_retransmitted_bytes_received_total", "Number of retransmitted RTP bytes received.", func(s *stats.Stats) float64 {
+		return float64(s.RetransmittedBytesReceived)
+	}),
+	counter("webrtc_inbound_rtp_fec_packets_received_total", "Number of FEC packets received.", func(s *stats.Stats) float64 {
+		return float64(s.FECPacketsReceived)
+	}),
+	counter("webrtc_inbound_rtp_fec_packets_discarded_total", "Number of FEC packets which recovered no RTP packet.", func(s *stats.Stats) float64 {
+		return float64(s.FECPacketsDiscarded)
+	}),
+	counter("webrtc_inbound_rtp_packets_discarded_total", "Number of RTP packets discarded by the jitter buffer.", func(s *stats.Stats) float64 {
+		return float64(s.PacketsDiscarded)
+	}),
+	counter("webrtc_inbound_rtp_frames_received_total", "Number of video frames received.", func(s *stats.Stats) float64 {
+		return float64(s.FramesReceived)
+	}),
+	counter("webrtc_inbound_rtp_key_frames_decoded_total", "Number of video keyframes received.", func(s *stats.Stats) float64 {
+		return float64(s.KeyFramesDecoded)
+	}),
+	counter("webrtc_inbound_rtp_fir_count_total", "Number of FIR packets sent.", func(s *stats.Stats) float64 {
+		return float64(s.InboundRTPStreamStats.FIRCount)
+	}),
+	counter("webrtc_inbound_rtp_pli_count_total", "Number of PLI packets sent.", func(s *stats.Stats) float64 {
+		return float64(s.InboundRTPStreamStats.PLICount)
+	}),
+	counter("webrtc_inbound_rtp_nack_count_total", "Number of NACK packets sent.", func(s *stats.Stats) float64 {
+		return float64(s.InboundRTPStreamStats.NACKCount)
+	}),
+	gauge("webrtc_remote_outbound_rtp_packets_sent", "Number of RTP packets sent by the remote peer, as of its last sender report.", func(s *stats.Stats) float64 {
+		return float64(s.RemoteOutboundRTPStreamStats.PacketsSent)
+	}),
+	gauge("webrtc_remote_outbound_rtp_bytes_sent", "Number of RTP payload bytes sent by the remote peer, as of its last sender report.", func(s *stats.Stats) float64 {
+		return float64(s.RemoteOutboundRTPStreamStats.BytesSent)
+	}),
+	gauge("webrtc_remote_outbound_rtp_remote_timestamp_seconds", "Unix time of the last sender report of the remote peer, by its clock.", func(s *stats.Stats) float64 {
+		return timestamp(s.RemoteTimeStamp)
+	}),
+	counter("webrtc_remote_outbound_rtp_reports_sent_total", "Number of sender reports received.", func(s *stats.Stats) float64 {
+		return float64(s.ReportsSent)
+	}),
+	gauge("webrtc_remote_outbound_rtp_round_trip_time_seconds", "Last round trip time measured with the extended reports.", func(s *stats.Stats) float64 {
+		return seconds(s.RemoteOutboundRTPStreamStats.RoundTripTime)
+	}),
+	counter("webrtc_remote_outbound_rtp_round_trip_time_seconds_total", "Sum of the round trip times measured with the extended reports.", func(s *stats.Stats) float64 {
+		return seconds(s.RemoteOutboundRTPStreamStats.TotalRoundTripTime)
+	}),
+	counter("webrtc_remote_outbound_rtp_round_trip_time_measurements_total", "Number of round trip times measured with the extended reports.", func(s *stats.Stats) float64 {
+		return float64(s.RemoteOutboundRTPStreamStats.RoundTripTimeMeasurements)
+	}),
+}
+
+// outboundMetrics are exported for the local streams, from the
+// OutboundRTPStreamStats and the RemoteInboundRTPStreamStats.
+//
+//nolint:lll
+var outboundMetrics = []streamMetric{
+	counter("webrtc_outbound_rtp_packets_sent_total", "Number of RTP packets sent, retransmissions included.", func(s *stats.Stats) float64 {
+		return float64(s.OutboundRTPStreamStats.PacketsSent)
+	}),
+	counter("webrtc_outbound_rtp_bytes_sent_total", "Number of RTP bytes sent, retransmissions included.", func(s *stats.Stats) float64 {
+		return float64(s.OutboundRTPStreamStats.BytesSent)
+	}),
+	counter("webrtc_outbound_rtp_header_bytes_sent_total", "Number of RTP header bytes sent.", func(s *stats.Stats) float64 {
+		return float64(s.HeaderBytesSent)
+	}),
+	counter("webrtc_outbound_rtp_retransmitted_packets_sent_total", "Number of retransmitted RTP packets sent.", func(s *stats.Stats) float64 {
+		return float64(s.RetransmittedPacketsSent)
+	}),
+	counter("webrtc_outbound_rtp_retransmitted_bytes_sent_total", "Number of retransmitted RTP bytes sent.", func(s *stats.Stats) float64 {
+		return float64(s.RetransmittedBytesSent)
+	}),
+	counter("webrtc_outbound_rtp_packet_send_delay_seconds_total", "Sum of the times the RTP packets waited in the pacer.", func(s *stats.Stats) float64 {
+		return seconds(s.TotalPacketSendDelay)
+	}),
+	counter("webrtc_outbound_rtp_frames_sent_total", "Number of video frames sent.", func(s *stats.Stats) float64 {
+		return float64(s.FramesSent)
+	}),
+	counter("webrtc_outbound_rtp_nack_count_total", "Number of NACK packets received.", func(s *stats.Stats) float64 {
+		return float64(s.OutboundRTPStreamStats.NACKCount)
+	}),
+	counter("webrtc_outbound_rtp_fir_count_total", "Number of FIR packets received.", func(s *stats.Stats) float64 {
+		return float64(s.OutboundRTPStreamStats.FIRCount)
+	}),
+	counter("webrtc_outbound_rtp_pli_count_total", "Number of PLI packets received.", func(s *stats.Stats) float64 {
+		return float64(s.OutboundRTPStreamStats.PLICount)
+	}),
+	gauge("webrtc_remote_inbound_rtp_packets_received", "Number of RTP packets received by the remote peer, as of its last receiver report.", func(s *stats.Stats) float64 {
+		return float64(s.RemoteInboundRTPStreamStats.PacketsReceived)
+	}),
+	gauge("webrtc_remote_inbound_rtp_packets_lost", "Number of RTP packets lost, as of the last receiver report.", func(s *stats.Stats) float64 {
+		return float64(s.RemoteInboundRTPStreamStats.PacketsLost)
+	}),
+	gauge("webrtc_remote_inbound_rtp_jitter_seconds", "Interarrival jitter measured by the remote peer.", func(s *stats.Stats) float64 {
+		return s.RemoteInboundRTPStreamStats.Jitter
+	}),
+	gauge("webrtc_remote_inbound_rtp_fraction_lost", "Fraction of the RTP packets lost since the previous receiver report.", func(s *stats.Stats) float64 {
+		return s.FractionLost
+	}),
+	gauge("webrtc_remote_inbound_rtp_round_trip_time_seconds", "Last round trip time measured with the receiver reports or the congestion control feedback.", func(s *stats.Stats) float64 {
+		return seconds(s.RemoteInboundRTPStreamStats.RoundTripTime)
+	}),
+	counter("webrtc_remote_inbound_rtp_round_trip_time_seconds_total", "Sum of the round trip times measured.", func(s *stats.Stats) float64 {
+		return seconds(s.RemoteInboundRTPStreamStats.TotalRoundTripTime)
+	}),
+	counter("webrtc_remote_inbound_rtp_round_trip_time_measurements_total", "Number of round trip times measured.", func(s *stats.Stats) float64 {
+		return float64(s.RemoteInboundRTPStreamStats.RoundTripTimeMeasurements)
+	}),
+}
+
+// streamMetrics returns the metrics exported for the streams of direction.
+func streamMetrics(direction stats.Direction) []streamMetric {
+	if direction == stats.DirectionOutbound {
+		return outboundMetrics
+	}
+
+	return inboundMetrics
+}
+
+// bweTargetBitrate is exported for the bandwidth estimator of a PeerConnection.
+var bweTargetBitrate = &Desc{
+	Name:       "webrtc_bwe_target_bitrate_bits_per_second",
+	Help:       "Target bitrate of the bandwidth estimator.",
+	LabelNames: bweLabelNames,
+}
+
+// bweMetricName returns the name of the metric of a value of the GetStats of a
+// bandwidth estimator, with its camel case key in snake case. Acronyms are kept
+// together, inALR is in_alr.
+func bweMetricName(key string) string {
+	runes := []rune(key)
+	var name strings.Builder
+	name.WriteString("webrtc_bwe_")
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 &&
+			(unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			name.WriteByte('_')
+		}
+		name.WriteRune(unicode.ToLower(r))
+	}
+
+	return name.String()
+}
+
+// bweValue converts a value of the GetStats of a bandwidth estimator to the
+// value of a gauge. Booleans are 0 or 1, other values than numbers are not
+// exported.
+func bweValue(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case time.Duration:
+		return v.Seconds(), true
+	case bool:
+		if v {
+			return 1, true
+		}
+
+		return 0, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pion/interceptor"
+)
+
+var errInvalidInterval = errors.New("exporter interval must be positive")
+
+// Option can be used to configure an Exporter.
+type Option func(e *Exporter) error
+
+// WithInterval sets the interval the metrics are updated at. The default is
+// one second.
+func WithInterval(interval time.Duration) Option {
+	return func(e *Exporter) error {
+		if interval <= 0 {
+			return errInvalidInterval
+		}
+		e.interval = interval
+
+		return nil
+	}
+}
+
+// WithClock sets the clock the ticker of the updates is taken from.
+func WithClock(clock interceptor.Clock) Option {
+	return func(e *Exporter) error {
+		e.clock = clock
+
+		return nil
+	}
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+// Names of the labels of the exported metrics.
+const (
+	// LabelPeerConnectionID is the ID the PeerConnection was added with.
+	LabelPeerConnectionID = "peer_connection_id"
+	// LabelSSRC is the SSRC of the stream, in decimal.
+	LabelSSRC = "ssrc"
+	// LabelDirection is "inbound" for the remote streams and "outbound" for the local ones.
+	LabelDirection = "direction"
+	// LabelMimeType is the MIME type of the codec of the stream.
+	LabelMimeType = "mime_type"
+)
+
+// Labels are the label values of a metric, by label name.
+type Labels map[string]string
+
+// Desc describes a metric. The Exporter passes the same *Desc for all the
+// label values of a metric, so that a Registry can use it as a key.
+type Desc struct {
+	// Name is the name of the metric. It follows the Prometheus conventions,
+	// the names of counters end in _total.
+	Name string
+	// Help describes the metric.
+	Help string
+	// Counter is true for metrics which only increase, and are exported with
+	// Counters. The other ones are exported with Gauges.
+	Counter bool
+	// LabelNames are the names of the labels the metric is exported with.
+	LabelNames []string
+}
+
+// Counter is a metric which only increases.
+type Counter interface {
+	// Add increases the counter by delta, which is never negative.
+	Add(delta float64)
+}
+
+// Gauge is a metric which is set to its current value.
+type Gauge interface {
+	// Set sets the gauge to value.
+	Set(value float64)
+}
+
+// Registry creates the metrics of the Exporter in a metrics library, like the
+// CounterVec and GaugeVec of Prometheus or the Float64Counter and Float64Gauge
+// of OpenTelemetry. It is called by a single goroutine at a time.
+type Registry interface {
+	// Counter returns the counter of the metric with the label values.
+	Counter(desc *Desc, labels Labels) Counter
+	// Gauge returns the gauge of the metric with the label values.
+	Gauge(desc *Desc, labels Labels) Gauge
+	// Unregister removes the metric with the label values, once the stream or
+	// the PeerConnection it was exported for is gone.
+	Unregister(desc *Desc, labels Labels)
+}
@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package stats
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// HistoryGetter returns the History of a stream. The Getter passed to the
+// NewPeerConnectionCallback implements it, the History is only recorded with
+// WithHistory.
+type HistoryGetter interface {
+	History(ssrc uint32) *History
+}
+
+// Snapshot is the Stats of a stream at a point in time.
+type Snapshot struct {
+	Timestamp time.Time
+	Stats     Stats
+}
+
+// History holds the last Snapshots of the Stats of a stream, taken on the
+// interval set with WithHistory, oldest first. Its methods compute rates over
+// a window of the given duration, ending at the last Snapshot.
+//
+// The counters of the Stats may start over, when the stats of a remote peer
+// are reset. A counter lower than in the previous Snapshot is taken as having
+// been reset to zero in between.
+type History struct {
+	Direction Direction
+	Snapshots []Snapshot
+}
+
+// historyBuffer is a ring buffer of the Snapshots of a stream.
+type historyBuffer struct {
+	direction Direction
+	snapshots []Snapshot
+	next      int
+	rules     []ruleState
+}
+
+func (b *historyBuffer) add(snapshot Snapshot, size int) {
+	if len(b.snapshots) < size {
+		b.snapshots = append(b.snapshots, snapshot)
+
+		return
+	}
+	b.snapshots[b.next] = snapshot
+	b.next = (b.next + 1) % size
+}
+
+func (b *historyBuffer) history() *History {
+	return &History{
+		Direction: b.direction,
+		Snapshots: slices.Concat(b.snapshots[b.next:], b.snapshots[:b.next]),
+	}
+}
+
+// window returns the Snapshots taken in the window ending at the last one.
+func (h *History) window(window time.Duration) []Snapshot {
+	if len(h.Snapshots) == 0 {
+		return nil
+	}
+	start := h.Snapshots[len(h.Snapshots)-1].Timestamp.Add(-window)
+	i, _ := slices.BinarySearchFunc(h.Snapshots, start, func(s Snapshot, t time.Time) int {
+		return s.Timestamp.Compare(t)
+	})
+
+	return h.Snapshots[i:]
+}
+
+// increase returns how much the counter increased over the snapshots.
+func increase(snapshots []Snapshot, counter func(s *Stats) float64) float64 {
+	total := 0.0
+	for i := 1; i < len(snapshots); i++ {
+		previous, current := counter(&snapshots[i-1].Stats), counter(&snapshots[i].Stats)
+		if current < previous {
+			// The counter was reset.
+			total += current
+		} else {
+			total += current - previous
+		}
+	}
+
+	return total
+}
+
+// rate returns the increase of the counter per second over the window, 0 if
+// it holds less than two Snapshots.
+func (h *History) rate(window time.Duration, counter func(s *Stats) float64) float64 {
+	snapshots := h.window(window)
+	if len(snapshots) < 2 {
+		return 0
+	}
+	duration := snapshots[len(snapshots)-1].Timestamp.Sub(snapshots[0].Timestamp)
+	if duration <= 0 {
+		return 0
+	}
+
+	return increase(snapshots, counter) / duration.Seconds()
+}
+
+// Bitrate returns the bitrate, in bits per second, the stream was sent or
+// received with over the window, headers included.
+func (h *History) Bitrate(window time.Duration) int {
+	return int(h.rate(window, func(s *Stats) float64 {
+		if h.Direction == DirectionOutbound {
+			return float64(s.OutboundRTPStreamStats.BytesSent) * 8
+		}
+
+		return float64(s.BytesReceived) * 8
+	}))
+}
+
+// PacketRate returns the number of packets per second the stream was sent or
+// received with over the window.
+func (h *History) PacketRate(window time.Duration) float64 {
+	return h.rate(window, func(s *Stats) float64 {
+		if h.Direction == DirectionOutbound {
+			return float64(s.OutboundRTPStreamStats.PacketsSent)
+		}
+
+		return float64(s.InboundRTPStreamStats.PacketsReceived)
+	})
+}
+
+// LossFraction returns the fraction of the packets of the stream lost over the
+// window. The losses of the outbound streams are the ones reported by the
+// remote peer in its receiver reports. It returns 0 if no packets were
+// expected.
+func (h *History) LossFraction(window time.Duration) float64 {
+	snapshots := h.window(window)
+	received := func(s *Stats) float64 {
+		if h.Direction == DirectionOutbound {
+			return float64(s.RemoteInboundRTPStreamStats.PacketsReceived)
+		}
+
+		return float64(s.InboundRTPStreamStats.PacketsReceived)
+	}
+	lost := func(s *Stats) float64 {
+		if h.Direction == DirectionOutbound {
+			return float64(s.RemoteInboundRTPStreamStats.PacketsLost)
+		}
+
+		return float64(s.InboundRTPStreamStats.PacketsLost)
+	}
+
+	// Lost packets may be received later, so losses decrease unless the
+	// counters were reset.
+	var lostTotal, receivedTotal float64
+	for i := 1; i < len(snapshots); i++ {
+		previous, current := &snapshots[i-1].Stats, &snapshots[i].Stats
+		if received(current) < received(previous) {
+			lostTotal += lost(current)
+			receivedTotal += received(current)
+		} else {
+			lostTotal += lost(current) - lost(previous)
+			receivedTotal += received(current) - received(previous)
+		}
+	}
+	lostTotal = max(lostTotal, 0)
+	if lostTotal+receivedTotal <= 0 {
+		return 0
+	}
+
+	return lostTotal / (lostTotal + receivedTotal)
+}
+
+// JitterPercentile returns the p-th percentile, 0 < p <= 100, of the jitter of
+// the Snapshots in the window, by the nearest rank method. The jitter of the
+// outbound streams is the one reported by the remote peer.
+func (h *History) JitterPercentile(window time.Duration, p float64) time.Duration {
+	snapshots := h.window(window)
+	if len(snapshots) == 0 {
+		return 0
+	}
+	jitters := make([]float64, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		if h.Direction == DirectionOutbound {
+			jitters = append(jitters, snapshot.Stats.RemoteInboundRTPStreamStats.Jitter)
+		} else {
+			jitters = append(jitters, snapshot.Stats.InboundRTPStreamStats.Jitter)
+		}
+	}
+	slices.Sort(jitters)
+	rank := int(math.Ceil(p / 100 * float64(len(jitters))))
+	rank = min(max(rank, 1), len(jitters))
+
+	return time.Duration(jitters[rank-1] * float64(time.Second))
+}
+
+// RTT returns the minimum, average and maximum of the round trip times
+// measured over the window, or zeros if none was. The minimum and maximum are
+// taken from the last round trip time of the Snapshots after a new one was
+// measured, the average from the sum of all of them.
+func (h *History) RTT(window time.Duration) (minRTT, avgRTT, maxRTT time.Duration) {
+	snapshots := h.window(window)
+	measurements := func(s *Stats) float64 {
+		if h.Direction == DirectionOutbound {
+			return float64(s.RemoteInboundRTPStreamStats.RoundTripTimeMeasurements)
+		}
+
+		return float64(s.RemoteOutboundRTPStreamStats.RoundTripTimeMeasurements)
+	}
+	rtt := func(s *Stats) time.Duration {
+		if h.Direction == DirectionOutbound {
+			return s.RemoteInboundRTPStreamStats.RoundTripTime
+		}
+
+		return s.RemoteOutboundRTPStreamStats.RoundTripTime
+	}
+	totals := func(s *Stats) float64 {
+		if h.Direction == DirectionOutbound {
+			return float64(s.RemoteInboundRTPStreamStats.TotalRoundTripTime)
+		}
+
+		return float64(s.RemoteOutboundRTPStreamStats.TotalRoundTripTime)
+	}
+
+	count := increase(snapshots, measurements)
+	if count == 0 {
+		return 0, 0, 0
+	}
+	minRTT = time.Duration(math.MaxInt64)
+	for i := 1; i < len(snapshots); i++ {
+		if measurements(&snapshots[i].Stats) == measurements(&snapshots[i-1].Stats) {
+			continue
+		}
+		sample := rtt(&snapshots[i].Stats)
+		minRTT = min(minRTT, sample)
+		maxRTT = max(maxRTT, sample)
+	}
+
+	return minRTT, time.Duration(increase(snapshots, totals) / count), maxRTT
+}
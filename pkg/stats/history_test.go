@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func inboundSnapshot(seconds int, packets, bytes uint64, lost int64, jitter float64) Snapshot {
+	snapshot := Snapshot{Timestamp: time.Unix(int64(seconds), 0)}
+	snapshot.Stats.InboundRTPStreamStats.PacketsReceived = packets
+	snapshot.Stats.InboundRTPStreamStats.BytesReceived = bytes
+	snapshot.Stats.InboundRTPStreamStats.PacketsLost = lost
+	snapshot.Stats.InboundRTPStreamStats.Jitter = jitter
+
+	return snapshot
+}
+
+func TestHistoryBuffer(t *testing.T) {
+	buffer := &historyBuffer{direction: DirectionInbound}
+	for i := range 5 {
+		buffer.add(Snapshot{Timestamp: time.Unix(int64(i), 0)}, 3)
+	}
+
+	history := buffer.history()
+	assert.Equal(t, DirectionInbound, history.Direction)
+	assert.Equal(t, []Snapshot{
+		{Timestamp: time.Unix(2, 0)},
+		{Timestamp: time.Unix(3, 0)},
+		{Timestamp: time.Unix(4, 0)},
+	}, history.Snapshots)
+}
+
+func TestHistory(t *testing.T) {
+	history := &History{
+		Direction: DirectionInbound,
+		Snapshots: []Snapshot{
+			inboundSnapshot(0, 0, 0, 0, 0.001),
+			inboundSnapshot(1, 100, 10_000, 0, 0.004),
+			inboundSnapshot(2, 190, 19_000, 10, 0.002),
+			inboundSnapshot(3, 290, 29_000, 10, 0.003),
+			inboundSnapshot(4, 390, 39_000, 10, 0.005),
+		},
+	}
+
+	t.Run("rates", func(t *testing.T) {
+		assert.Equal(t, 80_000, history.Bitrate(2*time.Second))
+		assert.Equal(t, 78_000, history.Bitrate(time.Hour))
+		assert.Equal(t, 100.0, history.PacketRate(time.Second))
+		// A single Snapshot in the window has no rate.
+		assert.Equal(t, 0.0, history.PacketRate(0))
+		assert.Equal(t, 0, (&History{}).Bitrate(time.Second))
+	})
+
+	t.Run("loss fraction", func(t *testing.T) {
+		assert.InDelta(t, 10.0/300, history.LossFraction(3*time.Second), 1e-9)
+		assert.Equal(t, 0.0, history.LossFraction(2*time.Second))
+	})
+
+	t.Run("jitter percentiles", func(t *testing.T) {
+		assert.Equal(t, time.Millisecond, history.JitterPercentile(time.Hour, 0))
+		assert.Equal(t, 3*time.Millisecond, history.JitterPercentile(time.Hour, 50))
+		assert.Equal(t, 4*time.Millisecond, history.JitterPercentile(time.Hour, 80))
+		assert.Equal(t, 5*time.Millisecond, history.JitterPercentile(time.Hour, 100))
+		assert.Equal(t, 5*time.Millisecond, history.JitterPercentile(time.Second, 90))
+		assert.Equal(t, time.Duration(0), (&History{}).JitterPercentile(time.Hour, 50))
+	})
+
+	t.Run("counter reset", func(t *testing.T) {
+		reset := &History{
+			Direction: DirectionInbound,
+			Snapshots: []Snapshot{
+				inboundSnapshot(0, 1000, 100_000, 50, 0),
+				inboundSnapshot(1, 1090, 109_000, 60, 0),
+				// The SSRC changed, the counters start over.
+				inboundSnapshot(2, 100, 10_000, 0, 0),
+			},
+		}
+		assert.Equal(t, 76_000, reset.Bitrate(time.Hour))
+		assert.Equal(t, 95.0, reset.PacketRate(time.Hour))
+		assert.Equal(t, 0.05, reset.LossFraction(time.Hour))
+	})
+
+	t.Run("late packets", func(t *testing.T) {
+		// Packets reported lost may be received later.
+		late := &History{
+			Direction: DirectionInbound,
+			Snapshots: []Snapshot{
+				inboundSnapshot(0, 0, 0, 0, 0),
+				inboundSnapshot(1, 90, 9_000, 10, 0),
+				inboundSnapshot(2, 195, 19_500, 5, 0),
+			},
+		}
+		assert.InDelta(t, 0.025, late.LossFraction(time.Hour), 1e-9)
+		assert.Equal(t, 0.0, late.LossFraction(time.Second))
+	})
+}
+
+func TestHistoryRTT(t *testing.T) {
+	snapshot := func(seconds int, rtt time.Duration, total time.Duration, measurements uint64) Snapshot {
+		s := Snapshot{Timestamp: time.Unix(int64(seconds), 0)}
+		s.Stats.RemoteInboundRTPStreamStats.RoundTripTime = rtt
+		s.Stats.RemoteInboundRTPStreamStats.TotalRoundTripTime = total
+		s.Stats.RemoteInboundRTPStreamStats.RoundTripTimeMeasurements = measurements
+
+		return s
+	}
+	history := &History{
+		Direction: DirectionOutbound,
+		Snapshots: []Snapshot{
+			snapshot(0, 100*time.Millisecond, 100*time.Millisecond, 1),
+			snapshot(1, 40*time.Millisecond, 200*time.Millisecond, 3),
+			// No new measurement.
+			snapshot(2, 40*time.Millisecond, 200*time.Millisecond, 3),
+			snapshot(3, 80*time.Millisecond, 280*time.Millisecond, 4),
+		},
+	}
+
+	minRTT, avgRTT, maxRTT := history.RTT(time.Hour)
+	assert.Equal(t, 40*time.Millisecond, minRTT)
+	assert.Equal(t, 60*time.Millisecond, avgRTT)
+	assert.Equal(t, 80*time.Millisecond, maxRTT)
+
+	minRTT, avgRTT, maxRTT = history.RTT(time.Second)
+	assert.Equal(t, 80*time.Millisecond, minRTT)
+	assert.Equal(t, 80*time.Millisecond, avgRTT)
+	assert.Equal(t, 80*time.Millisecond, maxRTT)
+
+	minRTT, avgRTT, maxRTT = history.RTT(0)
+	assert.Equal(t, time.Duration(0), minRTT)
+	assert.Equal(t, time.Duration(0), avgRTT)
+	assert.Equal(t, time.Duration(0), maxRTT)
+}
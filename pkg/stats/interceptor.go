@@ -5,6 +5,9 @@
 package stats
 
 import (
+	"cmp"
+	"errors"
+	"slices"
 	"sync"
 	"time"
 
@@ -14,6 +17,8 @@ import (
 	"github.com/pion/rtp"
 )
 
+var errInvalidHistory = errors.New("stats history interval and size must be positive")
+
 // Option can be used to configure the stats interceptor.
 type Option func(*Interceptor) error
 
@@ -37,6 +42,31 @@ func SetNowFunc(now func() time.Time) Option {
 	}
 }
 
+// SetClock sets the clock the interceptor gets the current timestamp, and the
+// ticker of the history, from.
+func SetClock(clock interceptor.Clock) Option {
+	return func(i *Interceptor) error {
+		i.now = clock.Now
+		i.clock = clock
+
+		return nil
+	}
+}
+
+// WithHistory keeps a History of the last size Snapshots of the Stats of every
+// bound stream, taken every interval.
+func WithHistory(interval time.Duration, size int) Option {
+	return func(i *Interceptor) error {
+		if interval <= 0 || size <= 0 {
+			return errInvalidHistory
+		}
+		i.historyInterval = interval
+		i.historySize = size
+
+		return nil
+	}
+}
+
 // WithLoggerFactory sets the logger factory for the interceptor.
 func WithLoggerFactory(loggerFactory logging.LoggerFactory) Option {
 	return func(i *Interceptor) error {
@@ -51,6 +81,49 @@ type Getter interface {
 	Get(ssrc uint32) *Stats
 }
 
+// Direction is the direction of an RTP stream, from the point of view of the
+// PeerConnection.
+type Direction int
+
+const (
+	// DirectionInbound is the direction of the remote streams, which are received.
+	DirectionInbound Direction = iota
+	// DirectionOutbound is the direction of the local streams, which are sent.
+	DirectionOutbound
+)
+
+func (d Direction) String() string {
+	switch d {
+	case DirectionInbound:
+		return "inbound"
+	case DirectionOutbound:
+		return "outbound"
+	default:
+		return "unknown"
+	}
+}
+
+// Stream describes an RTP stream the stats are recorded for.
+type Stream struct {
+	SSRC      uint32
+	MimeType  string
+	Direction Direction
+}
+
+// StreamLister lists the streams of a PeerConnection. The Getter passed to the
+// NewPeerConnectionCallback implements it.
+type StreamLister interface {
+	Streams() []Stream
+}
+
+// ClosedNotifier notifies that the stats of a PeerConnection are no longer
+// updated. The Getter passed to the NewPeerConnectionCallback implements it.
+type ClosedNotifier interface {
+	// Closed returns a channel which is closed once the Interceptor of the
+	// PeerConnection is closed.
+	Closed() <-chan struct{}
+}
+
 // NewPeerConnectionCallback receives a new StatsGetter for a newly created
 // PeerConnection.
 type NewPeerConnectionCallback func(string, Getter)
@@ -79,9 +152,14 @@ func (r *InterceptorFactory) OnNewPeerConnection(cb NewPeerConnectionCallback) {
 func (r *InterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor, error) {
 	interceptor := &Interceptor{
 		NoOp:      interceptor.NoOp{},
+		id:        id,
 		now:       time.Now,
 		lock:      sync.Mutex{},
 		recorders: map[uint32]Recorder{},
+		streams:   map[uint32]Stream{},
+		histories: map[uint32]*historyBuffer{},
+		clock:     interceptor.SystemClock(),
+		close:     make(chan struct{}),
 		wg:        sync.WaitGroup{},
 	}
 	for _, opt := range r.opts {
@@ -99,6 +177,15 @@ func (r *InterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor,
 		}
 	}
 
+	if interceptor.eventHandler != nil && interceptor.historySize == 0 {
+		interceptor.historyInterval = defaultEventHistoryInterval
+		interceptor.historySize = defaultEventHistorySize
+	}
+	if interceptor.historySize > 0 {
+		interceptor.wg.Add(1)
+		go interceptor.recordHistory()
+	}
+
 	if r.addPeerConnection != nil {
 		r.addPeerConnection(id, interceptor)
 	}
@@ -123,12 +210,25 @@ type RecorderFactory func(ssrc uint32, clockRate float64) Recorder
 // Interceptor is the interceptor that collects stream stats.
 type Interceptor struct {
 	interceptor.NoOp
+	id              string
 	now             func() time.Time
 	lock            sync.Mutex
 	RecorderFactory RecorderFactory
 	recorders       map[uint32]Recorder
+	streams         map[uint32]Stream
 	wg              sync.WaitGroup
 	loggerFactory   logging.LoggerFactory
+
+	clock           interceptor.Clock
+	historyInterval time.Duration
+	historySize     int
+	histories       map[uint32]*historyBuffer
+	eventHandler    EventHandler
+	rules           []Rule
+	close           chan struct{}
+	// eventLock is held from taking the Events until they are handled, so
+	// that the handler gets them in order.
+	eventLock sync.Mutex
 }
 
 // Get returns the statistics for the stream with ssrc.
@@ -144,37 +244,159 @@ func (r *Interceptor) Get(ssrc uint32) *Stats {
 	return nil
 }
 
-func (r *Interceptor) getRecorder(ssrc uint32, clockRate float64) Recorder {
+// Streams returns the streams which are bound, ordered by SSRC.
+func (r *Interceptor) Streams() []Stream {
 	r.lock.Lock()
 	defer r.lock.Unlock()
-	if rec, ok := r.recorders[ssrc]; ok {
+
+	streams := make([]Stream, 0, len(r.streams))
+	for _, stream := range r.streams {
+		streams = append(streams, stream)
+	}
+	slices.SortFunc(streams, func(a, b Stream) int {
+		return cmp.Compare(a.SSRC, b.SSRC)
+	})
+
+	return streams
+}
+
+// History returns the History of the stream with ssrc, or nil if WithHistory is
+// not set or the stream is not bound.
+func (r *Interceptor) History(ssrc uint32) *History {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if history, ok := r.histories[ssrc]; ok {
+		return history.history()
+	}
+
+	return nil
+}
+
+// recordHistory takes a Snapshot of the bound streams every history interval.
+func (r *Interceptor) recordHistory() {
+	defer r.wg.Done()
+
+	ticker := r.clock.NewTicker(r.historyInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.Ch():
+			r.eventLock.Lock()
+			r.handleEvents(r.takeSnapshots())
+			r.eventLock.Unlock()
+		case <-r.close:
+			return
+		}
+	}
+}
+
+// takeSnapshots takes a Snapshot of the bound streams, and returns the Events
+// of the rules evaluated on their History.
+func (r *Interceptor) takeSnapshots() []Event {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var events []Event
+	now := r.now()
+	for ssrc, stream := range r.streams {
+		rec, ok := r.recorders[ssrc]
+		if !ok {
+			continue
+		}
+		history, ok := r.histories[ssrc]
+		if !ok || history.direction != stream.Direction {
+			if ok {
+				events = append(events, history.resolve(r.rules, ssrc, r.id, now)...)
+			}
+			history = &historyBuffer{direction: stream.Direction}
+			r.histories[ssrc] = history
+		}
+		history.add(Snapshot{Timestamp: now, Stats: rec.GetStats()}, r.historySize)
+		if r.eventHandler != nil {
+			events = append(events, history.evaluate(r.rules, ssrc, r.id)...)
+		}
+	}
+
+	return events
+}
+
+// dropHistory drops the History of the stream with ssrc, and returns the
+// Resolved Events of the rules firing for it.
+func (r *Interceptor) dropHistory(ssrc uint32) []Event {
+	history, ok := r.histories[ssrc]
+	if !ok {
+		return nil
+	}
+	delete(r.histories, ssrc)
+
+	return history.resolve(r.rules, ssrc, r.id, r.now())
+}
+
+// handleEvents passes events to the EventHandler. It is called with eventLock
+// held.
+func (r *Interceptor) handleEvents(events []Event) {
+	for _, event := range events {
+		r.eventHandler(event)
+	}
+}
+
+func (r *Interceptor) getRecorder(info *interceptor.StreamInfo, direction Direction) Recorder {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.streams[info.SSRC] = Stream{SSRC: info.SSRC, MimeType: info.MimeType, Direction: direction}
+	if rec, ok := r.recorders[info.SSRC]; ok {
 		return rec
 	}
-	rec := r.RecorderFactory(ssrc, clockRate)
+	rec := r.RecorderFactory(info.SSRC, float64(info.ClockRate))
+	if rec, ok := rec.(streamInfoRecorder); ok {
+		rec.setStreamInfo(info)
+	}
 	r.wg.Add(1)
 	go func() {
 		defer r.wg.Done()
 		rec.Start()
 	}()
-	r.recorders[ssrc] = rec
+	r.recorders[info.SSRC] = rec
 
 	return rec
 }
 
-// Close closes the interceptor and associated stats recorders.
+// Close closes the interceptor and associated stats recorders. The rules
+// firing for the bound streams are Resolved.
 func (r *Interceptor) Close() error {
 	defer r.wg.Wait()
 
-	r.lock.Lock()
-	defer r.lock.Unlock()
+	r.eventLock.Lock()
+	defer r.eventLock.Unlock()
 
+	r.lock.Lock()
 	for _, r := range r.recorders {
 		r.Stop()
 	}
+	clear(r.streams)
+	var events []Event
+	for ssrc := range r.histories {
+		events = append(events, r.dropHistory(ssrc)...)
+	}
+	select {
+	case <-r.close:
+	default:
+		close(r.close)
+	}
+	r.lock.Unlock()
+
+	r.handleEvents(events)
 
 	return nil
 }
 
+// Closed implements ClosedNotifier.
+func (r *Interceptor) Closed() <-chan struct{} {
+	return r.close
+}
+
 // BindRTCPReader lets you modify any incoming RTCP packets. It is called once per sender/receiver, however this might
 // change in the future. The returned method will be called once per packet batch.
 func (r *Interceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.RTCPReader {
@@ -214,7 +436,7 @@ func (r *Interceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.
 func (r *Interceptor) BindLocalStream(
 	info *interceptor.StreamInfo, writer interceptor.RTPWriter,
 ) interceptor.RTPWriter {
-	recorder := r.getRecorder(info.SSRC, float64(info.ClockRate))
+	recorder := r.getRecorder(info, DirectionOutbound)
 
 	return interceptor.RTPWriterFunc(
 		func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
@@ -230,7 +452,7 @@ func (r *Interceptor) BindLocalStream(
 func (r *Interceptor) BindRemoteStream(
 	info *interceptor.StreamInfo, reader interceptor.RTPReader,
 ) interceptor.RTPReader {
-	recorder := r.getRecorder(info.SSRC, float64(info.ClockRate))
+	recorder := r.getRecorder(info, DirectionInbound)
 
 	return interceptor.RTPReaderFunc(
 		func(bytes []byte, attributes interceptor.Attributes) (int, interceptor.Attributes, error) {
@@ -244,3 +466,30 @@ func (r *Interceptor) BindRemoteStream(
 		},
 	)
 }
+
+// UnbindLocalStream removes the stream from the Streams, and drops its History,
+// resolving the rules firing for it. Its stats can still be retrieved with Get.
+func (r *Interceptor) UnbindLocalStream(info *interceptor.StreamInfo) {
+	r.unbindStream(info.SSRC, DirectionOutbound)
+}
+
+// UnbindRemoteStream removes the stream from the Streams, and drops its History,
+// resolving the rules firing for it. Its stats can still be retrieved with Get.
+func (r *Interceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
+	r.unbindStream(info.SSRC, DirectionInbound)
+}
+
+func (r *Interceptor) unbindStream(ssrc uint32, direction Direction) {
+	r.eventLock.Lock()
+	defer r.eventLock.Unlock()
+
+	r.lock.Lock()
+	var events []Event
+	if stream, ok := r.streams[ssrc]; ok && stream.Direction == direction {
+		delete(r.streams, ssrc)
+		events = r.dropHistory(ssrc)
+	}
+	r.lock.Unlock()
+
+	r.handleEvents(events)
+}
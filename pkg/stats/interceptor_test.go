@@ -4,11 +4,13 @@
 package stats
 
 import (
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/pion/interceptor"
 	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/interceptor/pkg/netem"
 	"github.com/pion/logging"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
@@ -149,6 +151,249 @@ func TestInterceptor(t *testing.T) {
 		}
 		assert.Equal(t, expectedOutgoingRTCP, roRTCP)
 	})
+
+	t.Run("lists streams", func(t *testing.T) {
+		f, err := NewInterceptor()
+		assert.NoError(t, err)
+
+		i, err := f.NewInterceptor("")
+		assert.NoError(t, err)
+		lister, ok := i.(StreamLister)
+		assert.True(t, ok)
+
+		remote := &interceptor.StreamInfo{SSRC: 2, MimeType: "audio/opus"}
+		local := &interceptor.StreamInfo{SSRC: 1, MimeType: "video/VP8"}
+		i.BindRemoteStream(remote, interceptor.RTPReaderFunc(
+			func([]byte, interceptor.Attributes) (int, interceptor.Attributes, error) {
+				return 0, nil, nil
+			},
+		))
+		i.BindLocalStream(local, interceptor.RTPWriterFunc(
+			func(*rtp.Header, []byte, interceptor.Attributes) (int, error) {
+				return 0, nil
+			},
+		))
+		assert.Equal(t, []Stream{
+			{SSRC: 1, MimeType: "video/VP8", Direction: DirectionOutbound},
+			{SSRC: 2, MimeType: "audio/opus", Direction: DirectionInbound},
+		}, lister.Streams())
+
+		// Only the direction the stream was bound in unbinds it.
+		i.UnbindLocalStream(remote)
+		i.UnbindRemoteStream(remote)
+		assert.Equal(t, []Stream{{SSRC: 1, MimeType: "video/VP8", Direction: DirectionOutbound}}, lister.Streams())
+
+		assert.NoError(t, i.Close())
+		assert.Empty(t, lister.Streams())
+	})
+
+	t.Run("notifies close", func(t *testing.T) {
+		f, err := NewInterceptor()
+		assert.NoError(t, err)
+
+		i, err := f.NewInterceptor("")
+		assert.NoError(t, err)
+		notifier, ok := i.(ClosedNotifier)
+		assert.True(t, ok)
+
+		select {
+		case <-notifier.Closed():
+			assert.Fail(t, "closed before Close")
+		default:
+		}
+		assert.NoError(t, i.Close())
+		_, open := <-notifier.Closed()
+		assert.False(t, open)
+	})
+
+	t.Run("counts frames of the stream codec", func(t *testing.T) {
+		var rec *recorder
+		f, err := NewInterceptor(SetRecorderFactory(func(ssrc uint32, clockRate float64) Recorder {
+			rec = newRecorder(ssrc, clockRate, logging.NewDefaultLoggerFactory())
+
+			return rec
+		}))
+		assert.NoError(t, err)
+		var statsGetter Getter
+		f.OnNewPeerConnection(func(_ string, g Getter) {
+			statsGetter = g
+		})
+
+		i, err := f.NewInterceptor("")
+		assert.NoError(t, err)
+
+		stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 1, ClockRate: 90_000, MimeType: "video/VP8"}, i)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+		// The recorder is started in the background and drops the packets until then.
+		assert.Eventually(t, func() bool {
+			return atomic.LoadUint32(&rec.running) == 1
+		}, time.Second, time.Millisecond)
+
+		stream.ReceiveRTP(&rtp.Packet{
+			Header:  rtp.Header{SSRC: 1, SequenceNumber: 1, Timestamp: 3000, Marker: true},
+			Payload: []byte{0x10, 0x00},
+		})
+		stream.ReceiveRTP(&rtp.Packet{
+			Header:  rtp.Header{SSRC: 1, SequenceNumber: 2, Timestamp: 6000, Marker: true},
+			Payload: []byte{0x10, 0x01},
+		})
+		for range 2 {
+			select {
+			case <-stream.ReadRTP():
+			case <-time.After(time.Second):
+				assert.FailNow(t, "expected to read RTP packet")
+			}
+		}
+
+		s := statsGetter.Get(1)
+		assert.Equal(t, uint32(2), s.FramesReceived)
+		assert.Equal(t, uint32(1), s.KeyFramesDecoded)
+	})
+
+	t.Run("invalid history", func(t *testing.T) {
+		f, err := NewInterceptor(WithHistory(0, 10))
+		assert.NoError(t, err)
+		_, err = f.NewInterceptor("")
+		assert.ErrorIs(t, err, errInvalidHistory)
+
+		f, err = NewInterceptor(WithHistory(time.Second, 0))
+		assert.NoError(t, err)
+		_, err = f.NewInterceptor("")
+		assert.ErrorIs(t, err, errInvalidHistory)
+	})
+
+	t.Run("records history", func(t *testing.T) {
+		clock := netem.NewVirtualClock(time.Unix(1000, 0))
+		f, err := NewInterceptor(
+			SetRecorderFactory(func(uint32, float64) Recorder {
+				return newMockRecorder()
+			}),
+			SetClock(clock),
+			WithHistory(time.Second, 3),
+		)
+		assert.NoError(t, err)
+
+		i, err := f.NewInterceptor("")
+		assert.NoError(t, err)
+		historyGetter, ok := i.(HistoryGetter)
+		assert.True(t, ok)
+
+		info := &interceptor.StreamInfo{SSRC: 1, MimeType: "audio/opus"}
+		i.BindLocalStream(info, interceptor.RTPWriterFunc(
+			func(*rtp.Header, []byte, interceptor.Attributes) (int, error) {
+				return 0, nil
+			},
+		))
+		assert.Nil(t, historyGetter.History(2))
+
+		// The ticker is created in the background, advance until the buffer is full.
+		assert.Eventually(t, func() bool {
+			clock.Advance(time.Second)
+			history := historyGetter.History(1)
+
+			return history != nil && len(history.Snapshots) == 3
+		}, time.Second, time.Millisecond)
+		history := historyGetter.History(1)
+		assert.Equal(t, DirectionOutbound, history.Direction)
+		for j := 1; j < len(history.Snapshots); j++ {
+			assert.True(t, history.Snapshots[j-1].Timestamp.Before(history.Snapshots[j].Timestamp))
+		}
+
+		i.UnbindLocalStream(info)
+		assert.Nil(t, historyGetter.History(1))
+		assert.NoError(t, i.Close())
+		assert.NoError(t, i.Close())
+	})
+
+	t.Run("invalid event rule", func(t *testing.T) {
+		f, err := NewInterceptor(WithEventHandler(nil))
+		assert.NoError(t, err)
+		_, err = f.NewInterceptor("")
+		assert.ErrorIs(t, err, errInvalidRule)
+
+		f, err = NewInterceptor(WithEventHandler(func(Event) {}, Rule{Name: "rule"}))
+		assert.NoError(t, err)
+		_, err = f.NewInterceptor("")
+		assert.ErrorIs(t, err, errInvalidRule)
+	})
+
+	t.Run("fires events", func(t *testing.T) {
+		clock := netem.NewVirtualClock(time.Unix(1000, 0))
+		events := make(chan Event, 1)
+		f, err := NewInterceptor(
+			SetRecorderFactory(func(uint32, float64) Recorder {
+				return newMockRecorder()
+			}),
+			SetClock(clock),
+			WithEventHandler(func(event Event) {
+				events <- event
+			}, Rule{
+				Name: "always",
+				Condition: func(*History) (map[string]float64, bool) {
+					return map[string]float64{"value": 1}, true
+				},
+				For: 2 * time.Second,
+			}),
+		)
+		assert.NoError(t, err)
+
+		i, err := f.NewInterceptor("pc")
+		assert.NoError(t, err)
+		info := &interceptor.StreamInfo{SSRC: 1}
+		bind := func() {
+			i.BindLocalStream(info, interceptor.RTPWriterFunc(
+				func(*rtp.Header, []byte, interceptor.Attributes) (int, error) {
+					return 0, nil
+				},
+			))
+		}
+		// The ticker is created in the background, advance until the rule fired.
+		fired := func() Event {
+			var event Event
+			assert.Eventually(t, func() bool {
+				clock.Advance(time.Second)
+				select {
+				case event = <-events:
+					return true
+				default:
+					return false
+				}
+			}, time.Second, time.Millisecond)
+
+			return event
+		}
+
+		bind()
+		event := fired()
+		assert.Equal(t, "always", event.Rule)
+		assert.Equal(t, "pc", event.PeerConnectionID)
+		assert.Equal(t, uint32(1), event.SSRC)
+		assert.Equal(t, DirectionOutbound, event.Direction)
+		assert.Equal(t, map[string]float64{"value": 1}, event.Values)
+		assert.False(t, event.Resolved)
+
+		historyGetter, ok := i.(HistoryGetter)
+		assert.True(t, ok)
+		assert.GreaterOrEqual(t, len(historyGetter.History(1).Snapshots), 3)
+
+		// Unbinding the stream resolves the firing rules.
+		i.UnbindLocalStream(info)
+		event = <-events
+		assert.Equal(t, "always", event.Rule)
+		assert.Equal(t, uint32(1), event.SSRC)
+		assert.Nil(t, event.Values)
+		assert.True(t, event.Resolved)
+
+		// So does closing the Interceptor.
+		bind()
+		assert.False(t, fired().Resolved)
+		assert.NoError(t, i.Close())
+		event = <-events
+		assert.True(t, event.Resolved)
+		assert.Equal(t, clock.Now(), event.Timestamp)
+	})
 }
 
 type recordedOutgoingRTP struct {
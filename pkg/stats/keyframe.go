@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package stats
+
+import (
+	"strings"
+)
+
+// isVideo returns true if mimeType is the one of a video codec.
+func isVideo(mimeType string) bool {
+	return len(mimeType) > len("video/") && strings.EqualFold(mimeType[:len("video/")], "video/")
+}
+
+// isKeyFrame returns true if the RTP payload starts or is part of the start of
+// a keyframe of the codec with the given mime type. Unknown codecs have no
+// keyframes.
+func isKeyFrame(mimeType string, payload []byte) bool {
+	switch strings.ToLower(mimeType) {
+	case "video/vp8":
+		return isVP8KeyFrame(payload)
+	case "video/vp9":
+		return isVP9KeyFrame(payload)
+	case "video/h264":
+		return isH264KeyFrame(payload)
+	case "video/h265":
+		return isH265KeyFrame(payload)
+	case "video/av1":
+		return isAV1KeyFrame(payload)
+	default:
+		return false
+	}
+}
+
+// isVP8KeyFrame parses the payload descriptor of RFC 7741, a keyframe starts in
+// the first partition with the inverse key frame flag of the VP8 header unset.
+//
+//nolint:cyclop
+func isVP8KeyFrame(payload []byte) bool {
+	if len(payload) < 1 {
+		return false
+	}
+	// The S bit must be set and the partition index 0.
+	if payload[0]&0x10 == 0 || payload[0]&0x07 != 0 {
+		return false
+	}
+	offset := 1
+	if payload[0]&0x80 != 0 {
+		if len(payload) < 2 {
+			return false
+		}
+		extension := payload[1]
+		offset++
+		if extension&0x80 != 0 {
+			if len(payload) <= offset {
+				return false
+			}
+			// The picture ID is 15 bits long if its M bit is set.
+			if payload[offset]&0x80 != 0 {
+				offset++
+			}
+			offset++
+		}
+		if extension&0x40 != 0 {
+			offset++
+		}
+		if extension&0x30 != 0 {
+			offset++
+		}
+	}
+	if len(payload) <= offset {
+		return false
+	}
+
+	return payload[offset]&0x01 == 0
+}
+
+// isVP9KeyFrame parses the payload descriptor of RFC 9628, a keyframe starts in
+// a packet beginning a frame which is not inter-picture predicted.
+func isVP9KeyFrame(payload []byte) bool {
+	if len(payload) < 1 {
+		return false
+	}
+
+	return payload[0]&0x08 != 0 && payload[0]&0x40 == 0
+}
+
+// isH264KeyFrame looks for an IDR slice in the single NAL unit, STAP-A and
+// FU-A packets of RFC 6184.
+func isH264KeyFrame(payload []byte) bool {
+	const (
+		naluTypeIDR  = 5
+		naluTypeSTAP = 24
+		naluTypeFU   = 28
+	)
+
+	if len(payload) < 1 {
+		return false
+	}
+	switch payload[0] & 0x1F {
+	case naluTypeIDR:
+		return true
+	case naluTypeSTAP:
+		for offset := 1; offset+2 < len(payload); {
+			size := int(payload[offset])<<8 | int(payload[offset+1])
+			if payload[offset+2]&0x1F == naluTypeIDR {
+				return true
+			}
+			offset += 2 + size
+		}
+	case naluTypeFU:
+		// Only the fragment with the start bit set starts the keyframe.
+		return len(payload) > 1 && payload[1]&0x80 != 0 && payload[1]&0x1F == naluTypeIDR
+	}
+
+	return false
+}
+
+// isH265KeyFrame looks for an IRAP picture in the single NAL unit, aggregation
+// and fragmentation unit packets of RFC 7798.
+func isH265KeyFrame(payload []byte) bool {
+	const (
+		naluTypeAP = 48
+		naluTypeFU = 49
+	)
+	isIRAP := func(naluType byte) bool {
+		return naluType >= 16 && naluType <= 21
+	}
+
+	if len(payload) < 2 {
+		return false
+	}
+	switch naluType := payload[0] >> 1 & 0x3F; {
+	case isIRAP(naluType):
+		return true
+	case naluType == naluTypeAP:
+		for offset := 2; offset+2 < len(payload); {
+			size := int(payload[offset])<<8 | int(payload[offset+1])
+			if isIRAP(payload[offset+2] >> 1 & 0x3F) {
+				return true
+			}
+			offset += 2 + size
+		}
+	case naluType == naluTypeFU:
+		// Only the fragment with the start bit set starts the keyframe.
+		return len(payload) > 2 && payload[2]&0x80 != 0 && isIRAP(payload[2]&0x3F)
+	}
+
+	return false
+}
+
+// isAV1KeyFrame reads the N bit of the aggregation header, which is set on the
+// first packet of a coded video sequence, starting with a keyframe.
+func isAV1KeyFrame(payload []byte) bool {
+	if len(payload) < 1 {
+		return false
+	}
+
+	return payload[0]&0x08 != 0
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsVideo(t *testing.T) {
+	assert.True(t, isVideo("video/VP8"))
+	assert.True(t, isVideo("VIDEO/h264"))
+	assert.False(t, isVideo("audio/opus"))
+	assert.False(t, isVideo("video/"))
+	assert.False(t, isVideo(""))
+}
+
+func TestIsKeyFrame(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		mimeType string
+		payload  []byte
+		keyFrame bool
+	}{
+		{name: "VP8 keyframe", mimeType: "video/VP8", payload: []byte{0x10, 0x00}, keyFrame: true},
+		{name: "VP8 interframe", mimeType: "video/VP8", payload: []byte{0x10, 0x01}},
+		{name: "VP8 continuation", mimeType: "video/VP8", payload: []byte{0x00, 0x00}},
+		{name: "VP8 second partition", mimeType: "video/VP8", payload: []byte{0x11, 0x00}},
+		{
+			name: "VP8 extended picture ID", mimeType: "video/vp8",
+			payload: []byte{0x90, 0x80, 0x81, 0x23, 0x00}, keyFrame: true,
+		},
+		{
+			name: "VP8 all extensions", mimeType: "video/VP8",
+			payload: []byte{0x90, 0xF0, 0x01, 0x02, 0x03, 0x00}, keyFrame: true,
+		},
+		{name: "VP8 truncated", mimeType: "video/VP8", payload: []byte{0x90, 0x80}},
+		{name: "VP8 empty", mimeType: "video/VP8"},
+		{name: "VP9 keyframe", mimeType: "video/VP9", payload: []byte{0x08}, keyFrame: true},
+		{name: "VP9 interframe", mimeType: "video/VP9", payload: []byte{0x48}},
+		{name: "VP9 continuation", mimeType: "video/VP9", payload: []byte{0x00}},
+		{name: "H264 IDR", mimeType: "video/H264", payload: []byte{0x65, 0x88}, keyFrame: true},
+		{name: "H264 non-IDR", mimeType: "video/H264", payload: []byte{0x41, 0x9A}},
+		{
+			name: "H264 STAP-A", mimeType: "video/H264",
+			payload: []byte{0x78, 0x00, 0x02, 0x67, 0x42, 0x00, 0x01, 0x65}, keyFrame: true,
+		},
+		{name: "H264 STAP-A without IDR", mimeType: "video/H264", payload: []byte{0x78, 0x00, 0x02, 0x67, 0x42}},
+		{name: "H264 FU-A start", mimeType: "video/H264", payload: []byte{0x7C, 0x85}, keyFrame: true},
+		{name: "H264 FU-A middle", mimeType: "video/H264", payload: []byte{0x7C, 0x05}},
+		{name: "H265 IDR", mimeType: "video/H265", payload: []byte{0x26, 0x01}, keyFrame: true},
+		{name: "H265 trailing picture", mimeType: "video/H265", payload: []byte{0x02, 0x01}},
+		{
+			name: "H265 AP", mimeType: "video/H265",
+			payload: []byte{0x60, 0x01, 0x00, 0x02, 0x40, 0x01, 0x00, 0x02, 0x26, 0x01}, keyFrame: true,
+		},
+		{name: "H265 FU start", mimeType: "video/H265", payload: []byte{0x62, 0x01, 0x93}, keyFrame: true},
+		{name: "H265 FU middle", mimeType: "video/H265", payload: []byte{0x62, 0x01, 0x13}},
+		{name: "AV1 new sequence", mimeType: "video/AV1", payload: []byte{0x18}, keyFrame: true},
+		{name: "AV1 continuation", mimeType: "video/AV1", payload: []byte{0x10}},
+		{name: "unknown codec", mimeType: "audio/opus", payload: []byte{0xFF}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.keyFrame, isKeyFrame(test.mimeType, test.payload))
+		})
+	}
+}
@@ -25,15 +25,30 @@ func (s ReceivedRTPStreamStats) String() string {
 }
 
 // InboundRTPStreamStats contains stats of inbound RTP streams.
+//
+// Some of the stats are taken from the Attributes set by the interceptors
+// registered before the stats interceptor, which read the packets before it:
+// the retransmissions are the packets repaired by the rtx interceptor, the FEC
+// packets are counted by the flexfec decoder and the discarded packets are the
+// late packets dropped by the jitterbuffer. The frames are counted by the
+// marker bit of the video streams, the keyframes are detected in the payloads
+// of VP8, VP9, H264, H265 and AV1.
 type InboundRTPStreamStats struct {
 	ReceivedRTPStreamStats
 
-	LastPacketReceivedTimestamp time.Time
-	HeaderBytesReceived         uint64
-	BytesReceived               uint64
-	FIRCount                    uint32
-	PLICount                    uint32
-	NACKCount                   uint32
+	LastPacketReceivedTimestamp  time.Time
+	HeaderBytesReceived          uint64
+	BytesReceived                uint64
+	RetransmittedPacketsReceived uint64
+	RetransmittedBytesReceived   uint64
+	FECPacketsReceived           uint64
+	FECPacketsDiscarded          uint64
+	PacketsDiscarded             uint64
+	FramesReceived               uint32
+	KeyFramesDecoded             uint32
+	FIRCount                     uint32
+	PLICount                     uint32
+	NACKCount                    uint32
 }
 
 // String returns a string representation of InboundRTPStreamStats.
@@ -43,6 +58,13 @@ func (s InboundRTPStreamStats) String() string {
 	out += fmt.Sprintf("\tLastPacketReceivedTimestamp: %v\n", s.LastPacketReceivedTimestamp)
 	out += fmt.Sprintf("\tHeaderBytesReceived: %v\n", s.HeaderBytesReceived)
 	out += fmt.Sprintf("\tBytesReceived: %v\n", s.BytesReceived)
+	out += fmt.Sprintf("\tRetransmittedPacketsReceived: %v\n", s.RetransmittedPacketsReceived)
+	out += fmt.Sprintf("\tRetransmittedBytesReceived: %v\n", s.RetransmittedBytesReceived)
+	out += fmt.Sprintf("\tFECPacketsReceived: %v\n", s.FECPacketsReceived)
+	out += fmt.Sprintf("\tFECPacketsDiscarded: %v\n", s.FECPacketsDiscarded)
+	out += fmt.Sprintf("\tPacketsDiscarded: %v\n", s.PacketsDiscarded)
+	out += fmt.Sprintf("\tFramesReceived: %v\n", s.FramesReceived)
+	out += fmt.Sprintf("\tKeyFramesDecoded: %v\n", s.KeyFramesDecoded)
 	out += fmt.Sprintf("\tFIRCount: %v\n", s.FIRCount)
 	out += fmt.Sprintf("\tPLICount: %v\n", s.PLICount)
 	out += fmt.Sprintf("\tNACKCount: %v\n", s.NACKCount)
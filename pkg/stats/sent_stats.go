@@ -23,13 +23,24 @@ func (s SentRTPStreamStats) String() string {
 }
 
 // OutboundRTPStreamStats contains stats of outbound RTP streams.
+//
+// The retransmissions are the packets sent on the RTX stream or resent by the
+// nack responder, and are included in PacketsSent and BytesSent. The send delay
+// is the time the packets waited in the pacing interceptor. Both are only
+// counted if the stats interceptor is registered before those interceptors, so
+// that it writes the packets after them. The frames are counted by the marker
+// bit of the video streams, retransmissions excluded.
 type OutboundRTPStreamStats struct {
 	SentRTPStreamStats
 
-	HeaderBytesSent uint64
-	NACKCount       uint32
-	FIRCount        uint32
-	PLICount        uint32
+	HeaderBytesSent          uint64
+	RetransmittedPacketsSent uint64
+	RetransmittedBytesSent   uint64
+	TotalPacketSendDelay     time.Duration
+	FramesSent               uint32
+	NACKCount                uint32
+	FIRCount                 uint32
+	PLICount                 uint32
 }
 
 // String returns a string representation of OutboundRTPStreamStats.
@@ -37,6 +48,10 @@ func (s OutboundRTPStreamStats) String() string {
 	out := "OutboundRTPStreamStats\n"
 	out += s.SentRTPStreamStats.String()
 	out += fmt.Sprintf("\tHeaderBytesSent: %v\n", s.HeaderBytesSent)
+	out += fmt.Sprintf("\tRetransmittedPacketsSent: %v\n", s.RetransmittedPacketsSent)
+	out += fmt.Sprintf("\tRetransmittedBytesSent: %v\n", s.RetransmittedBytesSent)
+	out += fmt.Sprintf("\tTotalPacketSendDelay: %v\n", s.TotalPacketSendDelay)
+	out += fmt.Sprintf("\tFramesSent: %v\n", s.FramesSent)
 	out += fmt.Sprintf("\tNACKCount: %v\n", s.NACKCount)
 	out += fmt.Sprintf("\tFIRCount: %v\n", s.FIRCount)
 	out += fmt.Sprintf("\tPLICount: %v\n", s.PLICount)
@@ -36,12 +36,23 @@ type internalStats struct {
 	inboundLastArrivalRTP         uint32
 	inboundLastTransit            int
 
+	inboundFrameInitialized    bool
+	inboundLastFrameTimestamp  uint32
+	inboundKeyFrameInitialized bool
+	inboundLastKeyFrame        uint32
+
 	remoteInboundFirstSequenceNumberInitialized bool
 	remoteInboundFirstSequenceNumber            int64
 
 	lastSenderReports []uint64
 
 	lastReceiverReferenceTimes []uint64
+	// receiverReferenceSSRC is the SSRC the Receiver Reference Time report
+	// blocks were last sent with, which RFC 3611 answers DLRR sub-blocks to.
+	receiverReferenceSSRC    uint32
+	hasReceiverReferenceSSRC bool
+
+	sentPackets *interceptor.SentPacketRegistry
 
 	InboundRTPStreamStats
 	OutboundRTPStreamStats
@@ -53,6 +64,7 @@ type internalStats struct {
 type incomingRTP struct {
 	ts         time.Time
 	header     rtp.Header
+	payload    []byte
 	payloadLen int
 	attr       interceptor.Attributes
 }
@@ -81,6 +93,8 @@ type recorder struct {
 
 	ssrc      uint32
 	clockRate float64
+	mimeType  string
+	rtxSSRC   uint32
 
 	maxLastSenderReports          int
 	maxLastReceiverReferenceTimes int
@@ -102,6 +116,20 @@ func newRecorder(ssrc uint32, clockRate float64, loggerFactory logging.LoggerFac
 	}
 }
 
+// streamInfoRecorder is implemented by the Recorders which use the StreamInfo of
+// their stream to find the retransmissions and the frames.
+type streamInfoRecorder interface {
+	setStreamInfo(info *interceptor.StreamInfo)
+}
+
+func (r *recorder) setStreamInfo(info *interceptor.StreamInfo) {
+	r.ms.Lock()
+	defer r.ms.Unlock()
+
+	r.mimeType = info.MimeType
+	r.rtxSSRC = info.SSRCRetransmission
+}
+
 func (r *recorder) Stop() {
 	atomic.StoreUint32(&r.running, 0)
 }
@@ -160,9 +188,60 @@ func (r *recorder) recordIncomingRTP(latestStats internalStats, incoming *incomi
 	latestStats.HeaderBytesReceived += uint64(incoming.header.MarshalSize())                 //nolint:gosec // G115
 	latestStats.BytesReceived += uint64(incoming.header.MarshalSize() + incoming.payloadLen) //nolint:gosec // G115
 
+	return r.recordIncomingAttributes(r.recordIncomingFrame(latestStats, incoming), incoming)
+}
+
+// recordIncomingAttributes records the stats set in the attributes of the
+// packet by the interceptors which read it before.
+func (r *recorder) recordIncomingAttributes(latestStats internalStats, incoming *incomingRTP) internalStats {
+	if retransmitted, _ := incoming.attr.Get(interceptor.RetransmittedAttributesKey).(bool); retransmitted {
+		latestStats.RetransmittedPacketsReceived++
+		//nolint:gosec // G115
+		latestStats.RetransmittedBytesReceived += uint64(incoming.header.MarshalSize() + incoming.payloadLen)
+	}
+	if fec, ok := incoming.attr.Get(interceptor.FECPacketsAttributesKey).(interceptor.FECPackets); ok {
+		latestStats.FECPacketsReceived = fec.Received
+		latestStats.FECPacketsDiscarded = fec.Discarded
+	}
+	if discarded, ok := incoming.attr.Get(interceptor.DiscardedPacketsAttributesKey).(uint64); ok {
+		latestStats.PacketsDiscarded = discarded
+	}
+
+	return latestStats
+}
+
+// recordIncomingFrame counts the frames of video streams by the packets with
+// the marker bit set, and the keyframes by their first packet. Both are only
+// counted when their RTP timestamp is newer than the last one counted, so that
+// duplicates, as well as packets of older frames which are retransmitted or
+// recovered, are ignored.
+func (r *recorder) recordIncomingFrame(latestStats internalStats, incoming *incomingRTP) internalStats {
+	if !isVideo(r.mimeType) {
+		return latestStats
+	}
+	timestamp := incoming.header.Timestamp
+	if incoming.header.Marker &&
+		(!latestStats.inboundFrameInitialized || newerTimestamp(timestamp, latestStats.inboundLastFrameTimestamp)) {
+		latestStats.FramesReceived++
+		latestStats.inboundLastFrameTimestamp = timestamp
+		latestStats.inboundFrameInitialized = true
+	}
+	if isKeyFrame(r.mimeType, incoming.payload) &&
+		(!latestStats.inboundKeyFrameInitialized || newerTimestamp(timestamp, latestStats.inboundLastKeyFrame)) {
+		latestStats.KeyFramesDecoded++
+		latestStats.inboundLastKeyFrame = timestamp
+		latestStats.inboundKeyFrameInitialized = true
+	}
+
 	return latestStats
 }
 
+// newerTimestamp returns whether the RTP timestamp a is newer than b, across
+// wrap arounds.
+func newerTimestamp(a, b uint32) bool {
+	return int32(a-b) > 0 //nolint:gosec // G115
+}
+
 //nolint:cyclop
 func (r *recorder) recordOutgoingRTCP(latestStats internalStats, v *outgoingRTCP) internalStats {
 	for _, pkt := range v.pkts {
@@ -206,6 +285,8 @@ func (r *recorder) recordOutgoingRTCP(latestStats internalStats, v *outgoingRTCP
 		case *rtcp.ExtendedReport:
 			for _, block := range rtcpPkt.Reports {
 				if xr, ok := block.(*rtcp.ReceiverReferenceTimeReportBlock); ok {
+					latestStats.receiverReferenceSSRC = rtcpPkt.SenderSSRC
+					latestStats.hasReceiverReferenceSSRC = true
 					latestStats.lastReceiverReferenceTimes = append(latestStats.lastReceiverReferenceTimes, xr.NTPTimestamp)
 					if len(latestStats.lastReceiverReferenceTimes) > r.maxLastReceiverReferenceTimes {
 						latestStats.lastReceiverReferenceTimes = latestStats.lastReceiverReferenceTimes[len(
@@ -220,18 +301,38 @@ func (r *recorder) recordOutgoingRTCP(latestStats internalStats, v *outgoingRTCP
 	return latestStats
 }
 
+// recordOutgoingRTP records the packets of the stream, and the retransmissions
+// sent for it on its RTX stream.
 func (r *recorder) recordOutgoingRTP(latestStats internalStats, v *outgoingRTP) internalStats {
-	if v.header.SSRC != r.ssrc {
+	onRTXStream := r.rtxSSRC != 0 && v.header.SSRC == r.rtxSSRC
+	if v.header.SSRC != r.ssrc && !onRTXStream {
 		return latestStats
 	}
 	headerSize := v.header.MarshalSize()
 	latestStats.OutboundRTPStreamStats.PacketsSent++
 	latestStats.OutboundRTPStreamStats.BytesSent += uint64(headerSize + v.payloadLen) //nolint:gosec // G115
 	latestStats.HeaderBytesSent += uint64(headerSize)                                 //nolint:gosec // G115
+	retransmitted, _ := v.attr.Get(interceptor.RetransmittedAttributesKey).(bool)
+	if retransmitted || onRTXStream {
+		latestStats.RetransmittedPacketsSent++
+		latestStats.RetransmittedBytesSent += uint64(headerSize + v.payloadLen) //nolint:gosec // G115
+	}
+	if delay, ok := v.attr.Get(interceptor.SendDelayAttributesKey).(time.Duration); ok {
+		latestStats.TotalPacketSendDelay += delay
+	}
+	if onRTXStream {
+		return latestStats
+	}
+	if !retransmitted && v.header.Marker && isVideo(r.mimeType) {
+		latestStats.FramesSent++
+	}
 	if !latestStats.remoteInboundFirstSequenceNumberInitialized {
 		latestStats.remoteInboundFirstSequenceNumber = int64(v.header.SequenceNumber)
 		latestStats.remoteInboundFirstSequenceNumberInitialized = true
 	}
+	if latestStats.sentPackets == nil {
+		latestStats.sentPackets, _ = v.attr.Get(interceptor.SentPacketRegistryAttributesKey).(*interceptor.SentPacketRegistry)
+	}
 
 	return latestStats
 }
@@ -277,11 +378,19 @@ func (r *recorder) recordIncomingRR(
 	return latestStats
 }
 
+// answersReceiverReference returns whether a DLRR sub-block with ssrc answers
+// the Receiver Reference Time report blocks sent for the stream. RFC 3611
+// identifies the sub-block by the SSRC the report blocks were sent with, older
+// pion peers by the SSRC of the stream.
+func (r *recorder) answersReceiverReference(latestStats internalStats, ssrc uint32) bool {
+	return ssrc == r.ssrc || latestStats.hasReceiverReferenceSSRC && ssrc == latestStats.receiverReferenceSSRC
+}
+
 func (r *recorder) recordIncomingXR(latestStats internalStats, pkt *rtcp.ExtendedReport, ts time.Time) internalStats {
 	for _, report := range pkt.Reports {
 		if xr, ok := report.(*rtcp.DLRRReportBlock); ok {
 			for _, xrReport := range xr.Reports {
-				if xrReport.LastRR != 0 && xrReport.DLRR != 0 && xrReport.SSRC == r.ssrc {
+				if xrReport.LastRR != 0 && xrReport.DLRR != 0 && r.answersReceiverReference(latestStats, xrReport.SSRC) {
 					for i := min(r.maxLastReceiverReferenceTimes, len(latestStats.lastReceiverReferenceTimes)) - 1; i >= 0; i-- {
 						lastRR := latestStats.lastReceiverReferenceTimes[i]
 						if (lastRR&0x0000FFFFFFFF0000)>>16 == uint64(xrReport.LastRR) {
@@ -300,6 +409,52 @@ func (r *recorder) recordIncomingXR(latestStats internalStats, pkt *rtcp.Extende
 	return latestStats
 }
 
+// recordIncomingCCFB measures the round trip time with the packets of the stream
+// acknowledged in a RFC 8888 feedback report. The acknowledged packets are looked
+// up in the interceptor.SentPacketRegistry set on the outgoing packets, the round
+// trip time is the time since the departure of a packet minus the time it waited
+// at the receiver until the report was sent. The shortest one of the report is
+// recorded.
+func (r *recorder) recordIncomingCCFB(
+	latestStats internalStats,
+	pkt *rtcp.CCFeedbackReport,
+	ts time.Time,
+) internalStats {
+	if latestStats.sentPackets == nil {
+		return latestStats
+	}
+	var rtt time.Duration
+	measured := false
+	for _, block := range pkt.ReportBlocks {
+		if block.MediaSSRC != r.ssrc {
+			continue
+		}
+		for i, metric := range block.MetricBlocks {
+			// Offsets of 0x1FFE and more are saturated or unavailable.
+			if !metric.Received || metric.ArrivalTimeOffset >= 0x1FFE {
+				continue
+			}
+			sequenceNumber := block.BeginSequence + uint16(i) //nolint:gosec // G115
+			sent, ok := latestStats.sentPackets.GetBySequenceNumber(r.ssrc, sequenceNumber)
+			if !ok {
+				continue
+			}
+			offset := time.Duration(metric.ArrivalTimeOffset) * time.Second / 1024
+			if sample := ts.Sub(sent.Departure) - offset; !measured || sample < rtt {
+				rtt = sample
+				measured = true
+			}
+		}
+	}
+	if measured && rtt >= 0 {
+		latestStats.RemoteInboundRTPStreamStats.RoundTripTime = rtt
+		latestStats.RemoteInboundRTPStreamStats.TotalRoundTripTime += rtt
+		latestStats.RemoteInboundRTPStreamStats.RoundTripTimeMeasurements++
+	}
+
+	return latestStats
+}
+
 func contains(ls []uint32, e uint32) bool {
 	return slices.Contains(ls, e)
 }
@@ -307,6 +462,13 @@ func contains(ls []uint32, e uint32) bool {
 //nolint:cyclop
 func (r *recorder) recordIncomingRTCP(latestStats internalStats, incoming *incomingRTCP) internalStats {
 	for _, pkt := range incoming.pkts {
+		// The DLRR sub-blocks may be identified by the SSRC of the receiver
+		// instead of the one of the stream, recordIncomingXR matches them.
+		if xr, ok := pkt.(*rtcp.ExtendedReport); ok {
+			latestStats = r.recordIncomingXR(latestStats, xr, incoming.ts)
+
+			continue
+		}
 		if !contains(pkt.DestinationSSRC(), r.ssrc) {
 			r.logger.Debugf("skipping incoming RTCP pkt: %v", pkt)
 
@@ -334,8 +496,8 @@ func (r *recorder) recordIncomingRTCP(latestStats internalStats, incoming *incom
 			latestStats.ReportsSent++
 			latestStats = r.recordIncomingRR(latestStats, pkt.Reports, incoming.ts)
 
-		case *rtcp.ExtendedReport:
-			return r.recordIncomingXR(latestStats, pkt, incoming.ts)
+		case *rtcp.CCFeedbackReport:
+			latestStats = r.recordIncomingCCFB(latestStats, pkt, incoming.ts)
 		}
 	}
 
@@ -360,10 +522,12 @@ func (r *recorder) QueueIncomingRTP(ts time.Time, buf []byte, attr interceptor.A
 		return
 	}
 	hdr := header.Clone()
+	headerSize := min(hdr.MarshalSize(), len(buf))
 	r.ms.Lock()
 	*r.latestStats = r.recordIncomingRTP(*r.latestStats, &incomingRTP{
 		ts:         ts,
 		header:     hdr,
+		payload:    buf[headerSize:],
 		payloadLen: len(buf) - hdr.MarshalSize(),
 		attr:       attr,
 	})
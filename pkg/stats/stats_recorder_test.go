@@ -1120,6 +1120,182 @@ func TestStatsRecorder_DLRR_Precision(t *testing.T) {
 	assert.Equal(t, int64(s.RemoteOutboundRTPStreamStats.RoundTripTime), int64(-9223372036854775808))
 }
 
+func TestStatsRecorder_DLRR_ReceiverSSRC(t *testing.T) {
+	recorder := newRecorder(5000, 90_000, logging.NewDefaultLoggerFactory())
+	recorder.Start()
+
+	now := time.Unix(1000, 0)
+	recorder.QueueOutgoingRTCP(now, []rtcp.Packet{&rtcp.ExtendedReport{
+		SenderSSRC: 42,
+		Reports: []rtcp.ReportBlock{
+			&rtcp.ReceiverReferenceTimeReportBlock{NTPTimestamp: ntp.ToNTP(now)},
+		},
+	}}, nil)
+	// The sub-block answering another receiver is ignored.
+	lastRR := uint32(ntp.ToNTP(now) >> 16) //nolint:gosec // G115
+	dlrr := func(ssrc uint32) []byte {
+		buf, err := rtcp.Marshal([]rtcp.Packet{&rtcp.ExtendedReport{Reports: []rtcp.ReportBlock{
+			&rtcp.DLRRReportBlock{Reports: []rtcp.DLRRReport{{SSRC: ssrc, LastRR: lastRR, DLRR: 65536}}},
+		}}})
+		assert.NoError(t, err)
+
+		return buf
+	}
+	recorder.QueueIncomingRTCP(now.Add(2*time.Second), dlrr(43), nil)
+	recorder.QueueIncomingRTCP(now.Add(3*time.Second), dlrr(42), nil)
+
+	s := recorder.GetStats()
+	recorder.Stop()
+
+	assert.Equal(t, 2*time.Second, s.RemoteOutboundRTPStreamStats.RoundTripTime)
+	assert.Equal(t, uint64(1), s.RemoteOutboundRTPStreamStats.RoundTripTimeMeasurements)
+}
+
+func TestStatsRecorder_CCFB_RoundTripTime(t *testing.T) {
+	recorder := newRecorder(5000, 90_000, logging.NewDefaultLoggerFactory())
+	recorder.Start()
+
+	departure := time.Unix(1000, 0)
+	sentPackets := interceptor.NewSentPacketRegistry()
+	attributes := interceptor.Attributes{}
+	attributes.Set(interceptor.SentPacketRegistryAttributesKey, sentPackets)
+	for i := range uint16(3) {
+		sentPackets.Add(interceptor.SentPacket{
+			SSRC:           5000,
+			SequenceNumber: 10 + i,
+			Departure:      departure.Add(time.Duration(i) * 10 * time.Millisecond),
+		})
+		recorder.QueueOutgoingRTP(departure, &rtp.Header{SSRC: 5000, SequenceNumber: 10 + i}, nil, attributes)
+	}
+
+	report := &rtcp.CCFeedbackReport{
+		ReportBlocks: []rtcp.CCFeedbackReportBlock{{
+			MediaSSRC:     5000,
+			BeginSequence: 10,
+			MetricBlocks: []rtcp.CCFeedbackMetricBlock{
+				{Received: true, ArrivalTimeOffset: 1024},
+				{Received: false},
+				{Received: true, ArrivalTimeOffset: 256},
+			},
+		}},
+	}
+	buf, err := report.Marshal()
+	assert.NoError(t, err)
+	recorder.QueueIncomingRTCP(departure.Add(1300*time.Millisecond), buf, nil)
+
+	s := recorder.GetStats()
+	assert.Equal(t, 300*time.Millisecond, s.RemoteInboundRTPStreamStats.RoundTripTime)
+	assert.Equal(t, 300*time.Millisecond, s.RemoteInboundRTPStreamStats.TotalRoundTripTime)
+	assert.Equal(t, uint64(1), s.RemoteInboundRTPStreamStats.RoundTripTimeMeasurements)
+}
+
+func TestStatsRecorder_OutboundAttributes(t *testing.T) {
+	recorder := newRecorder(5000, 90_000, logging.NewDefaultLoggerFactory())
+	recorder.setStreamInfo(&interceptor.StreamInfo{SSRC: 5000, MimeType: "video/VP8", SSRCRetransmission: 5001})
+	recorder.Start()
+
+	now := time.Unix(1000, 0)
+	payload := make([]byte, 100)
+	delayed := func(delay time.Duration) interceptor.Attributes {
+		return interceptor.Attributes{interceptor.SendDelayAttributesKey: delay}
+	}
+	recorder.QueueOutgoingRTP(now, &rtp.Header{SSRC: 5000, SequenceNumber: 1}, payload, delayed(2*time.Millisecond))
+	recorder.QueueOutgoingRTP(
+		now, &rtp.Header{SSRC: 5000, SequenceNumber: 2, Marker: true}, payload, delayed(3*time.Millisecond),
+	)
+	// A retransmission on the media stream and one on the RTX stream.
+	recorder.QueueOutgoingRTP(
+		now, &rtp.Header{SSRC: 5000, SequenceNumber: 2, Marker: true}, payload,
+		interceptor.Attributes{interceptor.RetransmittedAttributesKey: true},
+	)
+	recorder.QueueOutgoingRTP(now, &rtp.Header{SSRC: 5001, SequenceNumber: 7, Marker: true}, make([]byte, 102), nil)
+	recorder.QueueOutgoingRTP(now, &rtp.Header{SSRC: 6000, SequenceNumber: 1, Marker: true}, payload, nil)
+
+	s := recorder.GetStats()
+	assert.Equal(t, uint64(4), s.OutboundRTPStreamStats.PacketsSent)
+	assert.Equal(t, uint64(4*12+3*100+102), s.OutboundRTPStreamStats.BytesSent)
+	assert.Equal(t, uint64(2), s.RetransmittedPacketsSent)
+	assert.Equal(t, uint64(12+100+12+102), s.RetransmittedBytesSent)
+	assert.Equal(t, 5*time.Millisecond, s.TotalPacketSendDelay)
+	assert.Equal(t, uint32(1), s.FramesSent)
+}
+
+func TestStatsRecorder_InboundAttributes(t *testing.T) {
+	recorder := newRecorder(5000, 90_000, logging.NewDefaultLoggerFactory())
+	recorder.setStreamInfo(&interceptor.StreamInfo{SSRC: 5000, MimeType: "video/VP8"})
+	recorder.Start()
+
+	now := time.Unix(1000, 0)
+	receive := func(header rtp.Header, payload []byte, attr interceptor.Attributes) {
+		recorder.QueueIncomingRTP(now, mustMarshalRTP(t, rtp.Packet{Header: header, Payload: payload}), attr)
+	}
+	// A keyframe of two packets with its last packet repaired and duplicated, and an interframe.
+	receive(
+		rtp.Header{SSRC: 5000, SequenceNumber: 1, Timestamp: 3000}, []byte{0x10, 0x00},
+		interceptor.Attributes{interceptor.FECPacketsAttributesKey: interceptor.FECPackets{Received: 1}},
+	)
+	receive(
+		rtp.Header{SSRC: 5000, SequenceNumber: 2, Timestamp: 3000, Marker: true}, []byte{0x00, 0x00},
+		interceptor.Attributes{interceptor.RetransmittedAttributesKey: true},
+	)
+	receive(rtp.Header{SSRC: 5000, SequenceNumber: 2, Timestamp: 3000, Marker: true}, []byte{0x00, 0x00}, nil)
+	receive(
+		rtp.Header{SSRC: 5000, SequenceNumber: 3, Timestamp: 6000, Marker: true}, []byte{0x10, 0x01},
+		interceptor.Attributes{
+			interceptor.FECPacketsAttributesKey:       interceptor.FECPackets{Received: 3, Discarded: 1},
+			interceptor.DiscardedPacketsAttributesKey: uint64(2),
+		},
+	)
+
+	s := recorder.GetStats()
+	assert.Equal(t, uint64(4), s.InboundRTPStreamStats.PacketsReceived)
+	assert.Equal(t, uint64(1), s.RetransmittedPacketsReceived)
+	assert.Equal(t, uint64(12+2), s.RetransmittedBytesReceived)
+	assert.Equal(t, uint64(3), s.FECPacketsReceived)
+	assert.Equal(t, uint64(1), s.FECPacketsDiscarded)
+	assert.Equal(t, uint64(2), s.PacketsDiscarded)
+	assert.Equal(t, uint32(2), s.FramesReceived)
+	assert.Equal(t, uint32(1), s.KeyFramesDecoded)
+}
+
+func TestStatsRecorder_InboundFramesOfOlderPackets(t *testing.T) {
+	recorder := newRecorder(5000, 90_000, logging.NewDefaultLoggerFactory())
+	recorder.setStreamInfo(&interceptor.StreamInfo{SSRC: 5000, MimeType: "video/VP8"})
+	recorder.Start()
+
+	now := time.Unix(1000, 0)
+	receive := func(header rtp.Header, payload []byte, attr interceptor.Attributes) {
+		recorder.QueueIncomingRTP(now, mustMarshalRTP(t, rtp.Packet{Header: header, Payload: payload}), attr)
+	}
+	// A keyframe and an interframe across the RTP timestamp wrap around, then the
+	// keyframe retransmitted after the interframe.
+	receive(rtp.Header{SSRC: 5000, SequenceNumber: 1, Timestamp: 0xFFFFF000, Marker: true}, []byte{0x10, 0x00}, nil)
+	receive(rtp.Header{SSRC: 5000, SequenceNumber: 2, Timestamp: 2000, Marker: true}, []byte{0x10, 0x01}, nil)
+	receive(
+		rtp.Header{SSRC: 5000, SequenceNumber: 1, Timestamp: 0xFFFFF000, Marker: true}, []byte{0x10, 0x00},
+		interceptor.Attributes{interceptor.RetransmittedAttributesKey: true},
+	)
+
+	s := recorder.GetStats()
+	assert.Equal(t, uint32(2), s.FramesReceived)
+	assert.Equal(t, uint32(1), s.KeyFramesDecoded)
+}
+
+func TestStatsRecorder_AudioFrames(t *testing.T) {
+	recorder := newRecorder(5000, 48_000, logging.NewDefaultLoggerFactory())
+	recorder.setStreamInfo(&interceptor.StreamInfo{SSRC: 5000, MimeType: "audio/opus"})
+	recorder.Start()
+
+	// The marker bit of audio streams starts a talkspurt, not a frame.
+	header := &rtp.Header{SSRC: 5000, SequenceNumber: 1, Marker: true}
+	recorder.QueueIncomingRTP(time.Unix(1000, 0), mustMarshalRTP(t, rtp.Packet{Header: *header}), nil)
+	recorder.QueueOutgoingRTP(time.Unix(1000, 0), header, nil, nil)
+
+	s := recorder.GetStats()
+	assert.Equal(t, uint32(0), s.FramesReceived)
+	assert.Equal(t, uint32(0), s.FramesSent)
+}
+
 func TestGetStatsNotBlocking(t *testing.T) {
 	r := newRecorder(0, 90_000, logging.NewDefaultLoggerFactory())
 
@@ -5,7 +5,7 @@ package twcc
 
 import (
 	"errors"
-	"sync/atomic"
+	"sync"
 
 	"github.com/pion/interceptor"
 	"github.com/pion/rtp"
@@ -13,23 +13,53 @@ import (
 
 var errHeaderIsNil = errors.New("header is nil")
 
+// HeaderExtensionOption can be used to configure a HeaderExtensionInterceptor.
+type HeaderExtensionOption func(*HeaderExtensionInterceptor) error
+
+// HeaderExtensionClock sets the clock the departure times of the packets are taken from.
+func HeaderExtensionClock(clock interceptor.Clock) HeaderExtensionOption {
+	return func(h *HeaderExtensionInterceptor) error {
+		h.clock = clock
+
+		return nil
+	}
+}
+
 // HeaderExtensionInterceptorFactory is a interceptor.Factory for a HeaderExtensionInterceptor.
-type HeaderExtensionInterceptorFactory struct{}
+type HeaderExtensionInterceptorFactory struct {
+	opts []HeaderExtensionOption
+}
 
 // NewInterceptor constructs a new HeaderExtensionInterceptor.
 func (h *HeaderExtensionInterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
-	return &HeaderExtensionInterceptor{}, nil
+	headerExtensionInterceptor := &HeaderExtensionInterceptor{
+		clock:    interceptor.SystemClock(),
+		registry: interceptor.NewSentPacketRegistry(),
+	}
+	for _, opt := range h.opts {
+		if err := opt(headerExtensionInterceptor); err != nil {
+			return nil, err
+		}
+	}
+
+	return headerExtensionInterceptor, nil
 }
 
 // NewHeaderExtensionInterceptor returns a HeaderExtensionInterceptorFactory.
-func NewHeaderExtensionInterceptor() (*HeaderExtensionInterceptorFactory, error) {
-	return &HeaderExtensionInterceptorFactory{}, nil
+func NewHeaderExtensionInterceptor(opts ...HeaderExtensionOption) (*HeaderExtensionInterceptorFactory, error) {
+	return &HeaderExtensionInterceptorFactory{opts: opts}, nil
 }
 
 // HeaderExtensionInterceptor adds transport wide sequence numbers as header extension to each RTP packet.
+// The packets are recorded in an interceptor.SentPacketRegistry, which is set in
+// their attributes with the interceptor.SentPacketRegistryAttributesKey. The
+// interceptors consuming feedback later in the chain, such as the rtpfb and stats
+// interceptors, map the acknowledgments to the packets with it.
 type HeaderExtensionInterceptor struct {
 	interceptor.NoOp
-	nextSequenceNr uint32
+	clock    interceptor.Clock
+	registry *interceptor.SentPacketRegistry
+	init     sync.Once
 }
 
 const transportCCURI = "http://www.ietf.org/id/draft-holmer-rmcat-transport-wide-cc-extensions-01"
@@ -40,6 +70,16 @@ func (h *HeaderExtensionInterceptor) BindLocalStream(
 	info *interceptor.StreamInfo,
 	writer interceptor.RTPWriter,
 ) interceptor.RTPWriter {
+	h.init.Do(func() {
+		// The zero value of the HeaderExtensionInterceptor is usable as well.
+		if h.clock == nil {
+			h.clock = interceptor.SystemClock()
+		}
+		if h.registry == nil {
+			h.registry = interceptor.NewSentPacketRegistry()
+		}
+	})
+
 	var hdrExtID uint8
 	for _, e := range info.RTPHeaderExtensions {
 		if e.URI == transportCCURI {
@@ -48,25 +88,37 @@ func (h *HeaderExtensionInterceptor) BindLocalStream(
 			break
 		}
 	}
-	if hdrExtID == 0 { // Don't add header extension if ID is 0, because 0 is an invalid extension ID
-		return writer
-	}
 
 	return interceptor.RTPWriterFunc(
 		func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
-			sequenceNumber := atomic.AddUint32(&h.nextSequenceNr, 1) - 1
-			//nolint:gosec // G115
-			tcc, err := (&rtp.TransportCCExtension{TransportSequence: uint16(sequenceNumber)}).Marshal()
-			if err != nil {
-				return 0, err
-			}
 			if header == nil {
 				return 0, errHeaderIsNil
 			}
-			err = header.SetExtension(hdrExtID, tcc)
-			if err != nil {
-				return 0, err
+			packet := interceptor.SentPacket{
+				SSRC:           header.SSRC,
+				SequenceNumber: header.SequenceNumber,
+			}
+			// Don't add header extension if ID is 0, because 0 is an invalid extension ID
+			if hdrExtID != 0 {
+				sequenceNumber := h.registry.NextTransportSequenceNumber()
+				tcc, err := (&rtp.TransportCCExtension{TransportSequence: sequenceNumber}).Marshal()
+				if err != nil {
+					return 0, err
+				}
+				if err = header.SetExtension(hdrExtID, tcc); err != nil {
+					return 0, err
+				}
+				packet.TransportWide = true
+				packet.TransportSequenceNumber = sequenceNumber
+			}
+			packet.Size = header.MarshalSize() + len(payload)
+			packet.Departure = h.clock.Now()
+			h.registry.Add(packet)
+
+			if attributes == nil {
+				attributes = make(interceptor.Attributes)
 			}
+			attributes.Set(interceptor.SentPacketRegistryAttributesKey, h.registry)
 
 			return writer.Write(header, payload, attributes)
 		},
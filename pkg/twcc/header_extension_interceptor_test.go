@@ -87,4 +87,55 @@ func TestHeaderExtensionInterceptor(t *testing.T) {
 			assert.NoError(t, err)
 		}
 	})
+	t.Run("records packets in the registry", func(t *testing.T) {
+		departure := time.Unix(1000, 0)
+		factory, err := NewHeaderExtensionInterceptor(HeaderExtensionClock(staticClock{departure}))
+		assert.NoError(t, err)
+
+		inter, err := factory.NewInterceptor("")
+		assert.NoError(t, err)
+
+		var attributes interceptor.Attributes
+		writer := inter.BindLocalStream(&interceptor.StreamInfo{RTPHeaderExtensions: []interceptor.RTPHeaderExtension{
+			{
+				URI: transportCCURI,
+				ID:  1,
+			},
+		}}, interceptor.RTPWriterFunc(func(_ *rtp.Header, _ []byte, a interceptor.Attributes) (int, error) {
+			attributes = a
+
+			return 0, nil
+		}))
+
+		for _, seqNum := range []uint16{7, 8} {
+			_, err = writer.Write(&rtp.Header{SSRC: 1, SequenceNumber: seqNum}, make([]byte, 100), nil)
+			assert.NoError(t, err)
+		}
+
+		registry, ok := attributes.Get(interceptor.SentPacketRegistryAttributesKey).(*interceptor.SentPacketRegistry)
+		assert.True(t, ok)
+		packet, ok := registry.GetByTransportSequenceNumber(1)
+		assert.True(t, ok)
+		assert.Equal(t, interceptor.SentPacket{
+			Index:                   1,
+			SSRC:                    1,
+			SequenceNumber:          8,
+			TransportWide:           true,
+			TransportSequenceNumber: 1,
+			Size:                    120,
+			Departure:               departure,
+		}, packet)
+	})
+}
+
+type staticClock struct {
+	now time.Time
+}
+
+func (c staticClock) Now() time.Time {
+	return c.now
+}
+
+func (c staticClock) NewTicker(d time.Duration) interceptor.Ticker {
+	return interceptor.SystemClock().NewTicker(d)
 }
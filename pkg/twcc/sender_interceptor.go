@@ -27,7 +27,7 @@ func (s *SenderInterceptorFactory) NewInterceptor(_ string) (interceptor.Interce
 		packetChan: make(chan packet),
 		close:      make(chan struct{}),
 		interval:   100 * time.Millisecond,
-		startTime:  time.Now(),
+		clock:      interceptor.SystemClock(),
 	}
 
 	for _, opt := range s.opts {
@@ -37,6 +37,8 @@ func (s *SenderInterceptorFactory) NewInterceptor(_ string) (interceptor.Interce
 		}
 	}
 
+	senderInterceptor.startTime = senderInterceptor.clock.Now()
+
 	if senderInterceptor.loggerFactory == nil {
 		senderInterceptor.loggerFactory = logging.NewDefaultLoggerFactory()
 	}
@@ -65,6 +67,7 @@ type SenderInterceptor struct {
 	close chan struct{}
 
 	interval  time.Duration
+	clock     interceptor.Clock
 	startTime time.Time
 
 	recorder   *Recorder
@@ -84,6 +87,16 @@ func SendInterval(interval time.Duration) Option {
 	}
 }
 
+// SendClock sets the clock the arrival times are taken from and the feedback
+// reports are sent by.
+func SendClock(clock interceptor.Clock) Option {
+	return func(s *SenderInterceptor) error {
+		s.clock = clock
+
+		return nil
+	}
+}
+
 // WithLoggerFactory sets the logger factory for the interceptor.
 func WithLoggerFactory(loggerFactory logging.LoggerFactory) Option {
 	return func(s *SenderInterceptor) error {
@@ -163,7 +176,7 @@ func (s *SenderInterceptor) BindRemoteStream(
 				p := packet{
 					hdr:            header,
 					sequenceNumber: tccExt.TransportSequence,
-					arrivalTime:    time.Since(s.startTime).Microseconds(),
+					arrivalTime:    s.clock.Now().Sub(s.startTime).Microseconds(),
 					ssrc:           info.SSRC,
 				}
 				select {
@@ -210,7 +223,7 @@ func (s *SenderInterceptor) loop(writer interceptor.RTCPWriter) {
 		s.recorder.Record(p.ssrc, p.sequenceNumber, p.arrivalTime)
 	}
 
-	ticker := time.NewTicker(s.interval)
+	ticker := s.clock.NewTicker(s.interval)
 	for {
 		select {
 		case <-s.close:
@@ -220,7 +233,7 @@ func (s *SenderInterceptor) loop(writer interceptor.RTCPWriter) {
 		case p := <-s.packetChan:
 			s.recorder.Record(p.ssrc, p.sequenceNumber, p.arrivalTime)
 
-		case <-ticker.C:
+		case <-ticker.Ch():
 			// build and send twcc
 			pkts := s.recorder.BuildFeedbackPacket()
 			if len(pkts) == 0 {
@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sentPacketRegistrySize is the number of packets a SentPacketRegistry keeps.
+const sentPacketRegistrySize = 1 << 13
+
+type sentPacketRegistryKeyType int
+
+// SentPacketRegistryAttributesKey is set on outgoing RTP packets by the
+// interceptor assigning the transport wide sequence numbers. Its value is the
+// *SentPacketRegistry the packet was recorded in, so that the interceptors
+// consuming feedback later in the chain look the acknowledged packets up in the
+// registry instead of keeping a history of their own.
+const SentPacketRegistryAttributesKey sentPacketRegistryKeyType = iota
+
+// SentPacket is an outgoing RTP packet recorded in a SentPacketRegistry.
+type SentPacket struct {
+	// Index is the position of the packet in the registry. It increases by one
+	// for every recorded packet, independent of the stream it is sent on.
+	Index uint64
+	// SSRC is the SSRC of the stream the packet is sent on.
+	SSRC uint32
+	// SequenceNumber is the sequence number of the RTP header.
+	SequenceNumber uint16
+	// TransportWide is true if the packet carries a transport wide sequence number.
+	TransportWide bool
+	// TransportSequenceNumber is the transport wide sequence number of the packet.
+	TransportSequenceNumber uint16
+	// Size is the size of the packet in bytes.
+	Size int
+	// Departure is the time the packet was sent at.
+	Departure time.Time
+	// ProbeClusterID is the ID of the probe cluster the packet was sent in, 0 if none.
+	ProbeClusterID int
+}
+
+type ssrcSequenceNumber struct {
+	ssrc           uint32
+	sequenceNumber uint16
+}
+
+// SentPacketRegistry numbers the outgoing RTP packets of a PeerConnection with
+// transport wide sequence numbers, and records the packets sent so that the
+// consumers of TWCC and RFC 8888 feedback map the acknowledgments back to them.
+// The packets are found by transport wide sequence number, or by SSRC and RTP
+// sequence number. The registry keeps the last 8192 packets recorded.
+type SentPacketRegistry struct {
+	nextTransportSequenceNumber atomic.Uint32
+
+	lock                      sync.RWMutex
+	packets                   []SentPacket
+	next                      uint64
+	byTransportSequenceNumber map[uint16]uint64
+	bySequenceNumber          map[ssrcSequenceNumber]uint64
+}
+
+// NewSentPacketRegistry returns a new, empty SentPacketRegistry.
+func NewSentPacketRegistry() *SentPacketRegistry {
+	return &SentPacketRegistry{
+		byTransportSequenceNumber: map[uint16]uint64{},
+		bySequenceNumber:          map[ssrcSequenceNumber]uint64{},
+	}
+}
+
+// NextTransportSequenceNumber returns the transport wide sequence number of the
+// next packet. Every call returns a new number.
+func (r *SentPacketRegistry) NextTransportSequenceNumber() uint16 {
+	return uint16(r.nextTransportSequenceNumber.Add(1) - 1) //nolint:gosec // G115
+}
+
+// Add records an outgoing packet, and returns its Index. The Index of packet is ignored.
+func (r *SentPacketRegistry) Add(packet SentPacket) uint64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	packet.Index = r.next
+	r.next++
+	if len(r.packets) < sentPacketRegistrySize {
+		r.packets = append(r.packets, packet)
+	} else {
+		slot := &r.packets[packet.Index%sentPacketRegistrySize]
+		r.forget(*slot)
+		*slot = packet
+	}
+	if packet.TransportWide {
+		r.byTransportSequenceNumber[packet.TransportSequenceNumber] = packet.Index
+	}
+	r.bySequenceNumber[ssrcSequenceNumber{ssrc: packet.SSRC, sequenceNumber: packet.SequenceNumber}] = packet.Index
+
+	return packet.Index
+}
+
+// Update replaces the packet recorded at the Index of packet, and reports if it
+// was still in the registry. It is used to set the departure of a packet
+// recorded before it was paced.
+func (r *SentPacketRegistry) Update(packet SentPacket) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	old, ok := r.get(packet.Index)
+	if !ok {
+		return false
+	}
+	r.forget(old)
+	r.packets[packet.Index%sentPacketRegistrySize] = packet
+	if packet.TransportWide {
+		r.byTransportSequenceNumber[packet.TransportSequenceNumber] = packet.Index
+	}
+	r.bySequenceNumber[ssrcSequenceNumber{ssrc: packet.SSRC, sequenceNumber: packet.SequenceNumber}] = packet.Index
+
+	return true
+}
+
+// forget removes the lookups of an evicted packet, unless a newer packet took
+// them over. It must be called with the lock held.
+func (r *SentPacketRegistry) forget(packet SentPacket) {
+	if index, ok := r.byTransportSequenceNumber[packet.TransportSequenceNumber]; ok && index == packet.Index {
+		delete(r.byTransportSequenceNumber, packet.TransportSequenceNumber)
+	}
+	key := ssrcSequenceNumber{ssrc: packet.SSRC, sequenceNumber: packet.SequenceNumber}
+	if index, ok := r.bySequenceNumber[key]; ok && index == packet.Index {
+		delete(r.bySequenceNumber, key)
+	}
+}
+
+// Get returns the packet recorded at index, if it is still in the registry.
+func (r *SentPacketRegistry) Get(index uint64) (SentPacket, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.get(index)
+}
+
+// GetByTransportSequenceNumber returns the last packet recorded with the
+// transport wide sequence number, if it is still in the registry.
+func (r *SentPacketRegistry) GetByTransportSequenceNumber(sequenceNumber uint16) (SentPacket, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	index, ok := r.byTransportSequenceNumber[sequenceNumber]
+	if !ok {
+		return SentPacket{}, false
+	}
+
+	return r.get(index)
+}
+
+// GetBySequenceNumber returns the last packet recorded for the SSRC with the RTP
+// sequence number, if it is still in the registry.
+func (r *SentPacketRegistry) GetBySequenceNumber(ssrc uint32, sequenceNumber uint16) (SentPacket, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	index, ok := r.bySequenceNumber[ssrcSequenceNumber{ssrc: ssrc, sequenceNumber: sequenceNumber}]
+	if !ok {
+		return SentPacket{}, false
+	}
+
+	return r.get(index)
+}
+
+// NextIndex returns the Index the next packet recorded gets.
+func (r *SentPacketRegistry) NextIndex() uint64 {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.next
+}
+
+// get must be called with the lock held.
+func (r *SentPacketRegistry) get(index uint64) (SentPacket, bool) {
+	if index >= r.next || r.next-index > sentPacketRegistrySize {
+		return SentPacket{}, false
+	}
+
+	return r.packets[index%sentPacketRegistrySize], true
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSentPacketRegistry(t *testing.T) {
+	t.Run("Lookup", func(t *testing.T) {
+		registry := NewSentPacketRegistry()
+		assert.Equal(t, uint16(0), registry.NextTransportSequenceNumber())
+		assert.Equal(t, uint16(1), registry.NextTransportSequenceNumber())
+
+		departure := time.Unix(1000, 0)
+		index := registry.Add(SentPacket{
+			SSRC: 1, SequenceNumber: 10, TransportWide: true, TransportSequenceNumber: 1, Size: 1200, Departure: departure,
+		})
+		assert.Equal(t, uint64(0), index)
+		assert.Equal(t, uint64(1), registry.Add(SentPacket{SSRC: 2, SequenceNumber: 10}))
+		assert.Equal(t, uint64(2), registry.NextIndex())
+
+		packet, ok := registry.GetByTransportSequenceNumber(1)
+		assert.True(t, ok)
+		assert.Equal(t, SentPacket{
+			SSRC: 1, SequenceNumber: 10, TransportWide: true, TransportSequenceNumber: 1, Size: 1200, Departure: departure,
+		}, packet)
+
+		packet, ok = registry.GetBySequenceNumber(2, 10)
+		assert.True(t, ok)
+		assert.Equal(t, uint64(1), packet.Index)
+		_, ok = registry.GetByTransportSequenceNumber(0)
+		assert.False(t, ok)
+		_, ok = registry.GetBySequenceNumber(3, 10)
+		assert.False(t, ok)
+
+		packet, ok = registry.Get(0)
+		assert.True(t, ok)
+		assert.Equal(t, uint32(1), packet.SSRC)
+		_, ok = registry.Get(2)
+		assert.False(t, ok)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		registry := NewSentPacketRegistry()
+		index := registry.Add(SentPacket{SSRC: 1, SequenceNumber: 10, TransportWide: true, TransportSequenceNumber: 5})
+
+		departure := time.Unix(1000, 0)
+		assert.True(t, registry.Update(SentPacket{
+			Index: index, SSRC: 1, SequenceNumber: 11, TransportWide: true, TransportSequenceNumber: 5, Departure: departure,
+		}))
+		packet, ok := registry.GetByTransportSequenceNumber(5)
+		assert.True(t, ok)
+		assert.Equal(t, departure, packet.Departure)
+		_, ok = registry.GetBySequenceNumber(1, 10)
+		assert.False(t, ok)
+		_, ok = registry.GetBySequenceNumber(1, 11)
+		assert.True(t, ok)
+
+		assert.False(t, registry.Update(SentPacket{Index: 1}))
+	})
+
+	t.Run("Eviction", func(t *testing.T) {
+		registry := NewSentPacketRegistry()
+		for i := range sentPacketRegistrySize + 10 {
+			registry.Add(SentPacket{
+				SSRC:                    1,
+				SequenceNumber:          uint16(i), //nolint:gosec // G115
+				TransportWide:           true,
+				TransportSequenceNumber: registry.NextTransportSequenceNumber(),
+			})
+		}
+
+		_, ok := registry.Get(9)
+		assert.False(t, ok)
+		_, ok = registry.GetByTransportSequenceNumber(9)
+		assert.False(t, ok)
+		_, ok = registry.GetBySequenceNumber(1, 9)
+		assert.False(t, ok)
+
+		packet, ok := registry.GetByTransportSequenceNumber(10)
+		assert.True(t, ok)
+		assert.Equal(t, uint64(10), packet.Index)
+		packet, ok = registry.Get(sentPacketRegistrySize + 9)
+		assert.True(t, ok)
+		assert.Equal(t, uint16(sentPacketRegistrySize+9), packet.SequenceNumber)
+	})
+}